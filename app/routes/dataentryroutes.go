@@ -5,6 +5,7 @@ import (
 	"RAAS/core/middlewares"
 
 	"RAAS/internal/handlers/features/appuser"
+	"RAAS/internal/handlers/features/settings"
 	"RAAS/internal/handlers/preference"
 
 
@@ -21,6 +22,20 @@ func SetupDataEntryRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Confi
 
 	// Define the route for getting the next entry step
 	timeline.GET("", appuser.GetNextEntryStep())
+	r.POST("/b1/user/entry-progress/steps/:step/complete", middleware.AuthMiddleware(), appuser.CompleteEntryStep())
+
+	// ONBOARDING FLOW definitions (admin)
+	onboardingFlowHandler := settings.NewOnboardingFlowHandler()
+	r.Group("/b1/admin/onboarding-flows", middleware.AuthMiddleware(), middleware.RequireRole("admin")).
+		GET("", onboardingFlowHandler.ListFlows).
+		PUT("/:key", onboardingFlowHandler.UpsertFlow)
+
+	// SKILLS taxonomy (admin)
+	skillTaxonomyHandler := settings.NewSkillTaxonomyHandler()
+	r.Group("/b1/admin/skills", middleware.AuthMiddleware(), middleware.RequireRole("admin")).
+		GET("", skillTaxonomyHandler.ListSkills).
+		PUT("/:id", skillTaxonomyHandler.UpsertSkill).
+		DELETE("/:id", skillTaxonomyHandler.DeleteSkill)
 
 	// PERSONAL INFO routes
 	personalInfoHandler := preference.NewPersonalInfoHandler()
@@ -112,6 +127,7 @@ func SetupDataEntryRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Confi
 	{
 		keySkillsRoutes.GET("", keySkillsHandler.GetKeySkills)
 		keySkillsRoutes.POST("", keySkillsHandler.SetKeySkills)
+		keySkillsRoutes.GET("/suggest", keySkillsHandler.SuggestSkills)
 	}
 	
 	//CVNCL FORMAT routes
@@ -130,7 +146,9 @@ func SetupDataEntryRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Confi
 	dataExtractionRoutes := r.Group("/b1/data-extraction")
 	dataExtractionRoutes.Use(middleware.AuthMiddleware())
 	{
-		dataExtractionRoutes.POST("/resume", dataExtractionHandler.ExtractFromPDF)
+		dataExtractionRoutes.POST("/resume", dataExtractionHandler.ExtractResume)
+		dataExtractionRoutes.GET("/resume/:import_id", dataExtractionHandler.GetResumeImport)
+		dataExtractionRoutes.POST("/resume/:import_id/apply", dataExtractionHandler.ApplyResumeImport)
 	}
 
 
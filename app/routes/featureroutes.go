@@ -5,6 +5,7 @@ import (
     "RAAS/core/middlewares"
     // "RAAS/internal/handlers/features/generation"
     "RAAS/internal/handlers"
+    "RAAS/internal/handlers/features/alerts"
     "RAAS/internal/handlers/features/appuser"
     "RAAS/internal/handlers/features/generation"
     "RAAS/internal/handlers/features/jobs"
@@ -26,12 +27,16 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     // === USER ===
     seekerHandler := settings.NewSeekerHandler()
     r.Group("/b1/jobprofile", auth).
-    GET("",seekerHandler.GetSeekerProfile)
+    GET("",seekerHandler.GetSeekerProfile).
+    GET("/quality", seekerHandler.GetProfileQuality)
 
     seekerProfileHandler := appuser.NewSeekerProfileHandler()
     dashBoardRoute := r.Group("/b1/dashboard", auth)
     dashBoardRoute.GET("", seekerProfileHandler.GetDashboard)
 
+    usageHandler := appuser.NewUsageHandler()
+    r.GET("/b1/usage", auth, usageHandler.GetUsage)
+
 
     newDashboardHandler := appuser.NewDashboardHandler()
     newDashboardRoutes := r.Group("/b1/new-dashboard",auth)
@@ -50,6 +55,10 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     r.Group("/b1/saved-jobs", auth, paginate).
         POST("", savedJobsHandler.SaveJob).
         GET("", savedJobsHandler.GetSavedJobs).
+        GET("/tags", savedJobsHandler.GetSavedJobTags).
+        POST("/bulk-delete", savedJobsHandler.BulkDeleteSavedJobs).
+        POST("/move-to-tracker", savedJobsHandler.MoveSavedJobsToTracker).
+        PUT("/:job_id", savedJobsHandler.UpdateSavedJob).
         DELETE("/:job_id",savedJobsHandler.DeleteSavedJob)
 
     selectedJobsHandler := appuser.NewSelectedJobHandler()
@@ -61,13 +70,22 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
 
     // === JOBS ===
     r.Group("/b1/api/jobs", auth, paginate).
-        GET("", jobs.JobRetrievalHandler)
+        GET("", jobs.JobRetrievalHandler).
+        GET("/search", jobs.JobSearchHandler)
         
 
     // jobshandler := jobs.NewJobsHandler()    
     // r.Group("/b1/jobs",auth,paginate).GET("",jobshandler.GetAllJobs)
     // r.Group("/b1/jobs/delete",auth,paginate).DELETE("",jobshandler.DeleteAllJobs)
 
+    savedSearchHandler := alerts.NewSavedSearchHandler()
+    r.Group("/b1/saved-searches", auth).
+        POST("", savedSearchHandler.CreateSavedSearch).
+        GET("", savedSearchHandler.GetSavedSearches).
+        PUT("/:id", savedSearchHandler.UpdateSavedSearch).
+        DELETE("/:id", savedSearchHandler.DeleteSavedSearch)
+    r.GET("/b1/job-alerts/unsubscribe", alerts.UnsubscribeHandler)
+
     linkProviderHandler := jobs.NewLinkProviderHandler()
     r.Group("/b1/provide-link", auth).
         POST("", linkProviderHandler.PostAndGetLink)
@@ -76,8 +94,33 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     applicationTrackerHandler := appuser.NewApplicationTrackerHandler()
     r.Group("/b1/api/application-tracker",auth,paginate).
     GET("", applicationTrackerHandler.GetApplicationTracker).
+    GET("/board", applicationTrackerHandler.GetApplicationBoard).
+    GET("/analytics", applicationTrackerHandler.GetApplicationAnalytics).
+    POST("/manual", applicationTrackerHandler.CreateManualApplication).
+    POST("/import", applicationTrackerHandler.ImportApplicationsCSV).
     PUT("/:job_id/status", applicationTrackerHandler.UpdateApplicationStatus).
+    POST("/:job_id/applied", applicationTrackerHandler.MarkApplied).
+    POST("/:job_id/notes", applicationTrackerHandler.AddApplicationNote).
+    DELETE("/:job_id/notes/:note_id", applicationTrackerHandler.DeleteApplicationNote).
+    PUT("/:job_id/next-action", applicationTrackerHandler.SetNextAction).
+    POST("/:job_id/attachments/:kind", applicationTrackerHandler.UploadAttachment).
+    GET("/:job_id/attachments/:kind", applicationTrackerHandler.DownloadAttachment).
+    DELETE("/:job_id/attachments/:kind", applicationTrackerHandler.DeleteAttachment).
+    GET("/:job_id/download", applicationTrackerHandler.DownloadDocument).
     GET("/download-all/:job_id", applicationTrackerHandler.GetCVAndCL)
+
+    interviewHandler := appuser.NewInterviewHandler()
+    r.Group("/b1/api/application-tracker", auth).
+        POST("/:job_id/interviews", interviewHandler.CreateInterview)
+    r.Group("/b1/api/interviews", auth).
+        GET("", interviewHandler.ListInterviews).
+        GET("/calendar-feed", interviewHandler.GetCalendarFeed).
+        POST("/calendar-feed/rotate", interviewHandler.RotateCalendarFeed).
+        PUT("/:id", interviewHandler.UpdateInterview).
+        DELETE("/:id", interviewHandler.CancelInterview).
+        GET("/:id/ics", interviewHandler.GetInterviewICS)
+    r.GET("/b1/calendar/:token/interviews.ics", appuser.CalendarFeedHandler)
+
     r.GET("/b1/test/academics/dates", handlers.TestAcademicDatesHandler)
 
     // // === GENERATION ===
@@ -87,6 +130,7 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     generateCLRoute.POST("", coverLetterHandler.PostCoverLetter)
     generateCLRoute.PUT("",coverLetterHandler.PutCoverLetter)
     generateCLRoute.GET("",coverLetterHandler.GetCoverLetter)
+    generateCLRoute.POST("/regenerate", coverLetterHandler.RegenerateCoverLetter)
 
     resumeHandler := generation.NewInternalCVHandler()
     resumeRoute := r.Group("/b1/internal/generate-resume", auth)
@@ -94,6 +138,14 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     // resumeRoute.PUT("", resumeHandler.PutCV)
     resumeRoute.GET("", resumeHandler.GetCV)
     resumeRoute.PUT("",resumeHandler.PutCV)
+    resumeRoute.GET("/analysis", resumeHandler.GetCVAnalysis)
+
+    masterCVHandler := generation.NewMasterCVHandler()
+    r.Group("/b1/master-cv", auth).
+        POST("", masterCVHandler.GenerateMasterCV).
+        GET("", masterCVHandler.ListMasterCVs).
+        GET("/:language", masterCVHandler.GetMasterCV).
+        PUT("/:language/sections/:section", masterCVHandler.UpdateSection)
 
     extGenHandler := generation.NewExternalJobCVNCLGenerator()
     route := r.Group("/b1/external/generate", auth)
@@ -102,6 +154,19 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     route.PUT("/cv",extGenHandler.PutCV)
     route.PUT("/cl",extGenHandler.PutCoverLetter)
 
+    generationJobHandler := generation.NewGenerationJobHandler()
+    r.Group("/b1/generation-jobs", auth).
+        GET("", generationJobHandler.ListGenerationJobs).
+        GET("/:id", generationJobHandler.GetGenerationJob).
+        GET("/:id/events", generationJobHandler.StreamGenerationJob)
+
+    versionHandler := generation.NewDocumentVersionHandler()
+    r.Group("/b1/documents/:job_id/:kind", auth).
+        GET("/versions", versionHandler.ListVersions).
+        GET("/versions/:version", versionHandler.GetVersion).
+        POST("/versions/:version/restore", versionHandler.RestoreVersion).
+        GET("/diff", versionHandler.DiffVersions)
+
 
     jobResearchHandler := generation.NewJobResearchHandler()
     jobResearchGroup := r.Group("/b2/job-research",auth)
@@ -145,7 +210,7 @@ func SetupFeatureRoutes(r *gin.Engine, client *mongo.Client, cfg *config.Config)
     {
         examPortalRoutes.POST("/input",examPortalHandler.GenerateRandomExam)
         examPortalRoutes.POST("/results/submit",examPortalHandler.ProcessExamResults)
-        examPortalRoutes.GET("/results/list",examPortalHandler.GetFilteredExamResults)
+        examPortalRoutes.GET("/results/list",paginate,examPortalHandler.GetFilteredExamResults)
         examPortalRoutes.GET("/results/recent",examPortalHandler.GetRecentExamResults)
     }
 
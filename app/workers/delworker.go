@@ -15,6 +15,10 @@ var purgeCollections = []string{
     "seekers", "user_entry_timelines", "cover_letters", "cv",
     "selected_job_applications", "admins", "match_scores",
     "auth_users", "saved_jobs", "preferences", "notifications",
+    "saved_searches", "job_alert_deliveries",
+    "interview_events", "calendar_feeds", "application_attachments",
+    "document_versions", "generation_jobs", "usage_ledger", "master_cvs",
+    "ats_analyses",
 }
 
 // PurgeOlddeletedUsers finds and purges users deleted over 30 days ago.
@@ -0,0 +1,44 @@
+package workers
+
+import (
+    "context"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "RAAS/internal/handlers/features/generation"
+)
+
+// Idle workers look for due jobs (retries, abandoned runs) at this interval.
+const generationPollInterval = 2 * time.Second
+
+// StartGenerationWorker runs queued CV, cover letter and job research
+// generations on the given number of goroutines.
+func StartGenerationWorker(db *mongo.Database, workers int) context.CancelFunc {
+    ctx, cancel := context.WithCancel(context.Background())
+    for i := 0; i < workers; i++ {
+        go generationLoop(ctx, db)
+    }
+    log.Printf("[GenerationWorker] started %d workers", workers)
+    return cancel
+}
+
+func generationLoop(ctx context.Context, db *mongo.Database) {
+    for {
+        ran, err := generation.ProcessNextGenerationJob(ctx, db)
+        if err != nil {
+            log.Printf("[GenerationWorker] claim error: %v", err)
+        }
+        if ran {
+            continue
+        }
+        select {
+        case <-ctx.Done():
+            log.Println("[GenerationWorker] stopped")
+            return
+        case <-generation.GenerationQueued():
+        case <-time.After(generationPollInterval):
+        }
+    }
+}
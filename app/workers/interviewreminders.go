@@ -0,0 +1,201 @@
+package workers
+
+import (
+    "bytes"
+    "context"
+    "encoding/json"
+    "fmt"
+    "html"
+    "html/template"
+    "log"
+    "sort"
+    "strings"
+    "time"
+
+    "github.com/go-co-op/gocron"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/bson/primitive"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "RAAS/internal/handlers/repository"
+    "RAAS/internal/models"
+    "RAAS/utils"
+)
+
+// Interviews starting within this window get their reminder in the current run.
+const interviewReminderWindow = 24 * time.Hour
+
+// StartInterviewReminderWorker emails users the day before each interview.
+func StartInterviewReminderWorker(db *mongo.Database) *gocron.Scheduler {
+    s := gocron.NewScheduler(time.UTC)
+    s.Every(1).Hour().StartAt(nextFullHour(time.Now().UTC())).Do(func() { runInterviewReminders(db, time.Now()) })
+    s.StartAsync()
+    log.Println("[InterviewReminders] started: hourly reminder check")
+    return s
+}
+
+func runInterviewReminders(db *mongo.Database, now time.Time) {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+    defer cancel()
+
+    events := db.Collection(models.CollectionInterviewEvents)
+    cur, err := events.Find(ctx, bson.M{
+        "cancelled":        false,
+        "reminder_sent_at": bson.M{"$exists": false},
+        "starts_at":        bson.M{"$gt": now, "$lte": now.Add(interviewReminderWindow)},
+    })
+    if err != nil {
+        log.Println("[InterviewReminders] failed to list interviews:", err)
+        return
+    }
+    defer cur.Close(ctx)
+
+    for cur.Next(ctx) {
+        var event models.InterviewEvent
+        if err := cur.Decode(&event); err != nil {
+            log.Println("[InterviewReminders] decode interview:", err)
+            continue
+        }
+
+        if err := sendInterviewReminder(ctx, db, event); err != nil {
+            log.Printf("[InterviewReminders] interview %s for %s failed: %v", event.ID.Hex(), event.AuthUserID, err)
+            continue
+        }
+
+        if _, err := events.UpdateOne(ctx,
+            bson.M{"_id": event.ID},
+            bson.M{"$set": bson.M{"reminder_sent_at": now}},
+        ); err != nil {
+            log.Printf("[InterviewReminders] marking %s sent: %v", event.ID.Hex(), err)
+        }
+    }
+}
+
+func sendInterviewReminder(ctx context.Context, db *mongo.Database, event models.InterviewEvent) error {
+    var user struct {
+        Email     string `bson:"email"`
+        IsDeleted bool   `bson:"is_deleted"`
+    }
+    if err := db.Collection(models.CollectionAuthUsers).FindOne(ctx, bson.M{"auth_user_id": event.AuthUserID}).Decode(&user); err != nil {
+        return err
+    }
+    if user.IsDeleted || user.Email == "" {
+        return nil
+    }
+
+    loc, err := time.LoadLocation(event.Timezone)
+    if err != nil {
+        loc = time.UTC
+    }
+
+    attachments := []utils.EmailAttachment{{
+        Name:        utils.ICSFilename("interview", event.StartsAt),
+        ContentType: "text/calendar; charset=utf-8",
+        Data:        []byte(utils.BuildICS("Interview", []utils.ICSEvent{repository.InterviewICSEvent(event)})),
+    }}
+
+    var research models.JobResearchResult
+    hasResearch := db.Collection(models.CollectionJobResearch).
+        FindOne(ctx, bson.M{"auth_user_id": event.AuthUserID, "job_id": event.JobID}).
+        Decode(&research) == nil
+    if hasResearch {
+        attachments = append(attachments, utils.EmailAttachment{
+            Name:        "job-research.html",
+            ContentType: "text/html; charset=utf-8",
+            Data:        []byte(renderResearchSummary(event, research.Response)),
+        })
+    }
+
+    const tmplStr = `
+    <h2>Reminder: interview {{if .Company}}with {{.Company}} {{end}}tomorrow</h2>
+    <p>
+      <strong>{{.Title}}</strong> (round {{.Round}})<br/>
+      📅 {{.When}}<br/>
+      {{if .Interviewer}}👤 {{.Interviewer}}<br/>{{end}}
+      {{if .Location}}📍 {{.Location}}<br/>{{end}}
+      {{if .VideoLink}}🔗 <a href="{{.VideoLink}}">Join video call</a><br/>{{end}}
+    </p>
+    {{if .HasResearch}}<p>Your job research summary is attached. Good luck!</p>{{else}}<p>Good luck!</p>{{end}}
+    `
+
+    data := struct {
+        models.InterviewEvent
+        When        string
+        HasResearch bool
+    }{
+        InterviewEvent: event,
+        When:           event.StartsAt.In(loc).Format("Monday, 02 Jan 2006 15:04 MST"),
+        HasResearch:    hasResearch,
+    }
+
+    t := template.Must(template.New("interviewReminder").Parse(tmplStr))
+    var buf bytes.Buffer
+    if err := t.Execute(&buf, data); err != nil {
+        return err
+    }
+
+    return utils.SendEmailWithAttachments(utils.GetEmailConfig(), user.Email, "Interview reminder", buf.String(), attachments...)
+}
+
+// renderResearchSummary turns the stored research response into a
+// standalone HTML document, one section per top-level key.
+func renderResearchSummary(event models.InterviewEvent, resp map[string]interface{}) string {
+    var b strings.Builder
+    b.WriteString("<!DOCTYPE html><html><head><meta charset=\"utf-8\"><title>Job research</title></head><body>")
+    heading := event.Title
+    if event.Company != "" {
+        heading = strings.TrimSpace(heading + " – " + event.Company)
+    }
+    fmt.Fprintf(&b, "<h1>%s</h1>", html.EscapeString(heading))
+
+    keys := make([]string, 0, len(resp))
+    for k := range resp {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    for _, k := range keys {
+        fmt.Fprintf(&b, "<h2>%s</h2>", html.EscapeString(strings.ReplaceAll(k, "_", " ")))
+        writeResearchValue(&b, resp[k])
+    }
+    b.WriteString("</body></html>")
+    return b.String()
+}
+
+func writeResearchValue(b *strings.Builder, v interface{}) {
+    switch val := v.(type) {
+    case string:
+        fmt.Fprintf(b, "<p>%s</p>", html.EscapeString(val))
+    case primitive.A:
+        writeResearchValue(b, []interface{}(val))
+    case primitive.D:
+        writeResearchValue(b, map[string]interface{}(val.Map()))
+    case []interface{}:
+        b.WriteString("<ul>")
+        for _, item := range val {
+            b.WriteString("<li>")
+            writeResearchValue(b, item)
+            b.WriteString("</li>")
+        }
+        b.WriteString("</ul>")
+    case map[string]interface{}:
+        keys := make([]string, 0, len(val))
+        for k := range val {
+            keys = append(keys, k)
+        }
+        sort.Strings(keys)
+        b.WriteString("<dl>")
+        for _, k := range keys {
+            fmt.Fprintf(b, "<dt><strong>%s</strong></dt><dd>", html.EscapeString(k))
+            writeResearchValue(b, val[k])
+            b.WriteString("</dd>")
+        }
+        b.WriteString("</dl>")
+    default:
+        // Fall back to JSON for numbers, booleans and BSON-specific types
+        data, err := json.Marshal(val)
+        if err != nil {
+            data = []byte(fmt.Sprint(val))
+        }
+        fmt.Fprintf(b, "<p>%s</p>", html.EscapeString(string(data)))
+    }
+}
@@ -0,0 +1,254 @@
+package workers
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "html/template"
+    "log"
+    "net/url"
+    "time"
+
+    "github.com/go-co-op/gocron"
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+    "go.mongodb.org/mongo-driver/mongo/options"
+
+    "RAAS/core/config"
+    "RAAS/internal/handlers/repository"
+    "RAAS/internal/models"
+    "RAAS/utils"
+)
+
+const (
+    // Local hour (in the user's timezone) at which digests go out
+    alertSendHour = 8
+    // Weekly digests go out on this local weekday
+    alertSendWeekday = time.Monday
+    // Maximum jobs listed in one digest
+    alertMaxJobs = 10
+)
+
+// StartJobAlertNotifier checks saved searches every hour and sends digests
+// to users whose local send time has come.
+func StartJobAlertNotifier(db *mongo.Database) *gocron.Scheduler {
+    s := gocron.NewScheduler(time.UTC)
+    s.Every(1).Hour().StartAt(nextFullHour(time.Now().UTC())).Do(func() { runJobAlerts(db, time.Now()) })
+    s.StartAsync()
+    log.Println("[JobAlerts] started: hourly saved search digests")
+    return s
+}
+
+func nextFullHour(t time.Time) time.Time {
+    return t.Truncate(time.Hour).Add(time.Hour)
+}
+
+// alertRecipient caches per-user data needed while processing their searches.
+type alertRecipient struct {
+    Email    string
+    Location *time.Location
+    Enabled  bool
+}
+
+func runJobAlerts(db *mongo.Database, now time.Time) {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+    defer cancel()
+
+    cur, err := db.Collection(models.CollectionSavedSearches).Find(ctx, bson.M{"alerts_enabled": true})
+    if err != nil {
+        log.Println("[JobAlerts] failed to list saved searches:", err)
+        return
+    }
+    defer cur.Close(ctx)
+
+    recipients := make(map[string]alertRecipient)
+    for cur.Next(ctx) {
+        var search models.SavedSearch
+        if err := cur.Decode(&search); err != nil {
+            log.Println("[JobAlerts] decode saved search:", err)
+            continue
+        }
+
+        rcpt, ok := recipients[search.AuthUserID]
+        if !ok {
+            rcpt = loadAlertRecipient(ctx, db, search.AuthUserID)
+            recipients[search.AuthUserID] = rcpt
+        }
+        if !rcpt.Enabled || !isAlertDue(search, now, rcpt.Location) {
+            continue
+        }
+
+        if err := sendSavedSearchDigest(ctx, db, search, rcpt, now); err != nil {
+            log.Printf("[JobAlerts] search %s for %s failed: %v", search.ID.Hex(), search.AuthUserID, err)
+        }
+    }
+}
+
+// loadAlertRecipient resolves email, timezone and whether recommended-job
+// notifications are switched on for the user.
+func loadAlertRecipient(ctx context.Context, db *mongo.Database, userID string) alertRecipient {
+    var user struct {
+        Email     string `bson:"email"`
+        IsDeleted bool   `bson:"is_deleted"`
+    }
+    if err := db.Collection(models.CollectionAuthUsers).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&user); err != nil || user.IsDeleted || user.Email == "" {
+        return alertRecipient{}
+    }
+
+    var ns models.NotificationSettings
+    err := db.Collection(models.CollectionNotifications).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&ns)
+    if err == nil && !ns.RecommendedJobs {
+        return alertRecipient{}
+    }
+
+    loc := time.UTC
+    if prefs, err := repository.GetUserPreferences(ctx, db, userID); err == nil && prefs.Timezone != "" {
+        if l, err := time.LoadLocation(prefs.Timezone); err == nil {
+            loc = l
+        }
+    }
+
+    return alertRecipient{Email: user.Email, Location: loc, Enabled: true}
+}
+
+// isAlertDue reports whether the search's digest should be sent in this run.
+func isAlertDue(search models.SavedSearch, now time.Time, loc *time.Location) bool {
+    local := now.In(loc)
+    if local.Hour() != alertSendHour {
+        return false
+    }
+
+    minGap := 20 * time.Hour
+    if search.Frequency == models.AlertFrequencyWeekly {
+        if local.Weekday() != alertSendWeekday {
+            return false
+        }
+        minGap = 6 * 24 * time.Hour
+    }
+    return search.LastSentAt == nil || now.Sub(*search.LastSentAt) >= minGap
+}
+
+func sendSavedSearchDigest(ctx context.Context, db *mongo.Database, search models.SavedSearch, rcpt alertRecipient, now time.Time) error {
+    windowDays := 1
+    if search.Frequency == models.AlertFrequencyWeekly {
+        windowDays = 7
+    }
+    // one extra day so jobs posted just before the last run are not missed
+    query := repository.SearchCriteriaToQuery(search.Criteria, windowDays+1)
+
+    result, err := repository.SearchJobs(ctx, db, search.AuthUserID, query, 0, alertMaxJobs*3)
+    if err != nil {
+        return err
+    }
+    if len(result.Results) == 0 {
+        return nil
+    }
+
+    // De-duplicate against jobs already sent for this search
+    ids := make([]string, 0, len(result.Results))
+    for _, r := range result.Results {
+        ids = append(ids, r.JobID)
+    }
+    sent, err := deliveredJobIDs(ctx, db, search.ID, ids)
+    if err != nil {
+        return err
+    }
+
+    var jobs []models.Job
+    for _, r := range result.Results {
+        if sent[r.JobID] {
+            continue
+        }
+        jobs = append(jobs, r.Job)
+        if len(jobs) >= alertMaxJobs {
+            break
+        }
+    }
+    if len(jobs) == 0 {
+        return nil
+    }
+
+    if err := sendJobAlertEmail(rcpt.Email, search, jobs); err != nil {
+        return err
+    }
+
+    deliveries := make([]interface{}, 0, len(jobs))
+    for _, job := range jobs {
+        deliveries = append(deliveries, models.JobAlertDelivery{
+            SavedSearchID: search.ID,
+            AuthUserID:    search.AuthUserID,
+            JobID:         job.JobID,
+            SentAt:        now,
+        })
+    }
+    if _, err := db.Collection(models.CollectionJobAlertDeliveries).InsertMany(ctx, deliveries, options.InsertMany().SetOrdered(false)); err != nil && !mongo.IsDuplicateKeyError(err) {
+        log.Printf("[JobAlerts] recording deliveries for %s: %v", search.ID.Hex(), err)
+    }
+
+    _, err = db.Collection(models.CollectionSavedSearches).UpdateOne(ctx,
+        bson.M{"_id": search.ID},
+        bson.M{"$set": bson.M{"last_sent_at": now}},
+    )
+    return err
+}
+
+func deliveredJobIDs(ctx context.Context, db *mongo.Database, searchID interface{}, jobIDs []string) (map[string]bool, error) {
+    cur, err := db.Collection(models.CollectionJobAlertDeliveries).Find(ctx, bson.M{
+        "saved_search_id": searchID,
+        "job_id":          bson.M{"$in": jobIDs},
+    })
+    if err != nil {
+        return nil, err
+    }
+    defer cur.Close(ctx)
+
+    sent := make(map[string]bool)
+    for cur.Next(ctx) {
+        var d models.JobAlertDelivery
+        if err := cur.Decode(&d); err == nil {
+            sent[d.JobID] = true
+        }
+    }
+    return sent, cur.Err()
+}
+
+func sendJobAlertEmail(to string, search models.SavedSearch, jobs []models.Job) error {
+    cfg := utils.GetEmailConfig()
+
+    const tmplStr = `
+    <h2>{{len .Jobs}} new jobs for "{{.Name}}"</h2>
+    <ul>
+    {{range .Jobs}}
+      <li style="margin-bottom:12px;">
+        <strong>{{.Title}}</strong> at <em>{{.Company}}</em><br/>
+        📍 {{.Location}} | 📌 Type: {{.JobType}} | 📅 {{.PostedDate.Format "2006-01-02"}}
+      </li>
+    {{end}}
+    </ul>
+    <p style="font-size:12px;color:#888;">
+      You receive this {{.Frequency}} alert because you saved this search.
+      <a href="{{.UnsubscribeURL}}">Unsubscribe</a>
+    </p>
+    `
+
+    data := struct {
+        Name           string
+        Frequency      string
+        Jobs           []models.Job
+        UnsubscribeURL string
+    }{
+        Name:      search.Name,
+        Frequency: search.Frequency,
+        Jobs:      jobs,
+        UnsubscribeURL: fmt.Sprintf("%s/b1/job-alerts/unsubscribe?token=%s",
+            config.Cfg.Project.FrontendBaseUrl, url.QueryEscape(search.UnsubscribeToken)),
+    }
+
+    t := template.Must(template.New("jobAlertEmail").Parse(tmplStr))
+    var buf bytes.Buffer
+    if err := t.Execute(&buf, data); err != nil {
+        return err
+    }
+
+    return utils.SendEmail(cfg, to, fmt.Sprintf("New jobs for your search \"%s\"", search.Name), buf.String())
+}
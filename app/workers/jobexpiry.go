@@ -0,0 +1,180 @@
+package workers
+
+import (
+    "bytes"
+    "context"
+    "html/template"
+    "log"
+    "time"
+
+    "go.mongodb.org/mongo-driver/bson"
+    "go.mongodb.org/mongo-driver/mongo"
+
+    "RAAS/internal/geo"
+    "RAAS/internal/models"
+    "RAAS/internal/skills"
+    "RAAS/utils"
+)
+
+// RunJobExpiryTasks normalizes legacy job fields, geocodes and skill-tags new jobs, closes jobs whose
+// expires_at has passed and notifies users who had saved them.
+func RunJobExpiryTasks(ctx context.Context, db *mongo.Database) {
+    log.Println("[JobExpiryWorker] Starting expiry task...")
+
+    jobsColl := db.Collection(models.CollectionJobs)
+    if _, err := models.NormalizeJobLifecycleFields(ctx, jobsColl); err != nil {
+        log.Printf("[JobExpiryWorker] normalize error: %v", err)
+    }
+    if n, err := geo.GeocodePendingJobs(ctx, jobsColl); err != nil {
+        log.Printf("[JobExpiryWorker] geocode error: %v", err)
+    } else if n > 0 {
+        log.Printf("[JobExpiryWorker] geocoded %d jobs", n)
+    }
+    if err := skills.Reload(ctx, db.Collection(models.CollectionSkillTaxonomy)); err != nil {
+        log.Printf("[JobExpiryWorker] skill taxonomy reload error: %v", err)
+    }
+    if n, err := skills.TagPendingJobs(ctx, jobsColl); err != nil {
+        log.Printf("[JobExpiryWorker] skill tagging error: %v", err)
+    } else if n > 0 {
+        log.Printf("[JobExpiryWorker] tagged skills on %d jobs", n)
+    }
+
+    expired, err := closeExpiredJobs(ctx, jobsColl, time.Now())
+    if err != nil {
+        log.Printf("[JobExpiryWorker] close error: %v", err)
+        return
+    }
+    if len(expired) == 0 {
+        return
+    }
+    log.Printf("[JobExpiryWorker] closed %d expired jobs", len(expired))
+
+    notifySaversOfExpiredJobs(ctx, db, expired)
+}
+
+// closeExpiredJobs marks active jobs past their expiry as inactive and returns them.
+func closeExpiredJobs(ctx context.Context, coll *mongo.Collection, now time.Time) ([]models.Job, error) {
+    filter := bson.M{
+        "is_active":  true,
+        "expires_at": bson.M{"$lte": now},
+    }
+
+    cursor, err := coll.Find(ctx, filter)
+    if err != nil {
+        return nil, err
+    }
+    defer cursor.Close(ctx)
+
+    var expired []models.Job
+    if err := cursor.All(ctx, &expired); err != nil {
+        return nil, err
+    }
+    if len(expired) == 0 {
+        return nil, nil
+    }
+
+    ids := make([]string, len(expired))
+    for i, job := range expired {
+        ids[i] = job.JobID
+    }
+    _, err = coll.UpdateMany(ctx,
+        bson.M{"job_id": bson.M{"$in": ids}},
+        bson.M{"$set": bson.M{"is_active": false, "closed_at": now}},
+    )
+    return expired, err
+}
+
+// notifySaversOfExpiredJobs emails every user who saved one of the expired jobs.
+func notifySaversOfExpiredJobs(ctx context.Context, db *mongo.Database, expired []models.Job) {
+    jobsByID := make(map[string]models.Job, len(expired))
+    ids := make([]string, 0, len(expired))
+    for _, job := range expired {
+        jobsByID[job.JobID] = job
+        ids = append(ids, job.JobID)
+    }
+
+    cursor, err := db.Collection(models.CollectionSavedJobs).Find(ctx, bson.M{"job_id": bson.M{"$in": ids}})
+    if err != nil {
+        log.Printf("[JobExpiryWorker] fetch saved jobs: %v", err)
+        return
+    }
+    defer cursor.Close(ctx)
+
+    perUser := make(map[string][]models.Job)
+    for cursor.Next(ctx) {
+        var saved models.SavedJob
+        if err := cursor.Decode(&saved); err != nil {
+            log.Printf("[JobExpiryWorker] decode saved job: %v", err)
+            continue
+        }
+        perUser[saved.AuthUserID] = append(perUser[saved.AuthUserID], jobsByID[saved.JobID])
+    }
+
+    users := db.Collection(models.CollectionAuthUsers)
+    for userID, jobs := range perUser {
+        var u struct {
+            Email string `bson:"email"`
+        }
+        err := users.FindOne(ctx, bson.M{"auth_user_id": userID, "is_deleted": bson.M{"$ne": true}}).Decode(&u)
+        if err != nil || u.Email == "" {
+            continue
+        }
+        // Job emails follow the recommended-jobs toggle, as the job alerts do
+        var ns models.NotificationSettings
+        err = db.Collection(models.CollectionNotifications).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&ns)
+        if err == nil && !ns.RecommendedJobs {
+            continue
+        }
+        if err := sendExpiredJobsEmail(u.Email, jobs); err != nil {
+            log.Printf("[JobExpiryWorker] sending to %s failed: %v", u.Email, err)
+        }
+    }
+}
+
+func sendExpiredJobsEmail(to string, jobs []models.Job) error {
+    cfg := utils.GetEmailConfig()
+
+    const tmplStr = `
+    <h2>Saved jobs no longer accepting applications</h2>
+    <p>The following jobs you saved have expired and were closed:</p>
+    <ul>
+    {{range .}}
+      <li style="margin-bottom:12px;">
+        <strong>{{.Title}}</strong> at <em>{{.Company}}</em><br/>
+        📍 {{.Location}} | 📅 Posted {{.PostedDate.Format "2006-01-02"}}
+      </li>
+    {{end}}
+    </ul>
+    `
+
+    t := template.Must(template.New("expiredJobsEmail").Parse(tmplStr))
+    var buf bytes.Buffer
+    if err := t.Execute(&buf, jobs); err != nil {
+        return err
+    }
+
+    return utils.SendEmail(cfg, to, "Some of your saved jobs have expired", buf.String())
+}
+
+func jobExpiryLoop(ctx context.Context, interval time.Duration, db *mongo.Database) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+
+    RunJobExpiryTasks(ctx, db)
+    for {
+        select {
+        case <-ctx.Done():
+            log.Println("[JobExpiryWorker] stopped")
+            return
+        case <-ticker.C:
+            RunJobExpiryTasks(ctx, db)
+        }
+    }
+}
+
+// StartJobExpiryWorker starts the job expiry scheduler.
+func StartJobExpiryWorker(db *mongo.Database, interval time.Duration) context.CancelFunc {
+    ctx, cancel := context.WithCancel(context.Background())
+    go jobExpiryLoop(ctx, interval, db)
+    return cancel
+}
@@ -37,6 +37,8 @@ type CloudConfig struct {
     DataExtractionAPI          string
 
     GEN_API_KEY                string
+    MLLogBodies                bool // log redacted ML request and response bodies
+    MLStub                     bool // answer ML calls with the built-in stub, for development and tests
 
     BasicPlanMonthly           string
     BasicPlanQuarterly          string
@@ -88,6 +90,8 @@ func LoadCloudConfig() (*CloudConfig, error) {
         CV_Url:                     viper.GetString("CV_RESUME_API_URL"),
         JobResearchURL:              viper.GetString("JOB_RESEARCH_API"),
         GEN_API_KEY:                viper.GetString("COVER_CV_API_KEY"),
+        MLLogBodies:                viper.GetBool("ML_LOG_BODIES"),
+        MLStub:                     viper.GetBool("ML_STUB"),
 
         DataExtractionAPI:          viper.GetString("DATA_EXTRACTION_API"),    
 
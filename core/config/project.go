@@ -1,6 +1,10 @@
 package config
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+)
 
 type ProjectConfig struct {
 	// General Settings
@@ -37,8 +41,20 @@ type ProjectConfig struct {
 	RestThrottleClasses          string
 	RestThrottleRatesAnon        string
 	RestThrottleRatesUser        string
+
+	// Quota Settings
+	CoverLetterRegenerationCost  int // hundredths of an application one cover letter regeneration costs
+
+	// Worker Settings
+	GenerationWorkers            int // concurrent CV and cover letter generations
 }
 
+// DefaultCoverLetterRegenerationCost applies when CL_REGENERATION_COST is not set.
+const DefaultCoverLetterRegenerationCost = 25
+
+// DefaultGenerationWorkers applies when GENERATION_WORKERS is not set.
+const DefaultGenerationWorkers = 4
+
 func LoadProjectConfig() (*ProjectConfig, error) {
 	// Load values into the config struct
 	ProjectConfig := &ProjectConfig{
@@ -71,6 +87,22 @@ func LoadProjectConfig() (*ProjectConfig, error) {
 		RestThrottleClasses:        viper.GetString("REST_FRAMEWORK_DEFAULT_THROTTLE_CLASSES"),
 		RestThrottleRatesAnon:      viper.GetString("REST_FRAMEWORK_DEFAULT_THROTTLE_RATES_ANON"),
 		RestThrottleRatesUser:      viper.GetString("REST_FRAMEWORK_DEFAULT_THROTTLE_RATES_USER"),
+
+		CoverLetterRegenerationCost: viper.GetInt("CL_REGENERATION_COST"),
+
+		GenerationWorkers:          viper.GetInt("GENERATION_WORKERS"),
+	}
+	if !viper.IsSet("CL_REGENERATION_COST") {
+		ProjectConfig.CoverLetterRegenerationCost = DefaultCoverLetterRegenerationCost
+	}
+	if cost := ProjectConfig.CoverLetterRegenerationCost; cost < 0 || cost > 100 {
+		return nil, fmt.Errorf("CL_REGENERATION_COST must be between 0 and 100, got %d", cost)
+	}
+	if !viper.IsSet("GENERATION_WORKERS") {
+		ProjectConfig.GenerationWorkers = DefaultGenerationWorkers
+	}
+	if ProjectConfig.GenerationWorkers < 1 {
+		return nil, fmt.Errorf("GENERATION_WORKERS must be at least 1, got %d", ProjectConfig.GenerationWorkers)
 	}
 
 	return ProjectConfig, nil
@@ -61,4 +61,16 @@ func AuthMiddleware() gin.HandlerFunc {
 
         c.Next()
     }
-}
\ No newline at end of file
+}
+// RequireRole rejects requests whose token role differs from role. It must
+// run after AuthMiddleware.
+func RequireRole(role string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if c.GetString("role") != role {
+            c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+            c.Abort()
+            return
+        }
+        c.Next()
+    }
+}
@@ -1,11 +1,22 @@
 package middleware
 
 import (
+	"RAAS/core/security"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"strconv"
 )
 
-// PaginationMiddleware handles pagination logic using offset and limit
+// Pagination modes stored under "mode" in the pagination context value
+const (
+	PaginationModeOffset = "offset"
+	PaginationModeCursor = "cursor"
+)
+
+// PaginationMiddleware handles pagination logic using offset and limit.
+// Passing ?cursor=<token> (or ?paginate=cursor for the first page) switches
+// to cursor mode; the verified cursor is stored under "cursor".
 func PaginationMiddleware(c *gin.Context) {
 	// Get the "offset" and "limit" query parameters
 	offset := c.DefaultQuery("offset", "0") // Default to 0 if not provided
@@ -28,10 +39,26 @@ func PaginationMiddleware(c *gin.Context) {
 		limitInt = maxLimit // Cap the limit to maxLimit if exceeded
 	}
 
+	mode := PaginationModeOffset
+	var cursor *security.PageCursor
+	if token := c.Query("cursor"); token != "" {
+		cursor, err = security.DecodeCursor(token)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+			c.Abort()
+			return
+		}
+		mode = PaginationModeCursor
+	} else if c.Query("paginate") == PaginationModeCursor {
+		mode = PaginationModeCursor
+	}
+
 	// Store pagination information in context
 	c.Set("pagination", gin.H{
 		"offset": offsetInt,
 		"limit":  limitInt,
+		"mode":   mode,
+		"cursor": cursor,
 	})
 
 	// Proceed to the next handler
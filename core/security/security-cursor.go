@@ -0,0 +1,59 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// PageCursor is the position a cursor-paginated list continues from.
+// Value and ID keep their BSON types so they can be used directly in filters.
+type PageCursor struct {
+	Field    string      `bson:"f"`
+	Value    interface{} `bson:"v"`
+	ID       interface{} `bson:"id"`
+	Backward bool        `bson:"b,omitempty"`
+}
+
+// EncodeCursor serializes and signs a cursor into an opaque URL-safe token.
+func EncodeCursor(cur PageCursor) (string, error) {
+	payload, err := bson.Marshal(cur)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(signCursor(payload)), nil
+}
+
+// DecodeCursor verifies the token's signature and returns the cursor it holds.
+func DecodeCursor(token string) (*PageCursor, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	sig, err := enc.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(sig, signCursor(payload)) {
+		return nil, fmt.Errorf("invalid cursor signature")
+	}
+
+	var cur PageCursor
+	if err := bson.Unmarshal(payload, &cur); err != nil {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	return &cur, nil
+}
+
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte("cursor:"+getSecretKey()))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -66,11 +66,11 @@ type SeekerDTO struct {
 	ProficiencyTest            int                `json:"proficiency_test"`
 
 	PersonalInfo                bson.M             `json:"personal_info"`
-	WorkExperiences             []bson.M           `json:"work_experiences"`
-	Academics                   []bson.M           `json:"academics"`
-	PastProjects                []bson.M           `json:"past_projects"`
-	Certificates                []bson.M           `json:"certificates"`
-	Languages                   []bson.M           `json:"languages"`
+	WorkExperiences             []models.WorkExperience `json:"work_experiences"`
+	Academics                   []models.Academics      `json:"academics"`
+	PastProjects                []models.PastProject    `json:"past_projects"`
+	Certificates                []models.Certificate    `json:"certificates"`
+	Languages                   []models.Language       `json:"languages"`
 	KeySkills                   []string           `json:"key_skills"`
 
 	PrimaryTitle                string             `json:"primary_title"`
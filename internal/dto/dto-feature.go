@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"RAAS/internal/models"
+
 	"time"
 )
 
@@ -124,3 +126,44 @@ type BillingInfoDTO struct {
 	Invoices      []InvoiceDTO  `json:"invoices"`
 }
 
+
+// DocumentChange is one difference between two versions of a document.
+// Path addresses the value, e.g. "experience[0].responsibilities[2]".
+type DocumentChange struct {
+	Path string      `json:"path"`
+	Op   string      `json:"op"` // added | removed | changed
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+type DocumentDiff struct {
+	Kind    string           `json:"kind"`
+	From    int              `json:"from"`
+	To      int              `json:"to"`
+	Added   int              `json:"added"`
+	Removed int              `json:"removed"`
+	Changed int              `json:"changed"`
+	Changes []DocumentChange `json:"changes"`
+}
+
+// UsageQuota sums the ledger of one quota over a billing period.
+type UsageQuota struct {
+	Resource  string `json:"resource"`
+	Limit     int    `json:"limit"`
+	Remaining int    `json:"remaining"`
+	Committed int    `json:"committed"`
+	Reserved  int    `json:"reserved"` // held by queued or running generations
+	Released  int    `json:"released"` // refunded after failed generations
+
+	// Cover letter regenerations are charged in hundredths of an application
+	Regenerations int `json:"regenerations"`
+	Credit        int `json:"credit"` // hundredths left over from the last application opened by them
+}
+
+type UsageResponse struct {
+	SubscriptionTier string              `json:"subscription_tier"`
+	PeriodStart      *time.Time          `json:"period_start,omitempty"`
+	PeriodEnd        *time.Time          `json:"period_end,omitempty"`
+	Quotas           []UsageQuota        `json:"quotas"`
+	Entries          []models.UsageEntry `json:"entries"`
+}
@@ -1,5 +1,7 @@
 package dto
 
+import "time"
+
 // LinkResponseDTO represents the response DTO for job application links
 type LinkResponseDTO struct {
     JobID   string `json:"job_id" bson:"job_id"`
@@ -31,6 +33,9 @@ type JobDTO struct {
 
     //Extra Fields
     Selected       bool         `json:"selected" bson:"selected"`
+    Relevance      float64      `json:"relevance,omitempty" bson:"relevance,omitempty"` // Text search score (search only)
+    WorkMode       string       `json:"work_mode,omitempty" bson:"work_mode,omitempty"`   // onsite | hybrid | remote
+    DistanceKm     *float64     `json:"distance_km,omitempty" bson:"distance_km,omitempty"` // From the seeker's city
 
     //Generated and viewed
     LinkViewed     bool         `json:"link_viewed" bson:"link_viewed"`
@@ -39,6 +44,18 @@ type JobDTO struct {
 
 }
 
+// SavedJobDTO is a JobDTO with the user's saved job details. Removed jobs
+// keep their saved details with empty job fields.
+type SavedJobDTO struct {
+    JobDTO
+    SavedAt        time.Time    `json:"saved_at" bson:"saved_at"`
+    Notes          string       `json:"notes" bson:"notes"`
+    Tags           []string     `json:"tags" bson:"tags"`
+    Folder         string       `json:"folder" bson:"folder"`
+    Priority       string       `json:"priority" bson:"priority"`
+    Warning        string       `json:"warning,omitempty" bson:"warning,omitempty"` // removed | expired | stale
+}
+
 // JobFilterDTO represents the filter data for job retrieval.
 type JobFilterDTO struct {
     Title     string `form:"title" bson:"title"`
@@ -46,6 +63,52 @@ type JobFilterDTO struct {
 }
 
 
+// JobSearchQuery holds the query parameters accepted by /b1/api/jobs/search.
+type JobSearchQuery struct {
+    Q                string  `form:"q"`
+    JobType          string  `form:"job_type"`
+    Location         string  `form:"location"`
+    JobLang          string  `form:"job_language"`
+    Company          string  `form:"company"`
+    PostedWithinDays int     `form:"posted_within_days"`
+    MinMatchScore    float64 `form:"min_match_score"`
+    Sort             string  `form:"sort"` // relevance | date | match_score
+}
+
+// SavedSearchRequest creates or updates a saved search.
+type SavedSearchRequest struct {
+    Name          string  `json:"name" binding:"required,max=100"`
+    Q             string  `json:"q"`
+    JobType       string  `json:"job_type"`
+    Location      string  `json:"location"`
+    JobLang       string  `json:"job_language"`
+    Company       string  `json:"company"`
+    MinMatchScore float64 `json:"min_match_score" binding:"min=0,max=100"`
+    Frequency     string  `json:"frequency" binding:"required,oneof=daily weekly"`
+    AlertsEnabled *bool   `json:"alerts_enabled"`
+}
+
+// FacetCount is a single facet value and how many jobs carry it.
+type FacetCount struct {
+    Value string `json:"value" bson:"value"`
+    Count int    `json:"count" bson:"count"`
+}
+
+// PostedWithinCount counts jobs posted in the last Days days.
+type PostedWithinCount struct {
+    Days  int `json:"days"`
+    Count int `json:"count"`
+}
+
+// JobSearchFacets groups the facet counts returned by job search.
+type JobSearchFacets struct {
+    JobType      []FacetCount        `json:"job_type"`
+    Location     []FacetCount        `json:"location"`
+    JobLang      []FacetCount        `json:"job_language"`
+    Company      []FacetCount        `json:"company"`
+    PostedWithin []PostedWithinCount `json:"posted_within"`
+}
+
 type SelectedJobResponse struct {
 	AuthUserID            string             `json:"auth_user_id"`
 	Source                string             `json:"source"`
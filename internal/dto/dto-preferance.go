@@ -159,6 +159,7 @@ type CertificateRequest struct {
 
 
 type CertificateResponse struct {
+    ID               primitive.ObjectID `json:"id" bson:"_id"`
     AuthUserID       string     `json:"auth_user_id" bson:"auth_user_id"`
     CertificateName  string     `json:"certificate_name" bson:"certificate_name"`
     CertificateType  string     `json:"certificate_type" bson:"certificate_type"`
@@ -202,6 +203,14 @@ type KeySkillsResponse struct {
 	UpdatedAt  time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
+// SkillSuggestion is one autocomplete result from the skills taxonomy.
+type SkillSuggestion struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Related  []string `json:"related"`
+}
+
 // =======================
 // PREFERENCES
 // =======================
@@ -210,6 +219,11 @@ type PreferencesRequest struct {
 	Language             string `json:"language" binding:"required" bson:"language"`                       // "english" or "german"
 	Timezone             string `json:"timezone" binding:"required" bson:"timezone"`                       // e.g., "Europe/Berlin"
 	CookiePolicy         bool   `json:"cookie_policy" bson:"cookie_policy"`             // true or false
+
+	// Optional location preferences; omitted fields are left unchanged
+	PreferredRadiusKm    *int    `json:"preferred_radius_km,omitempty" binding:"omitempty,min=0,max=1000"`
+	WillingToRelocate    *bool   `json:"willing_to_relocate,omitempty"`
+	WorkModePreference   *string `json:"work_mode_preference,omitempty" binding:"omitempty,oneof=any onsite hybrid remote"`
 }
 
 type PreferencesResponse struct {
@@ -217,6 +231,9 @@ type PreferencesResponse struct {
 	Language             string    `json:"language" bson:"language"`
 	Timezone             string    `json:"timezone" bson:"timezone"`
 	CookiePolicy         bool      `json:"cookie_policy" bson:"cookie_policy"`
+	PreferredRadiusKm    int       `json:"preferred_radius_km" bson:"preferred_radius_km"`
+	WillingToRelocate    bool      `json:"willing_to_relocate" bson:"willing_to_relocate"`
+	WorkModePreference   string    `json:"work_mode_preference" bson:"work_mode_preference"`
 	CreatedAt            time.Time `json:"created_at" bson:"created_at"`
 	UpdatedAt            time.Time `json:"updated_at" bson:"updated_at"`
 }
@@ -239,3 +256,41 @@ type NotificationSettingsResponse struct {
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 }
+
+// =======================
+// RESUME IMPORT
+// =======================
+
+// ResumeDraft is a resume mapped onto the profile request DTOs for review.
+// Confidence holds a 0-1 score per field path, e.g. "personal_info.first_name"
+// or "work_experiences[0].start_date". Issues lists fields that must be
+// fixed before an entry can be saved.
+type ResumeDraft struct {
+	PersonalInfo    *PersonalInfoRequest    `json:"personal_info,omitempty" bson:"personal_info,omitempty"`
+	WorkExperiences []WorkExperienceRequest `json:"work_experiences" bson:"work_experiences"`
+	Academics       []AcademicsRequest      `json:"academics" bson:"academics"`
+	Languages       []LanguageRequest       `json:"languages" bson:"languages"`
+	KeySkills       KeySkillsRequest        `json:"key_skills" bson:"key_skills"`
+	Confidence      map[string]float64      `json:"confidence" bson:"confidence"`
+	Issues          map[string]string       `json:"issues,omitempty" bson:"issues,omitempty"`
+}
+
+// ResumeImportApplyRequest selects what to copy from a draft to the profile.
+// With All set the draft is taken as is and entries with issues are skipped;
+// otherwise the (possibly edited) entries sent are saved.
+type ResumeImportApplyRequest struct {
+	All             bool                    `json:"all"`
+	PersonalInfo    *PersonalInfoRequest    `json:"personal_info,omitempty"`
+	WorkExperiences []WorkExperienceRequest `json:"work_experiences,omitempty" binding:"omitempty,dive"`
+	Academics       []AcademicsRequest      `json:"academics,omitempty" binding:"omitempty,dive"`
+	Languages       []LanguageRequest       `json:"languages,omitempty" binding:"omitempty,dive"`
+	KeySkills       []string                `json:"key_skills,omitempty"`
+}
+
+// ResumeImportResult reports what an apply call saved.
+type ResumeImportResult struct {
+	Sections  []string          `json:"sections"`
+	Skipped   map[string]string `json:"skipped,omitempty"`
+	Completed bool              `json:"onboarding_completed"`
+	NextStep  string            `json:"next_step"`
+}
@@ -0,0 +1,141 @@
+package geo
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"log"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed data/cities.csv
+var citiesCSV []byte
+
+// City is a gazetteer entry.
+type City struct {
+	Name    string  `json:"name"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+}
+
+// Point returns the city as a GeoJSON point.
+func (c City) Point() *Point {
+	return NewPoint(c.Lat, c.Lon)
+}
+
+var (
+	loadOnce sync.Once
+	cities   map[string]City
+)
+
+// foldReplacer lowercases common diacritics so "München", "Muenchen" and
+// "Munchen" resolve to the same key.
+var foldReplacer = strings.NewReplacer(
+	"ä", "a", "ö", "o", "ü", "u", "ß", "ss",
+	"é", "e", "è", "e", "ê", "e", "á", "a", "à", "a", "â", "a", "å", "a",
+	"ó", "o", "ò", "o", "ô", "o", "ø", "o", "í", "i", "ì", "i",
+	"ú", "u", "ù", "u", "ç", "c", "ł", "l", "ș", "s", "ş", "s", "ñ", "n",
+)
+
+func normalize(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = foldReplacer.Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func load() {
+	cities = make(map[string]City)
+	scanner := bufio.NewScanner(bytes.NewReader(citiesCSV))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cols := strings.Split(line, ",")
+		if len(cols) != 5 {
+			log.Printf("⚠️ gazetteer: skipping malformed line %q", line)
+			continue
+		}
+		lat, errLat := strconv.ParseFloat(cols[3], 64)
+		lon, errLon := strconv.ParseFloat(cols[4], 64)
+		if errLat != nil || errLon != nil {
+			log.Printf("⚠️ gazetteer: bad coordinates in %q", line)
+			continue
+		}
+		city := City{Name: cols[0], Country: cols[2], Lat: lat, Lon: lon}
+		cities[normalize(city.Name)] = city
+		for _, alias := range strings.Split(cols[1], "|") {
+			if alias = normalize(alias); alias != "" {
+				cities[alias] = city
+			}
+		}
+	}
+}
+
+// Lookup resolves free-text locations such as "Berlin, DE", "Munich, Bavaria,
+// Germany" or "Frankfurt am Main (Hybrid)" against the bundled gazetteer.
+func Lookup(text string) (City, bool) {
+	loadOnce.Do(load)
+
+	text = normalize(text)
+	if text == "" {
+		return City{}, false
+	}
+	if c, ok := cities[text]; ok {
+		return c, true
+	}
+
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == '/' || r == '(' || r == ')' || r == ';' || r == '|'
+	})
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if c, ok := cities[part]; ok {
+			return c, true
+		}
+		// "Berlin Mitte", "Greater Munich Area": try every word window, longest first
+		words := strings.Fields(part)
+		for size := len(words) - 1; size > 0; size-- {
+			for start := 0; start+size <= len(words); start++ {
+				if c, ok := cities[strings.Join(words[start:start+size], " ")]; ok {
+					return c, true
+				}
+			}
+		}
+	}
+	return City{}, false
+}
+
+// LookupCity resolves a seeker's city, optionally disambiguated by country.
+func LookupCity(city, country string) (City, bool) {
+	if c, ok := Lookup(city); ok {
+		return c, true
+	}
+	if country != "" {
+		return Lookup(city + ", " + country)
+	}
+	return City{}, false
+}
+
+const earthRadiusKm = 6371.0
+
+// DistanceKm returns the great-circle distance between two points.
+func DistanceKm(a, b *Point) float64 {
+	if a == nil || b == nil {
+		return math.Inf(1)
+	}
+	lat1, lon1 := a.Lat()*math.Pi/180, a.Lon()*math.Pi/180
+	lat2, lon2 := b.Lat()*math.Pi/180, b.Lon()*math.Pi/180
+	dLat, dLon := lat2-lat1, lon2-lon1
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// RadiusInRadians converts kilometres to the radians used by $centerSphere.
+func RadiusInRadians(km float64) float64 {
+	return km / earthRadiusKm
+}
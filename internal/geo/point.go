@@ -0,0 +1,123 @@
+package geo
+
+import (
+	"context"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Point is a GeoJSON point as stored in MongoDB (coordinates are [lon, lat]).
+type Point struct {
+	Type        string    `bson:"type" json:"type"`
+	Coordinates []float64 `bson:"coordinates" json:"coordinates"`
+}
+
+func NewPoint(lat, lon float64) *Point {
+	return &Point{Type: "Point", Coordinates: []float64{lon, lat}}
+}
+
+func (p *Point) Lat() float64 { return p.Coordinates[1] }
+func (p *Point) Lon() float64 { return p.Coordinates[0] }
+
+// SamePlace reports whether two points, either of which may be nil, are the
+// same place.
+func SamePlace(a, b *Point) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return len(a.Coordinates) == 2 && len(b.Coordinates) == 2 &&
+		a.Coordinates[0] == b.Coordinates[0] && a.Coordinates[1] == b.Coordinates[1]
+}
+
+// Work modes detected on jobs and chosen by seekers.
+const (
+	WorkModeAny    = "any"
+	WorkModeOnsite = "onsite"
+	WorkModeHybrid = "hybrid"
+	WorkModeRemote = "remote"
+)
+
+// ValidWorkModePreference reports whether s can be stored as a seeker preference.
+func ValidWorkModePreference(s string) bool {
+	switch s {
+	case WorkModeAny, WorkModeOnsite, WorkModeHybrid, WorkModeRemote:
+		return true
+	}
+	return false
+}
+
+// DetectWorkMode infers remote/hybrid/onsite from job text (location, job type, ...).
+func DetectWorkMode(texts ...string) string {
+	joined := strings.ToLower(strings.Join(texts, " "))
+	switch {
+	case strings.Contains(joined, "hybrid"):
+		return WorkModeHybrid
+	case strings.Contains(joined, "remote"),
+		strings.Contains(joined, "home office"),
+		strings.Contains(joined, "homeoffice"),
+		strings.Contains(joined, "work from home"):
+		return WorkModeRemote
+	default:
+		return WorkModeOnsite
+	}
+}
+
+// Index2dSphere is the geospatial index on a GeoJSON field.
+func Index2dSphere(field string) mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: field, Value: "2dsphere"}},
+		Options: options.Index().SetName(field + "_2dsphere"),
+	}
+}
+
+// GeocodePendingJobs resolves location text for jobs that have not been
+// geocoded yet and stores geo_location / work_mode on them.
+func GeocodePendingJobs(ctx context.Context, coll *mongo.Collection) (int, error) {
+	cursor, err := coll.Find(ctx,
+		bson.M{"geo_resolved": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"job_id": 1, "location": 1, "job_type": 1, "title": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var models []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var job struct {
+			JobID    string `bson:"job_id"`
+			Location string `bson:"location"`
+			JobType  string `bson:"job_type"`
+			Title    string `bson:"title"`
+		}
+		if err := cursor.Decode(&job); err != nil {
+			continue
+		}
+
+		set := bson.M{
+			"geo_resolved": true,
+			"work_mode":    DetectWorkMode(job.Location, job.JobType, job.Title),
+		}
+		if city, ok := Lookup(job.Location); ok {
+			set["geo_location"] = city.Point()
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"job_id": job.JobID}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	res, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"RAAS/core/config"
 	"RAAS/internal/dto"
+	"RAAS/internal/handlers/repository"
 	"RAAS/internal/models"
 	"RAAS/utils"
 
@@ -160,11 +161,11 @@ func (r *UserRepo) CreateSeeker(input dto.SeekerSignUpInput, hashedPassword stri
 		ProficiencyTest:            1,
 
 		PersonalInfo:                bson.M{},
-		WorkExperiences:             []bson.M{},
-		Academics:                   []bson.M{},
-		PastProjects:                []bson.M{},
-		Certificates:                []bson.M{},
-		Languages:                   []bson.M{},
+		WorkExperiences:             []models.WorkExperience{},
+		Academics:                   []models.Academics{},
+		PastProjects:                []models.PastProject{},
+		Certificates:                []models.Certificate{},
+		Languages:                   []models.Language{},
 		KeySkills:                   []string{},
 
 		PrimaryTitle:                "",
@@ -183,44 +184,7 @@ func (r *UserRepo) CreateSeeker(input dto.SeekerSignUpInput, hashedPassword stri
 	}
 
 	// Create Timeline
-	timeline := models.UserEntryTimeline{
-		AuthUserID: authUserID,
-
-		// Compulsory steps
-		PersonalInfoCompleted:   false,
-		PersonalInfoRequired:    true,
-
-		AcademicsCompleted:      false,
-		AcademicsRequired:       true,
-
-		LanguagesCompleted:      false,
-		LanguagesRequired:       true,
-
-		JobTitlesCompleted:      false,
-		JobTitlesRequired:       true,
-
-		KeySkillsCompleted:      false,
-		KeySkillsRequired:       true,
-
-		// Optional steps
-		WorkExperiencesCompleted: false,
-		WorkExperiencesRequired:  false,
-
-		PastProjectsCompleted:    false,
-		PastProjectsRequired:     false,
-
-		CertificatesCompleted:    false,
-		CertificatesRequired:     false,
-
-		// Overall
-		Completed: false,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
-	}
-
-
-	_, err = r.DB.Collection("user_entry_timelines").InsertOne(ctx, timeline)
-	if err != nil {
+	if err := repository.CreateUserEntryTimeline(ctx, r.DB, authUserID, seeker.SubscriptionTier); err != nil {
 		return fmt.Errorf("user created but failed to create entry timeline: %w", err)
 	}
 
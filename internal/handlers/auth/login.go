@@ -79,7 +79,7 @@ if err != nil {
         return
     }
 
-    completed, next_step, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, user.AuthUserID, "")
+    completed, next_step, err := repository.CompleteOnboardingStep(ctx, db, user.AuthUserID, "")
     if err != nil {
         log.Printf("Timeline update error [SetKeySkills] user=%s: %v", user.AuthUserID, err)
     }
@@ -0,0 +1,205 @@
+package alerts
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/models"
+
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Maximum number of saved searches per user
+const maxSavedSearches = 20
+
+// SavedSearchHandler manages saved job searches and their alert settings
+type SavedSearchHandler struct{}
+
+func NewSavedSearchHandler() *SavedSearchHandler {
+	return &SavedSearchHandler{}
+}
+
+func criteriaFromRequest(req dto.SavedSearchRequest) models.SearchCriteria {
+	return models.SearchCriteria{
+		Q:             strings.TrimSpace(req.Q),
+		JobType:       strings.TrimSpace(req.JobType),
+		Location:      strings.TrimSpace(req.Location),
+		JobLang:       strings.TrimSpace(req.JobLang),
+		Company:       strings.TrimSpace(req.Company),
+		MinMatchScore: req.MinMatchScore,
+	}
+}
+
+// POST /b1/saved-searches
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	coll := db.Collection(models.CollectionSavedSearches)
+
+	var req dto.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	count, err := coll.CountDocuments(c, bson.M{"auth_user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not check saved searches"})
+		return
+	}
+	if count >= maxSavedSearches {
+		c.JSON(http.StatusConflict, gin.H{"error": "Saved search limit reached"})
+		return
+	}
+
+	alertsEnabled := true
+	if req.AlertsEnabled != nil {
+		alertsEnabled = *req.AlertsEnabled
+	}
+	now := time.Now()
+	search := models.SavedSearch{
+		ID:               primitive.NewObjectID(),
+		AuthUserID:       userID,
+		Name:             strings.TrimSpace(req.Name),
+		Criteria:         criteriaFromRequest(req),
+		Frequency:        req.Frequency,
+		AlertsEnabled:    alertsEnabled,
+		UnsubscribeToken: uuid.New().String(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if _, err := coll.InsertOne(c, search); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not save search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"issue": "Search saved successfully", "saved_search": search})
+}
+
+// GET /b1/saved-searches
+func (h *SavedSearchHandler) GetSavedSearches(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	cursor, err := db.Collection(models.CollectionSavedSearches).Find(c,
+		bson.M{"auth_user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching saved searches"})
+		return
+	}
+	defer cursor.Close(c)
+
+	searches := []models.SavedSearch{}
+	if err := cursor.All(c, &searches); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding saved searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"saved_searches": searches})
+}
+
+// PUT /b1/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search id"})
+		return
+	}
+
+	var req dto.SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	set := bson.M{
+		"name":       strings.TrimSpace(req.Name),
+		"criteria":   criteriaFromRequest(req),
+		"frequency":  req.Frequency,
+		"updated_at": time.Now(),
+	}
+	if req.AlertsEnabled != nil {
+		set["alerts_enabled"] = *req.AlertsEnabled
+	}
+
+	var updated models.SavedSearch
+	err = db.Collection(models.CollectionSavedSearches).FindOneAndUpdate(c,
+		bson.M{"_id": id, "auth_user_id": userID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Saved search updated", "saved_search": updated})
+}
+
+// DELETE /b1/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid saved search id"})
+		return
+	}
+
+	res, err := db.Collection(models.CollectionSavedSearches).DeleteOne(c, bson.M{"_id": id, "auth_user_id": userID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+	if res.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+	_, _ = db.Collection(models.CollectionJobAlertDeliveries).DeleteMany(c, bson.M{"saved_search_id": id})
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Saved search deleted"})
+}
+
+// GET /b1/job-alerts/unsubscribe?token=...
+// Public one-click unsubscribe link embedded in alert emails.
+func UnsubscribeHandler(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+
+	token := c.Query("token")
+	if token == "" {
+		c.String(http.StatusBadRequest, "Missing unsubscribe token.")
+		return
+	}
+
+	res, err := db.Collection(models.CollectionSavedSearches).UpdateOne(c,
+		bson.M{"unsubscribe_token": token},
+		bson.M{"$set": bson.M{"alerts_enabled": false, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Something went wrong. Please try again later.")
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.String(http.StatusNotFound, "This unsubscribe link is invalid or has expired.")
+		return
+	}
+
+	c.String(http.StatusOK, "You have been unsubscribed from these job alerts.")
+}
@@ -0,0 +1,62 @@
+package appuser
+
+import (
+	"RAAS/internal/handlers/repository"
+
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultBoardCardsPerColumn = 50
+	maxBoardCardsPerColumn     = 200
+	analyticsTopCompanies      = 20
+)
+
+// GET /b1/api/application-tracker/board?per_column=50
+// Groups every application into pipeline columns with counts.
+func (h *ApplicationTrackerHandler) GetApplicationBoard(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	perColumn := defaultBoardCardsPerColumn
+	if n, err := strconv.Atoi(c.Query("per_column")); err == nil && n > 0 {
+		perColumn = n
+	}
+	if perColumn > maxBoardCardsPerColumn {
+		perColumn = maxBoardCardsPerColumn
+	}
+
+	columns, err := repository.ApplicationBoard(c, db, userID, perColumn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build application board"})
+		return
+	}
+
+	total := 0
+	for _, col := range columns {
+		total += col.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":   total,
+		"columns": columns,
+	})
+}
+
+// GET /b1/api/application-tracker/analytics
+func (h *ApplicationTrackerHandler) GetApplicationAnalytics(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	analytics, err := repository.ApplicationAnalyticsFor(c, db, userID, analyticsTopCompanies)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute application analytics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
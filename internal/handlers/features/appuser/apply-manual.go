@@ -0,0 +1,401 @@
+package appuser
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxImportFileBytes     = 1 << 20 // 1 MB
+	maxImportRows          = 500
+	maxAttachmentFileBytes = 5 << 20 // 5 MB
+)
+
+// ManualApplicationRequest creates a tracker entry for a job applied to elsewhere.
+// Source is free text describing where the user applied (e.g. "LinkedIn").
+type ManualApplicationRequest struct {
+	Company     string `json:"company" binding:"required,max=200"`
+	Title       string `json:"title" binding:"required,max=200"`
+	Link        string `json:"link" binding:"omitempty,url,max=2000"`
+	DateApplied string `json:"date_applied"` // YYYY-MM-DD, defaults to today
+	Source      string `json:"source" binding:"max=100"`
+	Status      string `json:"status"`
+	Note        string `json:"note" binding:"max=2000"`
+}
+
+func validManualStatus(status string) bool {
+	if status == "" {
+		return true
+	}
+	for _, s := range repository.ManualApplicationStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// toManualApplication validates a request and converts it for the repository.
+func toManualApplication(req ManualApplicationRequest) (repository.ManualApplication, error) {
+	m := repository.ManualApplication{
+		Company: strings.TrimSpace(req.Company),
+		Title:   strings.TrimSpace(req.Title),
+		JobLink: strings.TrimSpace(req.Link),
+		Channel: strings.TrimSpace(req.Source),
+		Status:  strings.ToLower(strings.TrimSpace(req.Status)),
+		Note:    req.Note,
+	}
+	if m.Company == "" || m.Title == "" {
+		return m, errors.New("company and title are required")
+	}
+	if m.JobLink != "" {
+		if u, err := url.ParseRequestURI(m.JobLink); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return m, errors.New("link must be an http(s) URL")
+		}
+	}
+	if !validManualStatus(m.Status) {
+		return m, fmt.Errorf("status must be one of %s", strings.Join(repository.ManualApplicationStatuses, ", "))
+	}
+	if d := strings.TrimSpace(req.DateApplied); d != "" {
+		t, err := time.Parse("2006-01-02", d)
+		if err != nil {
+			return m, errors.New("date_applied must be YYYY-MM-DD")
+		}
+		if t.After(time.Now()) {
+			return m, errors.New("date_applied cannot be in the future")
+		}
+		m.AppliedAt = &t
+	}
+	return m, nil
+}
+
+// POST /b1/api/application-tracker/manual
+func (h *ApplicationTrackerHandler) CreateManualApplication(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req ManualApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	m, err := toManualApplication(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if exists, err := repository.ManualApplicationExists(c, db, userID, m.JobLink); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check existing applications"})
+		return
+	} else if exists {
+		c.JSON(http.StatusConflict, gin.H{"error": "An application with this link is already tracked"})
+		return
+	}
+
+	app, err := repository.CreateManualApplication(c, db, userID, m)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create application"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"issue": "Application tracked", "application": app})
+}
+
+// csvColumnAliases maps accepted CSV header names onto request fields.
+var csvColumnAliases = map[string]string{
+	"company":      "company",
+	"employer":     "company",
+	"title":        "title",
+	"job_title":    "title",
+	"position":     "title",
+	"link":         "link",
+	"url":          "link",
+	"job_link":     "link",
+	"date_applied": "date_applied",
+	"applied_at":   "date_applied",
+	"date":         "date_applied",
+	"source":       "source",
+	"channel":      "source",
+	"status":       "status",
+	"note":         "note",
+	"notes":        "note",
+}
+
+type csvRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// POST /b1/api/application-tracker/import (multipart "file")
+// Imports manual applications from a CSV with a header row.
+func (h *ApplicationTrackerHandler) ImportApplicationsCSV(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No CSV file found in request"})
+		return
+	}
+	if fileHeader.Size > maxImportFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "CSV file must be at most 1 MB"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read CSV file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportFileBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read CSV file"})
+		return
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel BOM
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CSV file", "details": err.Error()})
+		return
+	}
+	if len(records) < 2 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV must contain a header row and at least one application"})
+		return
+	}
+	if len(records)-1 > maxImportRows {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("CSV may contain at most %d applications", maxImportRows)})
+		return
+	}
+
+	columns := make(map[string]int)
+	for i, name := range records[0] {
+		key := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), " ", "_"))
+		if field, ok := csvColumnAliases[key]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["company"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header must include company and title columns"})
+		return
+	}
+	if _, ok := columns["title"]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "CSV header must include company and title columns"})
+		return
+	}
+
+	cell := func(record []string, field string) string {
+		if i, ok := columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	imported, skipped := 0, 0
+	rowErrors := []csvRowError{}
+	seenLinks := make(map[string]bool)
+	for n, record := range records[1:] {
+		row := n + 2 // 1-based, after the header
+		req := ManualApplicationRequest{
+			Company:     cell(record, "company"),
+			Title:       cell(record, "title"),
+			Link:        cell(record, "link"),
+			DateApplied: cell(record, "date_applied"),
+			Source:      cell(record, "source"),
+			Status:      cell(record, "status"),
+			Note:        cell(record, "note"),
+		}
+		m, err := toManualApplication(req)
+		if err != nil {
+			rowErrors = append(rowErrors, csvRowError{Row: row, Error: err.Error()})
+			continue
+		}
+
+		if m.JobLink != "" {
+			exists, err := repository.ManualApplicationExists(c, db, userID, m.JobLink)
+			if err != nil {
+				rowErrors = append(rowErrors, csvRowError{Row: row, Error: "failed to check existing applications"})
+				continue
+			}
+			if exists || seenLinks[m.JobLink] {
+				skipped++
+				continue
+			}
+			seenLinks[m.JobLink] = true
+		}
+
+		if _, err := repository.CreateManualApplication(c, db, userID, m); err != nil {
+			rowErrors = append(rowErrors, csvRowError{Row: row, Error: "failed to save application"})
+			continue
+		}
+		imported++
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"imported": imported,
+		"skipped":  skipped,
+		"errors":   rowErrors,
+	})
+}
+
+func validAttachmentKind(kind string) bool {
+	return kind == models.AttachmentKindCV || kind == models.AttachmentKindCoverLetter
+}
+
+// POST /b1/api/application-tracker/:job_id/attachments/:kind (multipart "file")
+// Attaches the CV (kind "cv") or cover letter (kind "cl") PDF that was sent.
+func (h *ApplicationTrackerHandler) UploadAttachment(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	jobID := c.Param("job_id")
+	kind := c.Param("kind")
+
+	if !validAttachmentKind(kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Attachment kind must be cv or cl"})
+		return
+	}
+	if _, err := repository.GetApplication(c, db, userID, jobID); errors.Is(err, repository.ErrApplicationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch application"})
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file found in request"})
+		return
+	}
+	if fileHeader.Size > maxAttachmentFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File must be at most 5 MB"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxAttachmentFileBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Only PDF files are supported"})
+		return
+	}
+
+	now := time.Now()
+	name := filepath.Base(fileHeader.Filename)
+	_, err = db.Collection(models.CollectionAppAttachments).UpdateOne(c,
+		bson.M{"auth_user_id": userID, "job_id": jobID, "kind": kind},
+		bson.M{"$set": models.ApplicationAttachment{
+			AuthUserID: userID,
+			JobID:      jobID,
+			Kind:       kind,
+			FileName:   name,
+			MimeType:   "application/pdf",
+			Data:       data,
+			UploadedAt: now,
+		}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachment"})
+		return
+	}
+
+	meta := models.ApplicationAttachmentMeta{Kind: kind, FileName: name, Size: len(data), UploadedAt: now}
+	appFilter := bson.M{"auth_user_id": userID, "job_id": jobID}
+	// Replace any previous entry of the same kind in one update
+	_, err = db.Collection(models.CollectionSelectedJobApps).UpdateOne(c, appFilter, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"attachments": bson.M{"$concatArrays": bson.A{
+				bson.M{"$filter": bson.M{
+					"input": bson.M{"$ifNull": bson.A{"$attachments", bson.A{}}},
+					"cond":  bson.M{"$ne": bson.A{"$$this.kind", kind}},
+				}},
+				bson.A{bson.M{"$literal": meta}},
+			}},
+			"updated_at": now,
+		}}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attachment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"issue": "Attachment uploaded", "attachment": meta})
+}
+
+// GET /b1/api/application-tracker/:job_id/attachments/:kind
+func (h *ApplicationTrackerHandler) DownloadAttachment(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var att models.ApplicationAttachment
+	err := db.Collection(models.CollectionAppAttachments).FindOne(c, bson.M{
+		"auth_user_id": userID,
+		"job_id":       c.Param("job_id"),
+		"kind":         c.Param("kind"),
+	}).Decode(&att)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, strings.ReplaceAll(att.FileName, `"`, "")))
+	c.Data(http.StatusOK, att.MimeType, att.Data)
+}
+
+// DELETE /b1/api/application-tracker/:job_id/attachments/:kind
+func (h *ApplicationTrackerHandler) DeleteAttachment(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	jobID := c.Param("job_id")
+	kind := c.Param("kind")
+
+	res, err := db.Collection(models.CollectionAppAttachments).DeleteOne(c, bson.M{"auth_user_id": userID, "job_id": jobID, "kind": kind})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete attachment"})
+		return
+	}
+	if res.DeletedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	_, _ = db.Collection(models.CollectionSelectedJobApps).UpdateOne(c,
+		bson.M{"auth_user_id": userID, "job_id": jobID},
+		bson.M{"$pull": bson.M{"attachments": bson.M{"kind": kind}}},
+	)
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Attachment deleted"})
+}
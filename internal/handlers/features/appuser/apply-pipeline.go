@@ -0,0 +1,206 @@
+package appuser
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type UpdateApplicationStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+	Note   string `json:"note" binding:"max=2000"`
+}
+
+type ApplicationNoteRequest struct {
+	Text string `json:"text" binding:"required,max=5000"`
+}
+
+type NextActionRequest struct {
+	NextAction   string     `json:"next_action" binding:"max=500"`
+	NextActionAt *time.Time `json:"next_action_at"`
+}
+
+func nonNilHistory(h []models.StatusChange) []models.StatusChange {
+	if h == nil {
+		return []models.StatusChange{}
+	}
+	return h
+}
+
+func nonNilNotes(n []models.ApplicationNote) []models.ApplicationNote {
+	if n == nil {
+		return []models.ApplicationNote{}
+	}
+	return n
+}
+
+// respondTransition runs a status change and maps pipeline errors to HTTP responses.
+func respondTransition(c *gin.Context, to, note string) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	jobID := c.Param("job_id")
+
+	app, err := repository.TransitionApplication(c, db, userID, jobID, to, strings.TrimSpace(note))
+	var terr *repository.TransitionError
+	switch {
+	case errors.Is(err, repository.ErrApplicationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	case errors.Is(err, repository.ErrApplicationConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "Application was changed by another request, please retry"})
+		return
+	case errors.As(err, &terr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Status change not allowed",
+			"from":    terr.From,
+			"to":      terr.To,
+			"allowed": terr.Allowed,
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "Status updated",
+		"status":          app.Status,
+		"interview_round": app.InterviewRound,
+		"allowed_next":    models.AllowedApplicationTransitions(app.Status),
+		"status_history":  nonNilHistory(app.StatusHistory),
+	})
+}
+
+// PUT /b1/api/application-tracker/:job_id/status
+func (h *ApplicationTrackerHandler) UpdateApplicationStatus(c *gin.Context) {
+	var req UpdateApplicationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !models.IsApplicationStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
+		return
+	}
+	respondTransition(c, req.Status, req.Note)
+}
+
+// POST /b1/api/application-tracker/:job_id/applied
+// Explicitly marks a generated application as sent to the employer.
+func (h *ApplicationTrackerHandler) MarkApplied(c *gin.Context) {
+	var req ApplicationNoteRequest
+	_ = c.ShouldBindJSON(&req) // note is optional
+	respondTransition(c, models.ApplicationStatusApplied, req.Text)
+}
+
+// POST /b1/api/application-tracker/:job_id/notes
+func (h *ApplicationTrackerHandler) AddApplicationNote(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req ApplicationNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Note text is required"})
+		return
+	}
+
+	now := time.Now()
+	note := models.ApplicationNote{ID: primitive.NewObjectID(), Text: strings.TrimSpace(req.Text), CreatedAt: now}
+	res, err := db.Collection(models.CollectionSelectedJobApps).UpdateOne(c,
+		bson.M{"auth_user_id": userID, "job_id": c.Param("job_id")},
+		bson.M{"$push": bson.M{"notes": note}, "$set": bson.M{"updated_at": now}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add note"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"issue": "Note added", "note": note})
+}
+
+// DELETE /b1/api/application-tracker/:job_id/notes/:note_id
+func (h *ApplicationTrackerHandler) DeleteApplicationNote(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	noteID, err := primitive.ObjectIDFromHex(c.Param("note_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid note id"})
+		return
+	}
+
+	res, err := db.Collection(models.CollectionSelectedJobApps).UpdateOne(c,
+		bson.M{"auth_user_id": userID, "job_id": c.Param("job_id"), "notes._id": noteID},
+		bson.M{"$pull": bson.M{"notes": bson.M{"_id": noteID}}, "$set": bson.M{"updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete note"})
+		return
+	}
+	if res.MatchedCount == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Note not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Note deleted"})
+}
+
+// PUT /b1/api/application-tracker/:job_id/next-action
+// Sets or clears (empty body fields) the next follow-up for an application.
+func (h *ApplicationTrackerHandler) SetNextAction(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req NextActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	update := bson.M{"$set": bson.M{"updated_at": time.Now()}}
+	unset := bson.M{}
+	if action := strings.TrimSpace(req.NextAction); action != "" {
+		update["$set"].(bson.M)["next_action"] = action
+	} else {
+		unset["next_action"] = ""
+	}
+	if req.NextActionAt != nil {
+		update["$set"].(bson.M)["next_action_at"] = req.NextActionAt.UTC()
+	} else {
+		unset["next_action_at"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	var app models.SelectedJobApplication
+	err := db.Collection(models.CollectionSelectedJobApps).FindOneAndUpdate(c,
+		bson.M{"auth_user_id": userID, "job_id": c.Param("job_id")},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&app)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update next action"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"issue":          "Next action updated",
+		"next_action":    app.NextAction,
+		"next_action_at": app.NextActionAt,
+	})
+}
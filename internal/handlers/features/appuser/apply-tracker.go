@@ -1,14 +1,15 @@
 package appuser
 
 import (
+    "RAAS/internal/handlers/repository"
     "RAAS/internal/models"
 
 	"context"
 	"net/http"
     "time"
     "fmt"
-    "log"
     "strconv"
+    "log"
 
 
 	"github.com/gin-gonic/gin"
@@ -33,9 +34,21 @@ type ApplicationTrackerResponse struct {
 	Skills       string 	`json:"skills"`
 	KeySkills    []string 	`json:"key_skills"`
 	MatchScore 	 float64   	`bson:"match_score" json:"match_score"` 
+	ATSScore     *int       `json:"ats_score,omitempty"` // see /b1/internal/generate-resume/analysis
 	Status       string 	`json:"status"`
 	Source       string 	`json:"source"`
     SelectedDate time.Time  `json:"selected_date"`
+
+    InterviewRound int                      `json:"interview_round,omitempty"`
+    AllowedNext    []string                 `json:"allowed_next"`
+    StatusHistory  []models.StatusChange    `json:"status_history"`
+    Notes          []models.ApplicationNote `json:"notes"`
+    NextAction     string                   `json:"next_action,omitempty"`
+    NextActionAt   *time.Time               `json:"next_action_at,omitempty"`
+    AppliedAt      *time.Time               `json:"applied_at,omitempty"`
+    JobLink        string                   `json:"job_link,omitempty"`
+    Channel        string                   `json:"channel,omitempty"`
+    Attachments    []models.ApplicationAttachmentMeta `json:"attachments,omitempty"`
 }
 
 func (h *ApplicationTrackerHandler) GetApplicationTracker(c *gin.Context) {
@@ -55,109 +68,105 @@ func (h *ApplicationTrackerHandler) GetApplicationTracker(c *gin.Context) {
     selColl := db.Collection("selected_job_applications")
     seekerColl := db.Collection("seekers")
     matchScoreColl := db.Collection("match_scores")
-    internalJobColl := db.Collection("jobs")
-    externalJobColl := db.Collection("external_jobs")
+    jobStore := repository.NewJobStore(db)
 
     // 2️⃣ Build filter
+    // Generated applications only count once the link was viewed; manual and saved ones always show
     filter := bson.M{
         "auth_user_id":           userID,
-        "cv_generated":           true,
-        "view_link":              true,
         "status":                 bson.M{"$ne": "deleted"},
+        "$or": bson.A{
+            bson.M{"cv_generated": true, "view_link": true},
+            bson.M{"source": models.ApplicationSourceManual},
+            bson.M{"status": models.ApplicationStatusSaved},
+        },
     }
     if statusParam, ok := c.GetQuery("status"); ok {
-        filter["status"] = statusParam
+        filter["status"] = models.NormalizeApplicationStatus(statusParam)
     }
     if companyParam, ok := c.GetQuery("company"); ok {
         filter["company"] = companyParam
     }
 
-    total, err := selColl.CountDocuments(context.TODO(), filter)
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications"})
-        return
-    }
-
-    cursor, err := selColl.Find(
-        context.TODO(),
-        filter,
-        options.Find().SetSort(bson.D{{Key: "selected_date", Value: -1}}),
-        options.Find().SetSkip(int64(skip)),
-        options.Find().SetLimit(int64(size)),
-    )
-    if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
-        return
-    }
-    defer cursor.Close(context.TODO())
+    cursorMode, pageCursor, cursorLimit := repository.CursorRequest(c)
 
     var apps []models.SelectedJobApplication
-    if err := cursor.All(context.TODO(), &apps); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode applications"})
-        return
-    }
-
-    // 3️⃣ Transition pending→applied and increment counters
-    for i := range apps {
-        if apps[i].Status != "pending" {
-            continue
+    var total int64
+    var cursorPage repository.CursorPage
+    var err error
+    if cursorMode {
+        apps, cursorPage, err = repository.FindPageByCursor[models.SelectedJobApplication](c, selColl, filter, "selected_date", pageCursor, cursorLimit)
+        if err == repository.ErrCursorMismatch {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+            return
         }
-
-        _, err := selColl.UpdateOne(
-            context.TODO(),
-            bson.M{"auth_user_id": userID, "job_id": apps[i].JobID},
-            bson.M{"$set": bson.M{"status": "applied"}},
-        )
         if err != nil {
-            log.Printf("❌ Failed to update application status for job %s: %v\n", apps[i].JobID, err)
-            continue
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+            return
+        }
+    } else {
+        total, err = selColl.CountDocuments(context.TODO(), filter)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications"})
+            return
         }
 
-        _, err = seekerColl.UpdateOne(
+        cursor, err := selColl.Find(
             context.TODO(),
-            bson.M{"auth_user_id": userID},
-            bson.M{"$inc": bson.M{
-                "total_applications":           1,
-                "weekly_applications_count":    1,
-            }},
+            filter,
+            options.Find().SetSort(bson.D{{Key: "selected_date", Value: -1}}),
+            options.Find().SetSkip(int64(skip)),
+            options.Find().SetLimit(int64(size)),
         )
         if err != nil {
-            log.Printf("❌ Failed to increment seeker counters for user %s: %v\n", userID, err)
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
+            return
         }
+        defer cursor.Close(context.TODO())
 
-        apps[i].Status = "applied"
+        if err := cursor.All(context.TODO(), &apps); err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode applications"})
+            return
+        }
     }
 
     // 4️⃣ Load seeker key skills
     var seeker models.Seeker
     _ = seekerColl.FindOne(context.TODO(), bson.M{"auth_user_id": userID}).Decode(&seeker)
 
+    // ATS scores of the generated CVs
+    jobIDs := make([]string, 0, len(apps))
+    for _, app := range apps {
+        jobIDs = append(jobIDs, app.JobID)
+    }
+    atsScores, err := repository.ATSScores(context.TODO(), db, userID, jobIDs)
+    if err != nil {
+        log.Printf("⚠️ Failed to load ATS scores of %s: %v", userID, err)
+    }
+
     // 5️⃣ Build response
     resp := make([]ApplicationTrackerResponse, 0, len(apps))
     for _, app := range apps {
         var title, company, location, jobTitle, jobDesc, skills string
 
-        if app.Source == "external" {
-            var ext struct {
-                Title, Company, Description, Location, JobTitle, Skills string
-            }
-            if err := externalJobColl.FindOne(context.TODO(), bson.M{"job_id": app.JobID}).Decode(&ext); err != nil {
-                continue
-            }
-            title, company, jobDesc, location, jobTitle, skills = ext.Title, ext.Company, ext.Description, ext.Location, ext.JobTitle, ext.Skills
+        if app.Source == models.ApplicationSourceManual {
+            title, company, jobTitle = app.Title, app.Company, app.Title
         } else {
-            var intJob struct {
-                Title, Company, Location, JobTitle, JobDescription, Skills string
-            }
-            if err := internalJobColl.FindOne(context.TODO(), bson.M{"job_id": app.JobID}).Decode(&intJob); err != nil {
+            job, err := jobStore.GetFrom(context.TODO(), app.JobID, app.Source)
+            if err != nil {
                 continue
             }
-            title, company, jobDesc, location, jobTitle, skills = intJob.Title, intJob.Company, intJob.JobDescription, intJob.Location, intJob.JobTitle, intJob.Skills
+            title, company, jobDesc, location, jobTitle, skills = job.Title, job.Company, job.JobDescription, job.Location, job.JobTitle, job.Skills
         }
 
         var match struct{ MatchScore float64 `bson:"match_score"` }
         _ = matchScoreColl.FindOne(context.TODO(), bson.M{"auth_user_id": userID, "job_id": app.JobID}).Decode(&match)
 
+        var atsScore *int
+        if score, ok := atsScores[app.JobID]; ok {
+            atsScore = &score
+        }
+
         resp = append(resp, ApplicationTrackerResponse{
             JobID:        app.JobID,
             Title:        title,
@@ -168,10 +177,30 @@ func (h *ApplicationTrackerHandler) GetApplicationTracker(c *gin.Context) {
             Skills:       skills,
             KeySkills:    seeker.KeySkills,
             MatchScore:   match.MatchScore,
-            Status:       app.Status,
+            ATSScore:     atsScore,
+            Status:       models.NormalizeApplicationStatus(app.Status),
             Source:       app.Source,
             SelectedDate: app.SelectedDate,
+
+            InterviewRound: app.InterviewRound,
+            AllowedNext:    models.AllowedApplicationTransitions(app.Status),
+            StatusHistory:  nonNilHistory(app.StatusHistory),
+            Notes:          nonNilNotes(app.Notes),
+            NextAction:     app.NextAction,
+            NextActionAt:   app.NextActionAt,
+            AppliedAt:      app.AppliedAt,
+            JobLink:        app.JobLink,
+            Channel:        app.Channel,
+            Attachments:    app.Attachments,
+        })
+    }
+
+    if cursorMode {
+        c.JSON(http.StatusOK, gin.H{
+            "pagination":   repository.CursorPagination(c, cursorPage),
+            "applications": resp,
         })
+        return
     }
 
     // 6️⃣ Pagination metadata
@@ -197,76 +226,3 @@ func (h *ApplicationTrackerHandler) GetApplicationTracker(c *gin.Context) {
         "applications": resp,
     })
 }
-
-type UpdateApplicationStatusRequest struct {
-	Status string `json:"status" binding:"required,oneof=interview selected rejected deleted"`
-}
-
-func (h *ApplicationTrackerHandler) UpdateApplicationStatus(c *gin.Context) {
-	db := c.MustGet("db").(*mongo.Database)
-	selColl := db.Collection("selected_job_applications")
-	userID := c.MustGet("userID").(string)
-	jobID := c.Param("job_id")
-
-	var req UpdateApplicationStatusRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status value"})
-		return
-	}
-
-	// Update the status
-	filter := bson.M{"auth_user_id": userID, "job_id": jobID}
-	update := bson.M{"$set": bson.M{"status": req.Status}}
-
-	res, err := selColl.UpdateOne(c, filter, update)
-	if err != nil || res.MatchedCount == 0 {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update status"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"message": "Status updated", "status": req.Status})
-}
-
-
-func (h *ApplicationTrackerHandler) GetCVAndCL(c *gin.Context) {
-    db := c.MustGet("db").(*mongo.Database)
-    userID := c.MustGet("userID").(string)
-
-    // ✅ Path parameter: job_id
-    jobID := c.Param("job_id")
-    if jobID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job_id in URL"})
-        return
-    }
-
-    cvColl := db.Collection("cv")
-    clColl := db.Collection("cover_letters")
-
-    var cvDoc models.CVData
-    var clDoc models.CoverLetterData
-
-    cvErr := cvColl.FindOne(c, bson.M{
-        "auth_user_id": userID,
-        "job_id":       jobID,
-    }).Decode(&cvDoc)
-    clErr := clColl.FindOne(c, bson.M{
-        "auth_user_id": userID,
-        "job_id":       jobID,
-    }).Decode(&clDoc)
-
-    if cvErr != nil || clErr != nil {
-        c.JSON(http.StatusNotFound, gin.H{
-            "error":    "CV or Cover Letter not found",
-            "cv_error": cvErr.Error(),
-            "cl_error": clErr.Error(),
-        })
-        return
-    }
-
-    c.JSON(http.StatusOK, gin.H{
-        "cv_data":   cvDoc.CVData,
-        "cv_format": cvDoc.CvFormat,
-        "cl_data":   clDoc.CLData,
-        "cl_format": clDoc.ClFormat,
-    })
-}
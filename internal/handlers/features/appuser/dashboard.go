@@ -245,7 +245,7 @@ func (h *SeekerProfileHandler) buildChecklist(c context.Context, db *mongo.Datab
 }
 func (h *SeekerProfileHandler) buildMiniJobs(db *mongo.Database, userID string) dto.MiniNewJobsResponse {
     const maxMiniJobs = 3
-    cutoffDate := time.Now().AddDate(0, 0, -14)
+    cutoffDate := repository.PostedSince(repository.DefaultPostedWithinDays)
     // Step 1: Fetch top match scores for the user
     matchScoreFilter := bson.M{
         "auth_user_id": userID,
@@ -279,8 +279,9 @@ func (h *SeekerProfileHandler) buildMiniJobs(db *mongo.Database, userID string)
         // Fetch job
         var job models.Job
         err := db.Collection("jobs").FindOne(context.TODO(), bson.M{
-            "job_id":       match.JobID,
-            "posted_date": bson.M{"$gte": cutoffDate.Format("2006-01-02")},
+            "job_id":      match.JobID,
+            "is_active":   bson.M{"$ne": false},
+            "posted_date": bson.M{"$gte": cutoffDate},
         }).Decode(&job)
         if err != nil {
             continue
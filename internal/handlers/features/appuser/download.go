@@ -0,0 +1,151 @@
+package appuser
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+	"RAAS/internal/render"
+
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GET /b1/api/application-tracker/:job_id/download?type=cv|cover_letter&format=pdf|docx&template=
+// Renders the generated CV or cover letter of an application.
+func (h *ApplicationTrackerHandler) DownloadDocument(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	jobID := c.Param("job_id")
+
+	kind := c.DefaultQuery("type", render.KindCV)
+	if kind != render.KindCV && kind != render.KindCoverLetter {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be cv or cover_letter"})
+		return
+	}
+	format := c.DefaultQuery("format", render.FormatPDF)
+
+	src, err := repository.LoadRenderSource(c, db, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found"})
+		return
+	}
+	file, err := repository.RenderGeneratedDocument(c, db, src, userID, jobID, kind, format, c.Query("template"))
+	if err != nil {
+		renderError(c, kind, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.Name))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// GET /b1/api/application-tracker/download-all/:job_id?format=pdf|docx|json
+// Returns the CV and cover letter of an application as one ZIP. With
+// format=json the stored generation data is returned instead.
+func (h *ApplicationTrackerHandler) GetCVAndCL(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	// ✅ Path parameter: job_id
+	jobID := c.Param("job_id")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job_id in URL"})
+		return
+	}
+	format := c.DefaultQuery("format", render.FormatPDF)
+	if format == "json" {
+		getCVAndCLData(c, db, userID, jobID)
+		return
+	}
+
+	src, err := repository.LoadRenderSource(c, db, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found"})
+		return
+	}
+
+	var files []render.File
+	for _, kind := range []string{render.KindCV, render.KindCoverLetter} {
+		file, err := repository.RenderGeneratedDocument(c, db, src, userID, jobID, kind, format, "")
+		if errors.Is(err, repository.ErrDocumentNotFound) {
+			continue
+		}
+		if err != nil {
+			renderError(c, kind, err)
+			return
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV or Cover Letter not found"})
+		return
+	}
+
+	archive, err := render.Zip(files...)
+	if err != nil {
+		log.Printf("❌ Failed to build ZIP for job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build download"})
+		return
+	}
+	name := render.FileName(src.Profile.Name, "Application", jobID) + ".zip"
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// getCVAndCLData returns the stored CV and cover letter JSON.
+func getCVAndCLData(c *gin.Context, db *mongo.Database, userID, jobID string) {
+	filter := bson.M{"auth_user_id": userID, "job_id": jobID}
+
+	var cvDoc models.CVData
+	var clDoc models.CoverLetterData
+	cvErr := db.Collection(models.CollectionCV).FindOne(c, filter).Decode(&cvDoc)
+	clErr := db.Collection(models.CollectionCoverLetters).FindOne(c, filter).Decode(&clDoc)
+	if cvErr != nil || clErr != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":    "CV or Cover Letter not found",
+			"cv_error": errorText(cvErr),
+			"cl_error": errorText(clErr),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cv_data":   cvDoc.CVData,
+		"cv_format": cvDoc.CvFormat,
+		"cl_data":   clDoc.CLData,
+		"cl_format": clDoc.ClFormat,
+	})
+}
+
+func renderError(c *gin.Context, kind string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found", "issue": "Generate the " + documentLabel(kind) + " for this job first."})
+	case errors.Is(err, repository.ErrUnknownTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "allowed_templates": render.TemplateNames(kind)})
+	case errors.Is(err, render.ErrUnsupportedFormat):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be pdf or docx"})
+	default:
+		log.Printf("❌ Failed to render %s: %v", kind, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render document"})
+	}
+}
+
+func documentLabel(kind string) string {
+	if kind == render.KindCoverLetter {
+		return "cover letter"
+	}
+	return "CV"
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
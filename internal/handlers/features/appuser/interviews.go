@@ -0,0 +1,378 @@
+package appuser
+
+import (
+	"RAAS/core/config"
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+	"RAAS/utils"
+
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultInterviewMinutes = 60
+
+type InterviewHandler struct{}
+
+func NewInterviewHandler() *InterviewHandler {
+	return &InterviewHandler{}
+}
+
+// InterviewRequest creates or updates an interview. StartsAt is either RFC 3339
+// or a local "2006-01-02T15:04" time in Timezone (default: the user's
+// preferred timezone).
+type InterviewRequest struct {
+	StartsAt        string `json:"starts_at" binding:"required"`
+	Timezone        string `json:"timezone"`
+	DurationMinutes int    `json:"duration_minutes" binding:"omitempty,min=5,max=600"`
+	Round           int    `json:"round" binding:"omitempty,min=1,max=20"`
+	Interviewer     string `json:"interviewer" binding:"max=200"`
+	Location        string `json:"location" binding:"max=500"`
+	VideoLink       string `json:"video_link" binding:"omitempty,url"`
+	Notes           string `json:"notes" binding:"max=2000"`
+}
+
+// resolveInterviewTime parses the requested start time in the requested or preferred timezone.
+func resolveInterviewTime(c *gin.Context, db *mongo.Database, userID string, req InterviewRequest) (time.Time, string, error) {
+	tz := strings.TrimSpace(req.Timezone)
+	if tz == "" {
+		if prefs, err := repository.GetUserPreferences(c, db, userID); err == nil {
+			tz = prefs.Timezone
+		}
+	}
+	if tz == "" {
+		tz = "UTC"
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("unknown timezone %q", tz)
+	}
+
+	if t, err := time.Parse(time.RFC3339, req.StartsAt); err == nil {
+		return t.UTC(), tz, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04", req.StartsAt, loc)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("starts_at must be RFC 3339 or YYYY-MM-DDTHH:MM")
+	}
+	return t.UTC(), tz, nil
+}
+
+// POST /b1/api/application-tracker/:job_id/interviews
+func (h *InterviewHandler) CreateInterview(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req InterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+
+	app, err := repository.GetApplication(c, db, userID, c.Param("job_id"))
+	if errors.Is(err, repository.ErrApplicationNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch application"})
+		return
+	}
+	if models.NormalizeApplicationStatus(app.Status) != models.ApplicationStatusInterview {
+		c.JSON(http.StatusConflict, gin.H{"error": "Application is not in interview stage"})
+		return
+	}
+
+	startsAt, tz, err := resolveInterviewTime(c, db, userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	round := req.Round
+	if round == 0 {
+		round = app.InterviewRound
+	}
+	if round == 0 {
+		round = 1
+	}
+	duration := req.DurationMinutes
+	if duration == 0 {
+		duration = defaultInterviewMinutes
+	}
+
+	title, company := repository.ApplicationJobInfo(c, db, app)
+	now := time.Now()
+	event := models.InterviewEvent{
+		ID:              primitive.NewObjectID(),
+		AuthUserID:      userID,
+		JobID:           app.JobID,
+		Round:           round,
+		Title:           title,
+		Company:         company,
+		StartsAt:        startsAt,
+		DurationMinutes: duration,
+		Timezone:        tz,
+		Interviewer:     strings.TrimSpace(req.Interviewer),
+		Location:        strings.TrimSpace(req.Location),
+		VideoLink:       strings.TrimSpace(req.VideoLink),
+		Notes:           strings.TrimSpace(req.Notes),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if _, err := db.Collection(models.CollectionInterviewEvents).InsertOne(c, event); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule interview"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"issue": "Interview scheduled", "interview": event})
+}
+
+// GET /b1/api/interviews?job_id=...&upcoming=true
+func (h *InterviewHandler) ListInterviews(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	filter := bson.M{"auth_user_id": userID}
+	if jobID := c.Query("job_id"); jobID != "" {
+		filter["job_id"] = jobID
+	}
+	if c.Query("upcoming") == "true" {
+		filter["starts_at"] = bson.M{"$gte": time.Now()}
+		filter["cancelled"] = false
+	}
+
+	cursor, err := db.Collection(models.CollectionInterviewEvents).Find(c, filter,
+		options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}}),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching interviews"})
+		return
+	}
+	defer cursor.Close(c)
+
+	events := []models.InterviewEvent{}
+	if err := cursor.All(c, &events); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding interviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"interviews": events})
+}
+
+func (h *InterviewHandler) findInterview(c *gin.Context) (*models.InterviewEvent, bool) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interview id"})
+		return nil, false
+	}
+
+	var event models.InterviewEvent
+	err = db.Collection(models.CollectionInterviewEvents).FindOne(c, bson.M{"_id": id, "auth_user_id": userID}).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching interview"})
+		return nil, false
+	}
+	return &event, true
+}
+
+// PUT /b1/api/interviews/:id
+func (h *InterviewHandler) UpdateInterview(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	event, ok := h.findInterview(c)
+	if !ok {
+		return
+	}
+
+	var req InterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload", "details": err.Error()})
+		return
+	}
+	if req.Timezone == "" {
+		req.Timezone = event.Timezone
+	}
+	startsAt, tz, err := resolveInterviewTime(c, db, userID, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	set := bson.M{
+		"starts_at":   startsAt,
+		"timezone":    tz,
+		"interviewer": strings.TrimSpace(req.Interviewer),
+		"location":    strings.TrimSpace(req.Location),
+		"video_link":  strings.TrimSpace(req.VideoLink),
+		"notes":       strings.TrimSpace(req.Notes),
+		"updated_at":  time.Now(),
+	}
+	if req.DurationMinutes > 0 {
+		set["duration_minutes"] = req.DurationMinutes
+	}
+	if req.Round > 0 {
+		set["round"] = req.Round
+	}
+	update := bson.M{"$set": set}
+	if !startsAt.Equal(event.StartsAt) {
+		// Rescheduled: remind again for the new date
+		update["$unset"] = bson.M{"reminder_sent_at": ""}
+	}
+
+	var updated models.InterviewEvent
+	err = db.Collection(models.CollectionInterviewEvents).FindOneAndUpdate(c,
+		bson.M{"_id": event.ID, "auth_user_id": userID},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update interview"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Interview updated", "interview": updated})
+}
+
+// DELETE /b1/api/interviews/:id
+// Interviews are cancelled rather than removed so subscribed calendars drop them.
+func (h *InterviewHandler) CancelInterview(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+
+	event, ok := h.findInterview(c)
+	if !ok {
+		return
+	}
+
+	_, err := db.Collection(models.CollectionInterviewEvents).UpdateOne(c,
+		bson.M{"_id": event.ID},
+		bson.M{"$set": bson.M{"cancelled": true, "updated_at": time.Now()}},
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel interview"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Interview cancelled"})
+}
+
+// GET /b1/api/interviews/:id/ics
+func (h *InterviewHandler) GetInterviewICS(c *gin.Context) {
+	event, ok := h.findInterview(c)
+	if !ok {
+		return
+	}
+
+	ics := utils.BuildICS("Interview", []utils.ICSEvent{repository.InterviewICSEvent(*event)})
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, utils.ICSFilename("interview", event.StartsAt)))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
+
+func calendarFeedURL(token string) string {
+	return fmt.Sprintf("%s/b1/calendar/%s/interviews.ics", config.Cfg.Project.FrontendBaseUrl, token)
+}
+
+func calendarFeedResponse(token string) gin.H {
+	feedURL := calendarFeedURL(token)
+	webcal := strings.Replace(strings.Replace(feedURL, "https://", "webcal://", 1), "http://", "webcal://", 1)
+	return gin.H{"url": feedURL, "webcal_url": webcal}
+}
+
+// GET /b1/api/interviews/calendar-feed
+// Returns the user's subscribable feed URL, creating it on first use.
+func (h *InterviewHandler) GetCalendarFeed(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var feed models.CalendarFeed
+	err := db.Collection(models.CollectionCalendarFeeds).FindOneAndUpdate(c,
+		bson.M{"auth_user_id": userID},
+		bson.M{"$setOnInsert": bson.M{"token": uuid.New().String(), "created_at": time.Now()}},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(feed.Token))
+}
+
+// POST /b1/api/interviews/calendar-feed/rotate
+// Invalidates the old feed URL, e.g. after it was shared by mistake.
+func (h *InterviewHandler) RotateCalendarFeed(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	token := uuid.New().String()
+	_, err := db.Collection(models.CollectionCalendarFeeds).UpdateOne(c,
+		bson.M{"auth_user_id": userID},
+		bson.M{"$set": bson.M{"token": token, "created_at": time.Now()}},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rotate calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, calendarFeedResponse(token))
+}
+
+// GET /b1/calendar/:token/interviews.ics
+// Public feed that calendar apps poll; the token authenticates the user.
+func CalendarFeedHandler(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+
+	var feed models.CalendarFeed
+	err := db.Collection(models.CollectionCalendarFeeds).FindOne(c, bson.M{"token": c.Param("token")}).Decode(&feed)
+	if err == mongo.ErrNoDocuments {
+		c.String(http.StatusNotFound, "Calendar not found.")
+		return
+	}
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Something went wrong. Please try again later.")
+		return
+	}
+
+	// Past 90 days onwards, so recent interviews remain visible
+	cursor, err := db.Collection(models.CollectionInterviewEvents).Find(c,
+		bson.M{"auth_user_id": feed.AuthUserID, "starts_at": bson.M{"$gte": time.Now().AddDate(0, 0, -90)}},
+		options.Find().SetSort(bson.D{{Key: "starts_at", Value: 1}}),
+	)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "Something went wrong. Please try again later.")
+		return
+	}
+	defer cursor.Close(c)
+
+	var events []models.InterviewEvent
+	if err := cursor.All(c, &events); err != nil {
+		c.String(http.StatusInternalServerError, "Something went wrong. Please try again later.")
+		return
+	}
+
+	icsEvents := make([]utils.ICSEvent, 0, len(events))
+	for _, e := range events {
+		icsEvents = append(icsEvents, repository.InterviewICSEvent(e))
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(utils.BuildICS("Interviews", icsEvents)))
+}
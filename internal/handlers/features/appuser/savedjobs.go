@@ -3,7 +3,9 @@ package appuser
 import (
     "fmt"
     "net/http"
-    "strconv"
+    "net/url"
+    "strings"
+    "time"
 
     "RAAS/internal/dto"
     "RAAS/internal/handlers/repository"
@@ -15,6 +17,13 @@ import (
     "go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+    maxSavedJobTags      = 20
+    maxSavedJobTagLength = 40
+    maxSavedJobNotes     = 2000
+    maxSavedJobBulk      = 100
+)
+
 // SavedJobsHandler handles saving and retrieving saved jobs
 type SavedJobsHandler struct{}
 
@@ -29,18 +38,35 @@ func (h *SavedJobsHandler) SaveJob(c *gin.Context) {
     userID := c.MustGet("userID").(string)
 
     var payload struct {
-        JobID  string `json:"job_id" binding:"required"`
+        JobID    string   `json:"job_id" binding:"required"`
+        Notes    string   `json:"notes"`
+        Tags     []string `json:"tags"`
+        Folder   string   `json:"folder"`
+        Priority string   `json:"priority"`
     }
     if err := c.ShouldBindJSON(&payload); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
         return
     }
 
+    if payload.Priority == "" {
+        payload.Priority = models.SavedJobPriorityNormal
+    }
+    tags, errMsg := validateSavedJobDetails(payload.Notes, payload.Tags, payload.Priority)
+    if errMsg != "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+        return
+    }
+
     coll := db.Collection("saved_jobs")
     _, err := coll.InsertOne(c, models.SavedJob{
         AuthUserID: userID,
-
         JobID:      payload.JobID,
+        SavedAt:    time.Now(),
+        Notes:      strings.TrimSpace(payload.Notes),
+        Tags:       tags,
+        Folder:     strings.TrimSpace(payload.Folder),
+        Priority:   payload.Priority,
     })
     if err != nil {
         if mongo.IsDuplicateKeyError(err) {
@@ -54,6 +80,73 @@ func (h *SavedJobsHandler) SaveJob(c *gin.Context) {
     c.JSON(http.StatusOK, gin.H{"issue": "Job saved successfully"})
 }
 
+// PUT /saved-jobs/:job_id
+// Only the fields present in the body are changed.
+func (h *SavedJobsHandler) UpdateSavedJob(c *gin.Context) {
+    db := c.MustGet("db").(*mongo.Database)
+    userID := c.MustGet("userID").(string)
+    jobID := c.Param("job_id")
+
+    var payload struct {
+        Notes    *string   `json:"notes"`
+        Tags     *[]string `json:"tags"`
+        Folder   *string   `json:"folder"`
+        Priority *string   `json:"priority"`
+    }
+    if err := c.ShouldBindJSON(&payload); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+        return
+    }
+
+    now := time.Now()
+    set := bson.M{"updated_at": now}
+    if payload.Notes != nil {
+        if len(*payload.Notes) > maxSavedJobNotes {
+            c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Notes must be at most %d characters", maxSavedJobNotes)})
+            return
+        }
+        set["notes"] = strings.TrimSpace(*payload.Notes)
+    }
+    if payload.Tags != nil {
+        tags, errMsg := validateSavedJobDetails("", *payload.Tags, models.SavedJobPriorityNormal)
+        if errMsg != "" {
+            c.JSON(http.StatusBadRequest, gin.H{"error": errMsg})
+            return
+        }
+        set["tags"] = tags
+    }
+    if payload.Folder != nil {
+        set["folder"] = strings.TrimSpace(*payload.Folder)
+    }
+    if payload.Priority != nil {
+        if !models.IsSavedJobPriority(*payload.Priority) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be one of low, normal, high"})
+            return
+        }
+        set["priority"] = *payload.Priority
+    }
+
+    var updated models.SavedJob
+    err := db.Collection("saved_jobs").FindOneAndUpdate(c,
+        bson.M{"auth_user_id": userID, "job_id": jobID},
+        bson.M{"$set": set},
+        options.FindOneAndUpdate().SetReturnDocument(options.After),
+    ).Decode(&updated)
+    if err == mongo.ErrNoDocuments {
+        c.JSON(http.StatusNotFound, gin.H{"error": "Saved job not found"})
+        return
+    }
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update saved job"})
+        return
+    }
+    if updated.Tags == nil {
+        updated.Tags = []string{}
+    }
+
+    c.JSON(http.StatusOK, gin.H{"issue": "Saved job updated", "saved_job": updated})
+}
+
 // DELETE /saved-jobs/:job_id
 func (h *SavedJobsHandler) DeleteSavedJob(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
@@ -75,58 +168,303 @@ func (h *SavedJobsHandler) DeleteSavedJob(c *gin.Context) {
 
     c.JSON(http.StatusCreated, gin.H{"issue": "Saved Job Deleted"})
 }
+
+type savedJobsBulkRequest struct {
+    JobIDs []string `json:"job_ids" binding:"required"`
+}
+
+// bindSavedJobsBulk reads and validates the job IDs of a bulk action.
+func bindSavedJobsBulk(c *gin.Context) ([]string, bool) {
+    var req savedJobsBulkRequest
+    if err := c.ShouldBindJSON(&req); err != nil || len(req.JobIDs) == 0 {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "job_ids must be a non-empty list"})
+        return nil, false
+    }
+    if len(req.JobIDs) > maxSavedJobBulk {
+        c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("At most %d job_ids per request", maxSavedJobBulk)})
+        return nil, false
+    }
+    return req.JobIDs, true
+}
+
+// POST /saved-jobs/bulk-delete
+func (h *SavedJobsHandler) BulkDeleteSavedJobs(c *gin.Context) {
+    db := c.MustGet("db").(*mongo.Database)
+    userID := c.MustGet("userID").(string)
+
+    jobIDs, ok := bindSavedJobsBulk(c)
+    if !ok {
+        return
+    }
+
+    res, err := db.Collection("saved_jobs").DeleteMany(c, bson.M{
+        "auth_user_id": userID,
+        "job_id":       bson.M{"$in": jobIDs},
+    })
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved jobs"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"issue": "Saved jobs deleted", "deleted": res.DeletedCount})
+}
+
+// POST /saved-jobs/move-to-tracker
+// Each job is moved into the application tracker in the "saved" status.
+func (h *SavedJobsHandler) MoveSavedJobsToTracker(c *gin.Context) {
+    db := c.MustGet("db").(*mongo.Database)
+    userID := c.MustGet("userID").(string)
+
+    jobIDs, ok := bindSavedJobsBulk(c)
+    if !ok {
+        return
+    }
+
+    results := make([]gin.H, 0, len(jobIDs))
+    moved := 0
+    for _, jobID := range jobIDs {
+        result := "moved"
+        _, err := repository.MoveSavedJobToTracker(c, db, userID, jobID)
+        switch err {
+        case nil:
+            moved++
+        case repository.ErrSavedJobNotFound:
+            result = "not_saved"
+        case repository.ErrSavedJobRemoved:
+            result = models.SavedJobWarningRemoved
+        case repository.ErrAlreadyTracked:
+            result = "already_tracked"
+        default:
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to move saved jobs", "results": results})
+            return
+        }
+        results = append(results, gin.H{"job_id": jobID, "result": result})
+    }
+
+    c.JSON(http.StatusOK, gin.H{"issue": "Saved jobs moved to tracker", "moved": moved, "results": results})
+}
+
+// GET /saved-jobs/tags
+// Lists the tags and folders in use, for filter menus.
+func (h *SavedJobsHandler) GetSavedJobTags(c *gin.Context) {
+    db := c.MustGet("db").(*mongo.Database)
+    userID := c.MustGet("userID").(string)
+
+    coll := db.Collection("saved_jobs")
+    filter := bson.M{"auth_user_id": userID}
+    tags, err := coll.Distinct(c, "tags", filter)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+        return
+    }
+    folders, err := coll.Distinct(c, "folder", bson.M{"auth_user_id": userID, "folder": bson.M{"$nin": bson.A{nil, ""}}})
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch folders"})
+        return
+    }
+
+    c.JSON(http.StatusOK, gin.H{"tags": tags, "folders": folders})
+}
+
+// GET /saved-jobs?tag=&folder=&priority=&offset=&limit=
+// Most recently saved first.
 func (h *SavedJobsHandler) GetSavedJobs(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
     userID := c.MustGet("userID").(string)
+    savedColl := db.Collection("saved_jobs")
 
-    // 1️⃣ Fetch saved job IDs
-    savedIDs, err := repository.FetchSavedJobIDs(c, db.Collection("saved_jobs"), userID)
+    // 1️⃣ Build filter
+    filter := bson.M{"auth_user_id": userID}
+    querySuffix := ""
+    if tag := strings.TrimSpace(c.Query("tag")); tag != "" {
+        filter["tags"] = tag
+        querySuffix += "&tag=" + url.QueryEscape(tag)
+    }
+    if folder := strings.TrimSpace(c.Query("folder")); folder != "" {
+        filter["folder"] = folder
+        querySuffix += "&folder=" + url.QueryEscape(folder)
+    }
+    if priority := c.Query("priority"); priority != "" {
+        if !models.IsSavedJobPriority(priority) {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be one of low, normal, high"})
+            return
+        }
+        filter["priority"] = priority
+        querySuffix += "&priority=" + priority
+    }
+
+    seeker, err := repository.GetSeekerData(db, userID)
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching saved job IDs"})
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seeker data"})
         return
     }
-    if len(savedIDs) == 0 {
-        c.JSON(http.StatusNoContent, gin.H{"jobs": []dto.JobDTO{}})
+
+    // Cursor mode
+    if cursorMode, cur, limit := repository.CursorRequest(c); cursorMode {
+        saved, page, err := repository.FindPageByCursor[models.SavedJob](c, savedColl, filter, "saved_at", cur, limit)
+        if err == repository.ErrCursorMismatch {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching saved jobs"})
+            return
+        }
+
+        jobs, err := savedJobDTOs(c, db, userID, seeker, saved, 1)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching jobs"})
+            return
+        }
+        c.JSON(http.StatusOK, gin.H{
+            "pagination": repository.CursorPagination(c, page),
+            "jobs":       jobs,
+        })
         return
     }
 
-    // 2️⃣ Pagination
-    page := 1
-    size := 10
-    if p, err := strconv.Atoi(c.DefaultQuery("page", "1")); err == nil && p > 0 {
-        page = p
+    // 2️⃣ Offset pagination
+    pagination := c.MustGet("pagination").(gin.H)
+    offset := pagination["offset"].(int)
+    limit := pagination["limit"].(int)
+
+    total, err := savedColl.CountDocuments(c, filter)
+    if err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error counting saved jobs"})
+        return
     }
-    if s, err := strconv.Atoi(c.DefaultQuery("size", "10")); err == nil && s > 0 {
-        size = s
+    if total == 0 {
+        c.JSON(http.StatusNoContent, gin.H{"jobs": []dto.SavedJobDTO{}})
+        return
     }
-    offset := (page - 1) * size
 
-    // 3️⃣ Fetch job documents
-    cursor, err := db.Collection("jobs").Find(c,
-        bson.M{"job_id": bson.M{"$in": savedIDs}},
-        options.Find().SetSkip(int64(offset)).SetLimit(int64(size)),
+    // 3️⃣ Fetch the page of saved jobs
+    cursor, err := savedColl.Find(c, filter,
+        options.Find().
+            SetSort(bson.D{{Key: "saved_at", Value: -1}, {Key: "_id", Value: -1}}).
+            SetSkip(int64(offset)).
+            SetLimit(int64(limit)),
     )
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching jobs"})
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching saved jobs"})
+        return
+    }
+    var saved []models.SavedJob
+    if err := cursor.All(c, &saved); err != nil {
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching saved jobs"})
         return
     }
-    defer cursor.Close(c)
 
-    // 4️⃣ Load seeker data (for skills)
-    seeker, err := repository.GetSeekerData(db, userID)
+    // 4️⃣ Build DTOs with warnings
+    jobs, err := savedJobDTOs(c, db, userID, seeker, saved, uint(offset+1))
     if err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seeker data"})
+        c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching jobs"})
         return
     }
 
-    // 5️⃣ Build JobDTO list
-    var jobs []dto.JobDTO
-    index := uint(offset + 1)
-    for cursor.Next(c) {
-        var job models.Job
-        if err := cursor.Decode(&job); err != nil {
+    // 5️⃣ Build pagination metadata
+    nextPage := ""
+    if int64(offset+limit) < total {
+        nextPage = fmt.Sprintf("/b1/saved-jobs?offset=%d&limit=%d%s", offset+limit, limit, querySuffix)
+    }
+    prevPage := ""
+    if offset > 0 {
+        prevOffset := offset - limit
+        if prevOffset < 0 {
+            prevOffset = 0
+        }
+        prevPage = fmt.Sprintf("/b1/saved-jobs?offset=%d&limit=%d%s", prevOffset, limit, querySuffix)
+    }
+
+    c.JSON(http.StatusOK, gin.H{
+        "pagination": gin.H{
+            "total":    total,
+            "next":     nextPage,
+            "prev":     prevPage,
+            "current":  (offset / limit) + 1,
+            "per_page": limit,
+        },
+        "jobs": jobs,
+    })
+}
+
+// validateSavedJobDetails checks user-supplied saved job fields and returns
+// the cleaned tags, or an error message.
+func validateSavedJobDetails(notes string, tags []string, priority string) ([]string, string) {
+    if len(notes) > maxSavedJobNotes {
+        return nil, fmt.Sprintf("Notes must be at most %d characters", maxSavedJobNotes)
+    }
+    if !models.IsSavedJobPriority(priority) {
+        return nil, "Priority must be one of low, normal, high"
+    }
+
+    cleaned := []string{}
+    seen := map[string]bool{}
+    for _, tag := range tags {
+        tag = strings.TrimSpace(tag)
+        if tag == "" || seen[strings.ToLower(tag)] {
+            continue
+        }
+        if len(tag) > maxSavedJobTagLength {
+            return nil, fmt.Sprintf("Tags must be at most %d characters", maxSavedJobTagLength)
+        }
+        seen[strings.ToLower(tag)] = true
+        cleaned = append(cleaned, tag)
+    }
+    if len(cleaned) > maxSavedJobTags {
+        return nil, fmt.Sprintf("At most %d tags per saved job", maxSavedJobTags)
+    }
+    return cleaned, ""
+}
+
+// savedJobDTOs joins saved jobs with their postings, numbering them from
+// index. Postings that were removed keep their saved details and a warning.
+func savedJobDTOs(c *gin.Context, db *mongo.Database, userID string, seeker models.Seeker, saved []models.SavedJob, index uint) ([]dto.SavedJobDTO, error) {
+    jobIDs := make([]string, 0, len(saved))
+    for _, s := range saved {
+        jobIDs = append(jobIDs, s.JobID)
+    }
+
+    cursor, err := db.Collection("jobs").Find(c, bson.M{"job_id": bson.M{"$in": jobIDs}})
+    if err != nil {
+        return nil, err
+    }
+    var found []models.Job
+    if err := cursor.All(c, &found); err != nil {
+        return nil, err
+    }
+    byID := make(map[string]*models.Job, len(found))
+    for i := range found {
+        byID[found[i].JobID] = &found[i]
+    }
+
+    jobs := []dto.SavedJobDTO{}
+    for _, s := range saved {
+        tags := s.Tags
+        if tags == nil {
+            tags = []string{}
+        }
+        priority := s.Priority
+        if priority == "" {
+            priority = models.SavedJobPriorityNormal
+        }
+        item := dto.SavedJobDTO{
+            JobDTO:   dto.JobDTO{Source: "saved", ID: index, JobID: s.JobID, UserSkills: seeker.KeySkills},
+            SavedAt:  s.SavedAt,
+            Notes:    s.Notes,
+            Tags:     tags,
+            Folder:   s.Folder,
+            Priority: priority,
+        }
+        index++
+
+        job, ok := byID[s.JobID]
+        if !ok {
+            item.Warning = repository.SavedJobWarning(nil)
+            jobs = append(jobs, item)
             continue
         }
+        item.Warning = repository.SavedJobWarning(job)
 
         score := repository.GetMatchScoreForJob(c, db, userID, job.JobID)
         isSelected := repository.IsJobSelected(c, db, userID, job.JobID)
@@ -139,51 +477,22 @@ func (h *SavedJobsHandler) GetSavedJobs(c *gin.Context) {
                 Decode(&selected)
         }
 
-        jobs = append(jobs, dto.JobDTO{
-            Source:        "saved",
-            ID:            index,
-            JobID:         job.JobID,
-            Title:         job.Title,
-            Company:       job.Company,
-            Location:      job.Location,
-            PostedDate:    job.PostedDate,
-            Processed:     job.Processed,
-            JobType:       job.JobType,
-            Skills:        job.Skills,
-            UserSkills:    seeker.KeySkills,
-            MatchScore:    score,
-            Description:   job.JobDescription,
-            JobLang:       job.JobLang,
-            JobTitle:      job.JobTitle,
-            Selected:      isSelected,
-            LinkViewed:    selected.ViewLink,
-            CvGenerated:   selected.CvGenerated,
-            ClGenerated:   selected.CoverLetterGenerated,
-        })
-        index++
-    }
-
-    // 6️⃣ Build pagination metadata
-    total := len(savedIDs)
-    totalPages := (total + size - 1) / size
-    next, prev := "", ""
-    if page < totalPages {
-        next = fmt.Sprintf("/saved-jobs?page=%d&size=%d", page+1, size)
-    }
-    if page > 1 {
-        prev = fmt.Sprintf("/saved-jobs?page=%d&size=%d", page-1, size)
+        item.Title = job.Title
+        item.Company = job.Company
+        item.Location = job.Location
+        item.PostedDate = job.PostedDate.Format("2006-01-02")
+        item.Processed = job.Processed
+        item.JobType = job.JobType
+        item.Skills = job.Skills
+        item.MatchScore = score
+        item.Description = job.JobDescription
+        item.JobLang = job.JobLang
+        item.JobTitle = job.JobTitle
+        item.Selected = isSelected
+        item.LinkViewed = selected.ViewLink
+        item.CvGenerated = selected.CvGenerated
+        item.ClGenerated = selected.CoverLetterGenerated
+        jobs = append(jobs, item)
     }
-
-    // 7️⃣ Return response
-    c.JSON(http.StatusOK, gin.H{
-        "pagination": gin.H{
-            "total":       total,
-            "per_page":    size,
-            "current":     page,
-            "total_pages": totalPages,
-            "next":        next,
-            "prev":        prev,
-        },
-        "jobs": jobs,
-    })
+    return jobs, nil
 }
@@ -5,6 +5,7 @@ import (
     "net/http"
     "time"
 
+    "RAAS/internal/handlers/repository"
     "RAAS/internal/models"
 
     "github.com/gin-gonic/gin"
@@ -38,6 +39,24 @@ func (h *SelectedJobHandler) GetSelectedJobApplications(c *gin.Context) {
     ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
     defer cancel()
 
+    // Cursor mode: most recently selected first, wrapped in the pagination envelope
+    if cursorMode, cur, limit := repository.CursorRequest(c); cursorMode {
+        results, page, err := repository.FindPageByCursor[models.SelectedJobApplication](ctx, coll, filter, "selected_date", cur, limit)
+        if err == repository.ErrCursorMismatch {
+            c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "DB query error"})
+            return
+        }
+        c.JSON(http.StatusOK, gin.H{
+            "pagination":   repository.CursorPagination(c, page),
+            "applications": results,
+        })
+        return
+    }
+
     cursor, err := coll.Find(ctx, filter)
     if err != nil {
         c.JSON(http.StatusInternalServerError, gin.H{"error": "DB query error"})
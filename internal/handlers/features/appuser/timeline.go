@@ -6,6 +6,7 @@ import (
 	"RAAS/internal/handlers/features/jobs"
 	"RAAS/internal/handlers/repository"
 
+	"errors"
 	"fmt"
 	"net/http"
 	"log"
@@ -31,11 +32,9 @@ func GetNextEntryStep() gin.HandlerFunc {
 			return
 		}
 		
-		collection := db.Collection("user_entry_timelines")
-		var timeline models.UserEntryTimeline
-		err := collection.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&timeline)
+		progress, err := repository.UpdateOnboardingProgress(ctx, db, userID, "")
 		if err != nil {
-			if err == mongo.ErrNoDocuments {
+			if errors.Is(err, mongo.ErrNoDocuments) {
 				fmt.Println("Error fetching timeline: User not found")
 				c.JSON(http.StatusNotFound, gin.H{"error": "Timeline not found"})
 			} else {
@@ -45,43 +44,14 @@ func GetNextEntryStep() gin.HandlerFunc {
 			return
 		}
 
-		log.Printf("🧭 Updated Timeline for user %s: %+v", userID, timeline)
-		steps := []struct {
-			Name      string
-			Completed bool
-			Required  bool
-		}{
-			{"personal_infos", timeline.PersonalInfoCompleted, timeline.PersonalInfoRequired},
-			{"work_experiences", timeline.WorkExperiencesCompleted, timeline.WorkExperiencesRequired},
-			{"academics", timeline.AcademicsCompleted, timeline.AcademicsRequired},
-			{"past_projects", timeline.PastProjectsCompleted, timeline.PastProjectsRequired},
-			{"certificates", timeline.CertificatesCompleted, timeline.CertificatesRequired},
-			{"languages", timeline.LanguagesCompleted, timeline.LanguagesRequired},
-			{"preferred_job_titles", timeline.JobTitlesCompleted, timeline.JobTitlesRequired},
-			{"key_skills", timeline.KeySkillsCompleted, timeline.KeySkillsRequired},
-		}
-	
-		for _, step := range steps {
-			if step.Required && !step.Completed {
-				c.JSON(http.StatusOK, gin.H{
-					"completed": false,
-					"next_step": step.Name,
-				})
-				return
-			}
-		}
-
-		if !timeline.Completed {
-			update := bson.M{
-				"$set": bson.M{"completed": true},
-			}
-
-			_, err := collection.UpdateOne(c, bson.M{"auth_user_id": userID}, update)
-			if err != nil {
-				fmt.Println("Error updating timeline:", err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to mark as completed"})
-				return
-			}
+		if !progress.Completed {
+			c.JSON(http.StatusOK, gin.H{
+				"completed": false,
+				"next_step": models.LegacyStepName(progress.NextStep),
+				"flow":      progress.Flow,
+				"steps":     progress.Steps,
+			})
+			return
 		}
 
 		    // 7️⃣ Load seeker profile
@@ -109,6 +79,62 @@ func GetNextEntryStep() gin.HandlerFunc {
 		c.JSON(http.StatusOK, gin.H{
 			"completed": true,
 			"next_step": nil,
+			"flow":      progress.Flow,
+			"steps":     progress.Steps,
+		})
+	}
+}
+
+// CompleteEntryStep marks an optional or manual onboarding step as done, e.g.
+// when the user skips it. Required data-entry steps complete when their data
+// is saved.
+func CompleteEntryStep() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		db := c.MustGet("db").(*mongo.Database)
+		userID := c.MustGet("userID").(string)
+		stepKey := c.Param("step")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		flow, err := repository.UserOnboardingFlow(ctx, db, userID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Timeline not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch timeline"})
+			}
+			return
+		}
+
+		step, ok := flow.Step(stepKey)
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Step is not part of your onboarding"})
+			return
+		}
+		if step.Required && !step.Manual {
+			c.JSON(http.StatusConflict, gin.H{"error": "This step completes when its details are saved"})
+			return
+		}
+
+		progress, err := repository.UpdateOnboardingProgress(ctx, db, userID, step.Key)
+		if err != nil {
+			log.Printf("❌ Timeline update error [CompleteEntryStep] user=%s: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update progress"})
+			return
+		}
+
+		if progress.Completed {
+			if err := jobs.StartJobMatchScoreCalculation(c, db, userID); err != nil {
+				log.Printf("Error starting job match process: %v", err)
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"issue":     "Step completed",
+			"completed": progress.Completed,
+			"next_step": models.LegacyStepName(progress.NextStep),
+			"steps":     progress.Steps,
 		})
 	}
 }
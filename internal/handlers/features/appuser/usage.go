@@ -0,0 +1,84 @@
+package appuser
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type UsageHandler struct{}
+
+func NewUsageHandler() *UsageHandler {
+	return &UsageHandler{}
+}
+
+// GET /b1/usage
+// Lists the quota ledger of the current billing period with totals per
+// quota. Free accounts have no billing period and see their whole ledger.
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var seeker models.Seeker
+	if err := db.Collection(models.CollectionSeekers).FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found"})
+			return
+		}
+		log.Printf("❌ Failed to load seeker %s for usage: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load usage"})
+		return
+	}
+
+	from, to := seeker.SubscriptionIntervalStart, seeker.SubscriptionIntervalEnd
+	entries, err := repository.ListUsage(c, db, userID, from, to)
+	if err != nil {
+		log.Printf("❌ Failed to list usage of %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load usage"})
+		return
+	}
+
+	limits := getLimits(seeker.SubscriptionTier, seeker.SubscriptionPeriod)
+	internal := dto.UsageQuota{Resource: models.UsageInternalApplication, Limit: limits.Internal,
+		Remaining: seeker.InternalApplications, Credit: seeker.InternalApplicationCredit}
+	external := dto.UsageQuota{Resource: models.UsageExternalApplication, Limit: limits.External,
+		Remaining: seeker.ExternalApplications, Credit: seeker.ExternalApplicationCredit}
+	for _, e := range entries {
+		q := &internal
+		if e.Resource == models.UsageExternalApplication ||
+			(e.Resource == models.UsageCoverLetterRegeneration && e.Source == "external") {
+			q = &external
+		}
+		if e.Resource == models.UsageCoverLetterRegeneration && e.Status == models.UsageCommitted {
+			q.Regenerations++
+		}
+		switch e.Status {
+		case models.UsageCommitted:
+			q.Committed += e.Amount
+		case models.UsageReserved:
+			q.Reserved += e.Amount
+		case models.UsageReleased:
+			q.Released += e.Amount
+		}
+	}
+
+	resp := dto.UsageResponse{
+		SubscriptionTier: seeker.SubscriptionTier,
+		Quotas:           []dto.UsageQuota{internal, external},
+		Entries:          entries,
+	}
+	if !from.IsZero() {
+		resp.PeriodStart = &from
+	}
+	if !to.IsZero() {
+		resp.PeriodEnd = &to
+	}
+	c.JSON(http.StatusOK, resp)
+}
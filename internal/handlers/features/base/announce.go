@@ -10,6 +10,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 
+	"RAAS/internal/handlers/repository"
 	"RAAS/internal/models"
 )
 func AnnouncementHandler(c *gin.Context) {
@@ -42,6 +43,23 @@ func AnnouncementHandler(c *gin.Context) {
 	}
 
 	// Otherwise → paginated active announcements list
+	if cursorMode, cur, limit := repository.CursorRequest(c); cursorMode {
+		announcements, page, err := repository.FindPageByCursor[models.Announcement](c, db.Collection("announcements"), filter, "created_at", cur, limit)
+		if err == repository.ErrCursorMismatch {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching announcements"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"pagination":    repository.CursorPagination(c, page),
+			"announcements": announcements,
+		})
+		return
+	}
+
 	pagination := c.MustGet("pagination").(gin.H)
 	offset := pagination["offset"].(int)
 	limit := pagination["limit"].(int)
@@ -4,6 +4,7 @@ import (
 
 	"RAAS/internal/models"
 	"RAAS/internal/dto"
+	"RAAS/internal/handlers/repository"
 
 	"context"
 	"net/http"
@@ -156,6 +157,24 @@ func (h *ExamPortalHandler) GetFilteredExamResults(c *gin.Context) {
 		filter["auth_user_id"] = authUserID
 	}
 
+	// Cursor mode: newest submissions first
+	if cursorMode, cur, limit := repository.CursorRequest(c); cursorMode {
+		results, page, err := repository.FindPageByCursor[models.ExamResult](c, db.Collection("exam_results"), filter, "submitted_at", cur, limit)
+		if err == repository.ErrCursorMismatch {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exam results", "details": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"pagination": repository.CursorPagination(c, page),
+			"results":    results,
+		})
+		return
+	}
+
 	// Query MongoDB
 	cursor, err := db.Collection("exam_results").Find(context.TODO(), filter)
 	if err != nil {
@@ -4,14 +4,14 @@ import (
 
     "RAAS/internal/handlers/repository"
     "RAAS/internal/models"
-    "fmt"
+    "log"
+    "strings"
   
     "net/http"
 
     "github.com/gin-gonic/gin"
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/mongo"
-    "go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type InternalCoverLetterHandler struct{}
@@ -20,13 +20,12 @@ func NewInternalCoverLetterHandler() *InternalCoverLetterHandler {
     return &InternalCoverLetterHandler{}
 }
 
+// POST /b1/internal/generate-cover-letter
+// Queues the cover letter generation and answers 202 with the generation job to follow.
 func (h *InternalCoverLetterHandler) PostCoverLetter(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	clColl := db.Collection("cover_letters")
 	selColl := db.Collection("selected_job_applications")
-	jobColl := db.Collection("jobs")
-	seekerColl := db.Collection("seekers")
-	authUserColl := db.Collection("auth_users")
 
 	userID := c.MustGet("userID").(string)
 
@@ -34,11 +33,21 @@ func (h *InternalCoverLetterHandler) PostCoverLetter(c *gin.Context) {
 		JobID 		string `json:"job_id" binding:"required"`
 		JobLang 	string `json:"job_language" binding:"required"`
 		ClFormat	string	`json:"cl_format" binding:"required"`
+		Options		*models.CoverLetterOptions	`json:"options"`
+		CallbackURL	string	`json:"callback_url"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job_id"})
 		return
 	}
+	opts := models.CoverLetterOptions{}
+	if req.Options != nil {
+		opts = *req.Options
+	}
+	if err := normalizeCoverLetterOptions(&opts, req.JobLang); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid options", "issue": err.Error()})
+		return
+	}
 
 	// Step 1: If already generated, return cached CL
 	var selApp struct {
@@ -53,99 +62,100 @@ func (h *InternalCoverLetterHandler) PostCoverLetter(c *gin.Context) {
 		}
 	}
 
-	// Step 2: Upsert selection record FIRST (this checks quota and deducts if first-time)
-    if err := upsertSelectedJobApp(db, userID, req.JobID, "cover_letter", "internal"); err != nil {
+	// Step 2: Check quota early; it is reserved when the generation is queued
+    if err := repository.CheckUsage(c, db, userID, req.JobID, models.GenerationKindCoverLetter, "internal"); err != nil {
         c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "issue": "Limit Reached"})
         return
     }
-	// Step 3: Fetch job, seeker and user details
-	var job models.Job
-	if err := jobColl.FindOne(c, bson.M{"job_id": req.JobID}).Decode(&job); err != nil {
+	// Step 3: Fetch the job
+	job, err := repository.NewJobStore(db).GetFrom(c, req.JobID, models.ApplicationSourceInternal)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
 
+	// Step 4: Build ML API payload
+	payload := map[string]interface{}{
+		"user_details":    generationUserDetails(c, db, userID),
+		"job_description": jobDescription(job.Job),
+		"cl_data":         coverLetterRequest(&opts),
+	}
+
+	// Step 5: Queue the generation; quota is reserved now and refunded if it fails
+	enqueueGeneration(c, db, models.GenerationJob{
+		AuthUserID: userID,
+		JobID:      req.JobID,
+		Kind:       models.GenerationKindCoverLetter,
+		Source:     "internal",
+		Payloads:   map[string]interface{}{"cover_letter": payload},
+		Params:     map[string]string{"cl_format": req.ClFormat},
+		Options:    &opts,
+	}, req.CallbackURL)
+}
+
+// generationUserDetails builds the seeker part of a generation request.
+func generationUserDetails(c *gin.Context, db *mongo.Database, userID string) map[string]interface{} {
 	var seeker models.Seeker
-	_ = seekerColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker)
+	_ = db.Collection("seekers").FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker)
 
 	var authUser models.AuthUser
-	_ = authUserColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&authUser)
-
-	pInfo, _ := repository.GetPersonalInfo(&seeker)
+	_ = db.Collection("auth_users").FindOne(c, bson.M{"auth_user_id": userID}).Decode(&authUser)
+
+	var name string
+	var city, linkedIn *string
+	if pInfo, err := repository.GetPersonalInfo(&seeker); err == nil && pInfo != nil {
+		name = pInfo.FirstName
+		if pInfo.SecondName != nil {
+			name += " " + *pInfo.SecondName
+		}
+		city, linkedIn = pInfo.City, pInfo.LinkedInProfile
+	}
 	we, _ := repository.GetWorkExperience(&seeker)
 	certs, _ := repository.GetCertificates(&seeker)
 	langs, _ := repository.GetLanguages(&seeker)
 	pastProjects, _ := repository.GetPastProjects(&seeker)
 	education, _ := repository.GetAcademics(&seeker)
 
-	// Step 4: Build ML API payload
-	payload := map[string]interface{}{
-		"user_details": map[string]interface{}{
-			"name":               fmt.Sprintf("%s %s", pInfo.FirstName, *pInfo.SecondName),
-			"designation":        seeker.PrimaryTitle,
-			"address":            pInfo.City,
-			"contact":            authUser.Phone,
-			"email":              authUser.Email,
-			"portfolio":          /*pInfo.ExternalLinks*/"",
-			"linkedin":           pInfo.LinkedInProfile,
-			"tools":              "VsCode",
-			"skills":             seeker.KeySkills,
-			"education":          education,
-			"experience_summary": we,
-			"past_projects":      pastProjects,
-			"certifications":     certs,
-			"languages":          langs,
-		},
-		"job_description": map[string]interface{}{
-			"job_title":       job.JobTitle,
-			"title":           job.Title,
-			"company":         job.Company,
-			"location":        job.Location,
-			"job_type":        job.JobType,
-			"link":            job.Link,
-			"description":     job.JobDescription,
-			"responsibilities": "",
-			"qualifications":   "",
-			"skills":           job.Skills,
-			"benefits":         "",
-		},
-		"cl_data": map[string]string{"language": req.JobLang, "spec": ""},
-	}
-
-	// Step 5: Call ML API
-	clResp, err := CallCoverLetterAPI(payload)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("ML API failed: %v", err)})
-		return
+	return map[string]interface{}{
+		"name":               strings.TrimSpace(name),
+		"designation":        seeker.PrimaryTitle,
+		"address":            city,
+		"contact":            authUser.Phone,
+		"email":              authUser.Email,
+		"portfolio":          /*pInfo.ExternalLinks*/"",
+		"linkedin":           linkedIn,
+		"tools":              "VsCode",
+		"skills":             seeker.KeySkills,
+		"education":          education,
+		"experience_summary": we,
+		"past_projects":      pastProjects,
+		"certifications":     certs,
+		"languages":          langs,
 	}
+}
 
-	// Step 6: Save generated cover letter
-	_, err = clColl.InsertOne(c, bson.M{
-		"auth_user_id": userID,
-		"job_id":       req.JobID,
-		"cl_data":      clResp,
-		"cl_format":req.ClFormat,
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save cover letter"})
-		return
+// jobDescription builds the job part of a generation request.
+func jobDescription(job models.Job) map[string]interface{} {
+	return map[string]interface{}{
+		"job_title":        job.JobTitle,
+		"title":            job.Title,
+		"company":          job.Company,
+		"location":         job.Location,
+		"job_type":         job.JobType,
+		"link":             job.Link,
+		"description":      job.JobDescription,
+		"responsibilities": "",
+		"qualifications":   "",
+		"skills":           job.Skills,
+		"benefits":         "",
 	}
-
-	// Step 7: Decrease daily CL quota
-	seekerColl.UpdateOne(c, bson.M{"auth_user_id": userID}, bson.M{"$inc": bson.M{"daily_generatable_coverletter": -1}})
-
-	// Step 8: Return response
-	c.JSON(http.StatusOK, gin.H{
-		"job_id":  req.JobID,
-		"cl_data": clResp,
-		"cl_format":req.ClFormat,
-	})
 }
 
 
+// PUT /b1/internal/generate-cover-letter
+// Saves an edit of the cover letter as a new version.
 func (h *InternalCoverLetterHandler) PutCoverLetter(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
-    clColl := db.Collection("cover_letters")
     userID := c.MustGet("userID").(string)
 
     var req struct {	
@@ -159,15 +169,12 @@ func (h *InternalCoverLetterHandler) PutCoverLetter(c *gin.Context) {
         return
     }
 
-    opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-    filter := bson.M{"auth_user_id": userID, "job_id": req.JobID}
-    update := bson.M{"$set": bson.M{"cl_data": req.CLData,"cl_format":req.ClFormat}}
-
-    var updated models.CoverLetterData
-    if err := clColl.FindOneAndUpdate(c, filter, update, opts).Decode(&updated); err != nil {
-        if err == mongo.ErrNoDocuments {
+    version, err := repository.UpdateDocument(c, db, userID, req.JobID, models.DocumentKindCoverLetter, req.CLData, req.ClFormat, 0)
+    if err != nil {
+        if err == repository.ErrDocumentNotFound {
             c.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found"})
         } else {
+            log.Printf("❌ Failed to update cover letter for job %s: %v", req.JobID, err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Update error"})
         }
         return
@@ -175,8 +182,9 @@ func (h *InternalCoverLetterHandler) PutCoverLetter(c *gin.Context) {
 
     c.JSON(http.StatusOK, gin.H{
     "job_id":  req.JobID,
-    "cl_data": updated.CLData,
+    "cl_data": version.Data,
 	"cl_format": req.ClFormat,
+    "version": version.Version,
     })
 }
 
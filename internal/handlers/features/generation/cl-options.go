@@ -0,0 +1,93 @@
+package generation
+
+import (
+	"RAAS/internal/models"
+
+	"fmt"
+	"strings"
+)
+
+const (
+	maxEmphasis       = 5
+	maxEmphasisLength = 150
+	maxInstructions   = 500
+)
+
+// coverLetterTones describe each tone to the ML API.
+var coverLetterTones = map[string]string{
+	"formal":       "formal and reserved",
+	"professional": "professional and matter-of-fact",
+	"friendly":     "warm and friendly while staying professional",
+	"enthusiastic": "enthusiastic and energetic",
+	"confident":    "confident and assertive",
+}
+
+// coverLetterLengths are the target word counts of each length.
+var coverLetterLengths = map[string]int{
+	"short":  200,
+	"medium": 300,
+	"long":   400,
+}
+
+// normalizeCoverLetterOptions trims and checks the options. The language
+// falls back to the job language of the request.
+func normalizeCoverLetterOptions(opts *models.CoverLetterOptions, language string) error {
+	opts.Tone = strings.ToLower(strings.TrimSpace(opts.Tone))
+	if _, ok := coverLetterTones[opts.Tone]; opts.Tone != "" && !ok {
+		return fmt.Errorf("unknown tone %q", opts.Tone)
+	}
+	opts.Length = strings.ToLower(strings.TrimSpace(opts.Length))
+	if _, ok := coverLetterLengths[opts.Length]; opts.Length != "" && !ok {
+		return fmt.Errorf("unknown length %q", opts.Length)
+	}
+
+	var emphasis []string
+	for _, e := range opts.Emphasis {
+		if e = strings.TrimSpace(e); e == "" {
+			continue
+		}
+		if len(e) > maxEmphasisLength {
+			return fmt.Errorf("emphasis entries are limited to %d characters", maxEmphasisLength)
+		}
+		emphasis = append(emphasis, e)
+	}
+	if len(emphasis) > maxEmphasis {
+		return fmt.Errorf("at most %d emphasis entries are allowed", maxEmphasis)
+	}
+	opts.Emphasis = emphasis
+
+	opts.Instructions = strings.TrimSpace(opts.Instructions)
+	if len(opts.Instructions) > maxInstructions {
+		return fmt.Errorf("instructions are limited to %d characters", maxInstructions)
+	}
+	if opts.Language = strings.TrimSpace(opts.Language); opts.Language == "" {
+		opts.Language = language
+	}
+	return nil
+}
+
+// coverLetterSpec renders the options as the spec of the ML request.
+func coverLetterSpec(opts *models.CoverLetterOptions) string {
+	if opts == nil {
+		return ""
+	}
+	var parts []string
+	if opts.Tone != "" {
+		parts = append(parts, fmt.Sprintf("Tone: %s.", coverLetterTones[opts.Tone]))
+	}
+	if opts.Length != "" {
+		parts = append(parts, fmt.Sprintf("Length: about %d words.", coverLetterLengths[opts.Length]))
+	}
+	if len(opts.Emphasis) > 0 {
+		parts = append(parts, fmt.Sprintf("Emphasise: %s.", strings.Join(opts.Emphasis, "; ")))
+	}
+	if opts.Instructions != "" {
+		parts = append(parts, "Additional instructions: "+opts.Instructions)
+	}
+	return strings.Join(parts, " ")
+}
+
+// coverLetterRequest is the cl_data part of the ML request.
+func coverLetterRequest(opts *models.CoverLetterOptions) map[string]string {
+	return map[string]string{"language": opts.Language, "spec": coverLetterSpec(opts)}
+}
@@ -0,0 +1,125 @@
+package generation
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// POST /b1/internal/generate-cover-letter/regenerate
+// Queues another take on an existing cover letter of an internal or external
+// job. The result becomes a new version; the previous ones stay in the
+// history. Without options those of the current version are reused, and a
+// missing language keeps the current one. Costs a share of an application.
+func (h *InternalCoverLetterHandler) RegenerateCoverLetter(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req struct {
+		JobID       string                     `json:"job_id" binding:"required"`
+		ClFormat    string                     `json:"cl_format"`
+		Options     *models.CoverLetterOptions `json:"options"`
+		CallbackURL string                     `json:"callback_url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job_id"})
+		return
+	}
+
+	var current models.CoverLetterData
+	if err := db.Collection(models.CollectionCoverLetters).FindOne(c, bson.M{"auth_user_id": userID, "job_id": req.JobID}).Decode(&current); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found", "issue": "Generate the cover letter first"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+		return
+	}
+	var app models.SelectedJobApplication
+	if err := db.Collection(models.CollectionSelectedJobApps).FindOne(c, bson.M{"auth_user_id": userID, "job_id": req.JobID}).Decode(&app); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	// Options and language of the current version
+	var previous models.CoverLetterOptions
+	if current.Version > 0 {
+		if v, err := repository.GetDocumentVersion(c, db, userID, req.JobID, models.DocumentKindCoverLetter, current.Version); err == nil && v.Options != nil {
+			previous = *v.Options
+		}
+	}
+
+	job, err := repository.NewJobStore(db).GetFrom(c, req.JobID, app.Source)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	language := previous.Language
+	if language == "" {
+		language = job.JobLang
+	}
+
+	opts := previous
+	if req.Options != nil {
+		opts = *req.Options
+	}
+	if err := normalizeCoverLetterOptions(&opts, language); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid options", "issue": err.Error()})
+		return
+	}
+	format := req.ClFormat
+	if format == "" {
+		format = current.ClFormat
+	}
+
+	payload := map[string]interface{}{
+		"user_details":    generationUserDetails(c, db, userID),
+		"job_description": jobDescription(job.Job),
+		"cl_data":         coverLetterRequest(&opts),
+	}
+
+	// Partial credit is reserved now and refunded if the generation fails
+	enqueueGeneration(c, db, models.GenerationJob{
+		AuthUserID: userID,
+		JobID:      req.JobID,
+		Kind:       models.GenerationKindCoverLetterRegeneration,
+		Source:     job.Origin,
+		Payloads:   map[string]interface{}{"cover_letter": payload},
+		Params:     map[string]string{"cl_format": format},
+		Options:    &opts,
+	}, req.CallbackURL)
+}
+
+func runCoverLetterRegeneration(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	clResp, err := CallCoverLetterAPI(ctx, jobPayload(*job, "cover_letter"))
+	if err != nil {
+		return nil, fmt.Errorf("ML API failed: %w", err)
+	}
+
+	format := job.Params["cl_format"]
+	version, err := repository.RegenerateDocument(ctx, db, job.AuthUserID, job.JobID, models.DocumentKindCoverLetter, clResp, format, job.Options)
+	if errors.Is(err, repository.ErrDocumentNotFound) {
+		return nil, &generationError{err: errors.New("cover letter was deleted"), issue: "Cover letter not found"}
+	}
+	if err != nil {
+		log.Printf("❌ Failed to save regenerated cover letter for job %s: %v", job.JobID, err)
+		return nil, fmt.Errorf("failed to save cover letter: %w", err)
+	}
+
+	return map[string]interface{}{
+		"job_id":    job.JobID,
+		"cl_data":   clResp,
+		"cl_format": format,
+		"version":   version.Version,
+		"options":   job.Options,
+	}, nil
+}
@@ -0,0 +1,41 @@
+package generation
+
+import (
+	"RAAS/internal/handlers/repository"
+
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GET /b1/internal/generate-resume/analysis?job_id=...
+// Rates the CV of an internal or external job as an applicant-tracking
+// system would: job keywords found and missing, standard sections, length
+// and readability, and an overall ATS score. The analysis is stored and
+// redone only after the CV changes.
+func (h *InternalCVHandler) GetCVAnalysis(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	jobID := c.Query("job_id")
+	if jobID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing job_id query parameter"})
+		return
+	}
+
+	analysis, err := repository.AnalyzeCV(c, db, userID, jobID)
+	switch {
+	case errors.Is(err, repository.ErrCVNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "CV not found", "issue": "Generate the CV first"})
+	case errors.Is(err, repository.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+	case err != nil:
+		log.Printf("❌ Failed to analyse CV of job %s: %v", jobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyse CV"})
+	default:
+		c.JSON(http.StatusOK, analysis)
+	}
+}
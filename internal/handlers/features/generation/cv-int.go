@@ -2,7 +2,6 @@ package generation
 
 import (
 
-	"fmt"
 	"net/http"
 
 
@@ -12,7 +11,6 @@ import (
     "log"
 
 	"github.com/gin-gonic/gin"
-    "go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
     // "go.mongodb.org/mongo-driver/bson/primitive"
@@ -24,15 +22,13 @@ func NewInternalCVHandler() *InternalCVHandler {
     return &InternalCVHandler{}
 }
 
-// POST /b1/generate-cv
+// POST /b1/internal/generate-resume
+// Queues the CV generation and answers 202 with the generation job to follow.
 func (h *InternalCVHandler) PostCV(c *gin.Context) {
 
     db := c.MustGet("db").(*mongo.Database)
     cvColl := db.Collection("cv")
     selColl := db.Collection("selected_job_applications")
-    jobColl := db.Collection("jobs")
-    seekerColl := db.Collection("seekers")
-    authUserColl := db.Collection("auth_users")
 
     userID := c.MustGet("userID").(string)
 
@@ -40,6 +36,7 @@ func (h *InternalCVHandler) PostCV(c *gin.Context) {
                     JobID string `json:"job_id" binding:"required"`
                     JobLang string `json:"job_language" binding:"required"`
                     CvFormat string `json:"cv_format" binding:"required"`
+                    CallbackURL string `json:"callback_url"`
                 }
 
     if err := c.ShouldBindJSON(&req); err != nil {
@@ -60,31 +57,20 @@ func (h *InternalCVHandler) PostCV(c *gin.Context) {
         }
     }
 
-    // Step 2: Upsert first to validate limits and track usage
-    if err := upsertSelectedJobApp(db, userID, req.JobID, "cv", "internal"); err != nil {
+    // Step 2: Check limits early; quota is reserved when the generation is queued
+    if err := repository.CheckUsage(c, db, userID, req.JobID, models.GenerationKindCV, "internal"); err != nil {
         c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "issue": "Limit Reached"})
         return
     }
 
     // 3. Fetch supporting data
-    var job models.Job
-	
-    if err := jobColl.FindOne(c, bson.M{"job_id": req.JobID}).Decode(&job); err != nil {
+    job, err := repository.NewJobStore(db).GetFrom(c, req.JobID, models.ApplicationSourceInternal)
+    if err != nil {
         c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
         return
     }
-    var seeker models.Seeker
-    seekerColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker)
-
-    var authUser models.AuthUser
-    authUserColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&authUser)
-
-    pInfo, _ := repository.GetPersonalInfo(&seeker)
-    experienceSummaryObjs, _ := repository.GetWorkExperience(&seeker)
-    certificateObjs, _ := repository.GetCertificates(&seeker)
-    languageObjs, _ := repository.GetLanguages(&seeker)
-    pastProjects, _ := repository.GetPastProjects(&seeker)
-    educationObjs, _ := repository.GetAcademics(&seeker)
+    userDetails := generationUserDetails(c, db, userID)
+    userDetails["tools"] = []string{}
 
     // 1️⃣ Build education strings
 	// education := []string{}
@@ -157,71 +143,27 @@ func (h *InternalCVHandler) PostCV(c *gin.Context) {
 
     // 4. Build payload matching your required structure
     payload := map[string]interface{}{
-        "user_details": map[string]interface{}{
-            "name":               fmt.Sprintf("%s %s", pInfo.FirstName, *pInfo.SecondName),
-            "designation":        seeker.PrimaryTitle,
-            "address":            pInfo.City,
-            "contact":            authUser.Phone,
-            "email":              authUser.Email,
-            "portfolio":          /*pInfo.ExternalLinks*/"",
-            "linkedin":           pInfo.LinkedInProfile,
-            "tools":              []string{},           // or populate as needed
-            "skills":             seeker.KeySkills,
-            "education":          educationObjs,
-            "experience_summary": experienceSummaryObjs,
-            "past_projects":      pastProjects,
-            "certifications":     certificateObjs,
-            "languages":          languageObjs,
-        },
-        "job_description": map[string]interface{}{
-            "job_title":       job.JobTitle,
-            "title":           job.Title,
-            "company":         job.Company,
-            "location":        job.Location,
-            "job_type":        job.JobType,
-            "link":            job.Link,
-            "description":     job.JobDescription,
-            "responsibilities": "", // optional
-            "qualifications":   "", // optional
-            "skills":           job.Skills,
-            "benefits":         "", // optional
-        },
-        "cv_data": map[string]string{"language": req.JobLang, "spec": ""},
+        "user_details": userDetails,
+        "job_description": jobDescription(job.Job),
+        "cv_data": map[string]interface{}{"language": req.JobLang, "spec": ""},
     }
 
-    // 5. Call ML CV API
-        cvResp, err := CallCVAPI(payload)
-        if err != nil {
-            c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("CV API failed: %v", err)})
-            return
-        }
-
-        if userID == "" || req.JobID == "" {
-        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user or job ID"})
-        return
+    // Build on the master CV of the job language when there is one
+    if language := repository.MasterCVLanguage(req.JobLang); language != "" {
+        if master, err := repository.GetMasterCV(c, db, userID, language); err == nil {
+            payload["cv_data"].(map[string]interface{})["base_cv"] = master.Data()
         }
-        // 6. Save generated CV JSON
-        _, err = cvColl.InsertOne(c, bson.M{
-            "auth_user_id": userID,
-            "job_id":       req.JobID,
-            "cv_data":      cvResp,
-            "cv_format":    req.CvFormat,
-        })
-        if err != nil {
-        log.Printf("InsertOne error: %v", err)
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save CV data"})
-        return
     }
 
-        c.JSON(http.StatusOK, gin.H{
-            "job_id":  req.JobID,
-            "cv_data": cvResp,
-            "cv_format":req.CvFormat,
-        })
-
-    // c.JSON(http.StatusOK,gin.H{
-    //     "payload":payload,
-    // })
+    // 5. Queue the generation; quota is reserved now and refunded if it fails
+    enqueueGeneration(c, db, models.GenerationJob{
+        AuthUserID: userID,
+        JobID:      req.JobID,
+        Kind:       models.GenerationKindCV,
+        Source:     "internal",
+        Payloads:   map[string]interface{}{"cv": payload},
+        Params:     map[string]string{"cv_format": req.CvFormat},
+    }, req.CallbackURL)
 }
 
 // GET /b1/generate-cv?job_id=...
@@ -259,9 +201,10 @@ func (h *InternalCVHandler) GetCV(c *gin.Context) {
 
 }
 
+// PUT /b1/internal/generate-resume
+// Saves an edit of the CV as a new version.
 func (h *InternalCVHandler) PutCV(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
-    cvColl := db.Collection("cv")
     userID := c.MustGet("userID").(string)
 
     var req struct {
@@ -274,15 +217,12 @@ func (h *InternalCVHandler) PutCV(c *gin.Context) {
         return
     }
 
-    opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-    filter := bson.M{"auth_user_id": userID, "job_id": req.JobID}
-    update := bson.M{"$set": bson.M{"cv_data": req.CVData,"cv_format":req.CvFormat}}
-
-    var updated models.CVData
-    if err := cvColl.FindOneAndUpdate(c, filter, update, opts).Decode(&updated); err != nil {
-        if err == mongo.ErrNoDocuments {
+    version, err := repository.UpdateDocument(c, db, userID, req.JobID, models.DocumentKindCV, req.CVData, req.CvFormat, 0)
+    if err != nil {
+        if err == repository.ErrDocumentNotFound {
             c.JSON(http.StatusNotFound, gin.H{"error": "CV not found"})
         } else {
+            log.Printf("❌ Failed to update CV for job %s: %v", req.JobID, err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Update error"})
         }
         return
@@ -290,7 +230,8 @@ func (h *InternalCVHandler) PutCV(c *gin.Context) {
 
     c.JSON(http.StatusOK, gin.H{
         "job_id"    :  req.JobID,
-        "cv_data"   :  updated.CVData,
+        "cv_data"   :  version.Data,
         "cv_format" :req.CvFormat,
+        "version"   :  version.Version,
     })
 }
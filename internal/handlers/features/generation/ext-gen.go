@@ -5,14 +5,11 @@ import (
     "RAAS/internal/handlers/repository"
     "RAAS/internal/models"
 
-    "fmt"
     "net/http"
-    "time"
     "log"
     "github.com/gin-gonic/gin"
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/mongo"
-    "go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type ExternalJobCVNCLGenerator struct{}
@@ -21,14 +18,14 @@ func NewExternalJobCVNCLGenerator() *ExternalJobCVNCLGenerator{
 	return &ExternalJobCVNCLGenerator{}
 }
 
+// POST /b1/external/generate
+// Queues the CV and cover letter generation for an external job and answers
+// 202 with the generation job to follow.
 func (h *ExternalJobCVNCLGenerator) PostExternalCVNCL(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
     cvColl := db.Collection("cv")
     clColl := db.Collection("cover_letters")
     selColl := db.Collection("selected_job_applications")
-    seekerColl := db.Collection("seekers")
-    authUserColl := db.Collection("auth_users")
-    extJobColl := db.Collection("external_jobs")
 
     userID := c.MustGet("userID").(string)
 
@@ -41,12 +38,22 @@ func (h *ExternalJobCVNCLGenerator) PostExternalCVNCL(c *gin.Context) {
         JobLang        string `json:"job_language" binding:"required"`
         ClFormat       string `json:"cl_format" binding:"required"`
         CvFormat       string `json:"cv_format" binding:"required"`
+        ClOptions      *models.CoverLetterOptions `json:"cl_options"`
+        CallbackURL    string `json:"callback_url"`
     }
 
     if err := c.ShouldBindJSON(&req); err != nil {
         c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
         return
     }
+    clOpts := models.CoverLetterOptions{}
+    if req.ClOptions != nil {
+        clOpts = *req.ClOptions
+    }
+    if err := normalizeCoverLetterOptions(&clOpts, req.JobLang); err != nil {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cl_options", "issue": err.Error()})
+        return
+    }
 
     // Step 1: Check cached generation
     var selApp struct {
@@ -69,48 +76,16 @@ func (h *ExternalJobCVNCLGenerator) PostExternalCVNCL(c *gin.Context) {
         }
     }
 
-    if err := upsertSelectedJobApp(db, userID, req.JobID, "cover_letter", "external"); err != nil {
-        c.JSON(http.StatusForbidden, gin.H{"error": err.Error(),"issue":"Limit Exceeded"})
-        return
-    }
-    if err := upsertSelectedJobApp(db, userID, req.JobID, "cv", "external"); err != nil {
+    if err := repository.CheckUsage(c, db, userID, req.JobID, models.GenerationKindExternal, "external"); err != nil {
         c.JSON(http.StatusForbidden, gin.H{"error": err.Error(),"issue":"Limit Exceeded"})
         return
     }
 
 
 	//Step 2: Obtain Data for processing.
-	    var seeker models.Seeker
-    seekerColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker)
-
-    var authUser models.AuthUser
-    authUserColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&authUser)
-
-        pInfo, _ := repository.GetPersonalInfo(&seeker)
-        experienceSummaryObjs, _ := repository.GetWorkExperience(&seeker)
-        certificateObjs, _ := repository.GetCertificates(&seeker)
-        languageObjs, _ := repository.GetLanguages(&seeker)
-        pastProjects, _ := repository.GetPastProjects(&seeker)
-        educationObjs, _ := repository.GetAcademics(&seeker)
-
-	// Shared user details
-    userDetails := map[string]interface{}{
-			"name":               fmt.Sprintf("%s %s", pInfo.FirstName, *pInfo.SecondName),
-            "designation":        seeker.PrimaryTitle,
-            "address":            pInfo.City,
-            "contact":            authUser.Phone,
-            "email":              authUser.Email,
-            "portfolio":          /*pInfo.ExternalLinks*/"",
-            "linkedin":           pInfo.LinkedInProfile,
-            "tools":              []string{},
-                      
-            "skills":             seeker.KeySkills,
-            "education":          educationObjs,
-            "experience_summary": experienceSummaryObjs,
-            "past_projects":      pastProjects,
-            "certifications":     certificateObjs,
-            "languages":          languageObjs,	
-    }
+	// Shared user details; the CV and cover letter list no tools
+    userDetails := generationUserDetails(c, db, userID)
+    userDetails["tools"] = []string{}
 
 	jobDesc := map[string]interface{}{
         "company":         req.Company,
@@ -125,76 +100,36 @@ func (h *ExternalJobCVNCLGenerator) PostExternalCVNCL(c *gin.Context) {
         "benefits":         []string{},
     }
 
-	// Step 3: call ML APIs
+	// Step 3: build the ML requests
     cvPayload := map[string]interface{}{
         "user_details":    userDetails,
         "job_description": jobDesc,
         "cv_data":         map[string]string{"language": req.JobLang, "spec": ""},
     }
-    cvResp, cvErr := CallCVAPI(cvPayload)
-    if cvErr != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "CV generation failed: " + cvErr.Error()})
-        return
-    }
 
 	clPayload := map[string]interface{}{
         "user_details":    userDetails,
         "job_description": jobDesc,
-        "cl_data":         map[string]string{"language": req.JobLang, "spec": ""},
-    }
-    clResp, clErr := CallCoverLetterAPI(clPayload)
-    if clErr != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Cover letter generation failed: " + clErr.Error()})
-        return
+        "cl_data":         coverLetterRequest(&clOpts),
     }
 
-	// Step 4: Save to MongoDB
-    cvColl.InsertOne(c, bson.M{"auth_user_id": userID, "job_id": req.JobID, "cv_data": cvResp, "cv_format":req.CvFormat,})
-    clColl.InsertOne(c, bson.M{"auth_user_id": userID, "job_id": req.JobID, "cl_data": clResp,"cl_format":req.ClFormat,})
-
-    
-
-	// limit decrement
-    seekerColl.UpdateOne(c, bson.M{"auth_user_id":userID}, bson.M{"$inc": bson.M{"daily_generatable_coverletter": -1}})
-     // Step 6: Upsert into external_jobs model
-    now := time.Now()
-    filterJob := bson.M{"job_id": req.JobID}
-    updateJob := bson.M{
-        "$setOnInsert": bson.M{
-            "job_id":       req.JobID,
-            "title":        req.JobTitle,
+    // Step 4: Queue the generation; one application of quota is reserved now and refunded if it fails
+    enqueueGeneration(c, db, models.GenerationJob{
+        AuthUserID: userID,
+        JobID:      req.JobID,
+        Kind:       models.GenerationKindExternal,
+        Source:     "external",
+        Payloads:   map[string]interface{}{"cv": cvPayload, "cover_letter": clPayload},
+        Params: map[string]string{
             "company":      req.Company,
+            "job_title":    req.JobTitle,
             "description":  req.JobDescription,
             "job_language": req.JobLang,
-            "posted_date":  now,
+            "cv_format":    req.CvFormat,
+            "cl_format":    req.ClFormat,
         },
-    }
-    opts := options.Update().SetUpsert(true)
-    if _, err := extJobColl.UpdateOne(c, filterJob, updateJob, opts); err != nil {
-        c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save external job"})
-        return
-    }
-
-
-    // 🔁 Step 7: Also update company in selected_job_applications (external)
-    _, err := selColl.UpdateOne(
-        c,
-        bson.M{"auth_user_id": userID, "job_id": req.JobID, "source": "external"},
-        bson.M{"$set": bson.M{"company": req.Company}},
-    )
-    if err != nil {
-        log.Printf("❌ Failed to update company in selected_job_applications: %v\n", err)
-        // optionally continue or ignore
-    }
-
-    // Step 6: response
-    c.JSON(http.StatusOK, gin.H{
-		"job_id": req.JobID,
-        "cv_data": cvResp,
-        "cl_data": clResp,
-        "cl_format":req.ClFormat,
-        "cv_format":req.CvFormat,
-    })
+        Options: &clOpts,
+    }, req.CallbackURL)
 }
 
 // GET /b1/external/generate?job_id=...
@@ -243,7 +178,6 @@ func (h *ExternalJobCVNCLGenerator) GetExternalCVNCL(c *gin.Context) {
 
 func (h *ExternalJobCVNCLGenerator) PutCoverLetter(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
-    clColl := db.Collection("cover_letters")
     userID := c.MustGet("userID").(string)
 
     var req struct {
@@ -256,27 +190,22 @@ func (h *ExternalJobCVNCLGenerator) PutCoverLetter(c *gin.Context) {
         return
     }
 
-    opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-    filter := bson.M{"auth_user_id": userID, "job_id": req.JobID}
-    update := bson.M{"$set": bson.M{"cl_data": req.CLData,"cl_format":req.ClFormat}}
-
-    var updated models.CoverLetterData
-    err := clColl.FindOneAndUpdate(c, filter, update, opts).Decode(&updated)
+    version, err := repository.UpdateDocument(c, db, userID, req.JobID, models.DocumentKindCoverLetter, req.CLData, req.ClFormat, 0)
     if err != nil {
-        if err == mongo.ErrNoDocuments {
+        if err == repository.ErrDocumentNotFound {
             c.JSON(http.StatusNotFound, gin.H{"error": "Cover letter not found"})
         } else {
+            log.Printf("❌ Failed to update cover letter for job %s: %v", req.JobID, err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Update error"})
         }
         return
     }
 
-    c.JSON(http.StatusOK, gin.H{"job_id":req.JobID, "cl_data":updated.CLData, "cl_format": req.ClFormat })
+    c.JSON(http.StatusOK, gin.H{"job_id":req.JobID, "cl_data":version.Data, "cl_format": req.ClFormat, "version": version.Version })
 }
 
 func (h *ExternalJobCVNCLGenerator) PutCV(c *gin.Context) {
     db := c.MustGet("db").(*mongo.Database)
-    cvColl := db.Collection("cv")
     userID := c.MustGet("userID").(string)
 
     var req struct {
@@ -290,16 +219,12 @@ func (h *ExternalJobCVNCLGenerator) PutCV(c *gin.Context) {
         return
     }
 
-    opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
-    filter := bson.M{"auth_user_id": userID, "job_id": req.JobID}
-    update := bson.M{"$set": bson.M{"cv_data": req.CVData, "cv_format":req.CvFormat}}
-
-    var updated models.CVData
-    err := cvColl.FindOneAndUpdate(c, filter, update, opts).Decode(&updated)
+    version, err := repository.UpdateDocument(c, db, userID, req.JobID, models.DocumentKindCV, req.CVData, req.CvFormat, 0)
     if err != nil {
-        if err == mongo.ErrNoDocuments {
+        if err == repository.ErrDocumentNotFound {
             c.JSON(http.StatusNotFound, gin.H{"error": "CV not found"})
         } else {
+            log.Printf("❌ Failed to update CV for job %s: %v", req.JobID, err)
             c.JSON(http.StatusInternalServerError, gin.H{"error": "Update error"})
         }
         return
@@ -307,7 +232,8 @@ func (h *ExternalJobCVNCLGenerator) PutCV(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"job_id": req.JobID,
-        "cv_data": updated.CVData,
+        "cv_data": version.Data,
         "cv_format": req.CvFormat,
+        "version": version.Version,
     })
 }
\ No newline at end of file
@@ -0,0 +1,126 @@
+package generation
+
+import (
+	"RAAS/internal/models"
+
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	callbackAttempts = 3
+	// CallbackSignatureHeader carries "sha256=" and the hex HMAC of the body,
+	// keyed with the callback_secret returned when the job was queued.
+	CallbackSignatureHeader = "X-RAAS-Signature"
+)
+
+// callbackClient only dials public addresses, so a callback cannot reach
+// services inside our network, and never follows redirects.
+var callbackClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{Timeout: 5 * time.Second, Control: dialPublicOnly}).DialContext,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+}
+
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return fmt.Errorf("callback address %s is not public", host)
+	}
+	return nil
+}
+
+func publicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast())
+}
+
+// validateCallbackURL accepts absolute https URLs that do not name a local host.
+func validateCallbackURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return errors.New("callback_url must be an absolute URL")
+	}
+	if u.Scheme != "https" {
+		return errors.New("callback_url must use https")
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return errors.New("callback_url must be publicly reachable")
+	}
+	if ip := net.ParseIP(host); ip != nil && !publicIP(ip) {
+		return errors.New("callback_url must be publicly reachable")
+	}
+	return nil
+}
+
+func newCallbackKey() string {
+	b := make([]byte, 32)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func signCallback(key string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// deliverGenerationCallback posts the finished job to its callback URL,
+// retrying with backoff, and records when it was delivered.
+func deliverGenerationCallback(db *mongo.Database, job models.GenerationJob) {
+	body, err := json.Marshal(job)
+	if err != nil {
+		log.Printf("❌ Failed to encode callback for generation job %s: %v", job.ID.Hex(), err)
+		return
+	}
+	signature := signCallback(job.CallbackKey, body)
+
+	for attempt := 1; attempt <= callbackAttempts; attempt++ {
+		if attempt > 1 {
+			time.Sleep(time.Duration(attempt*attempt) * time.Second)
+		}
+		req, err := http.NewRequest(http.MethodPost, job.CallbackURL, bytes.NewReader(body))
+		if err != nil {
+			log.Printf("❌ Invalid callback URL for generation job %s: %v", job.ID.Hex(), err)
+			return
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(CallbackSignatureHeader, signature)
+
+		resp, err := callbackClient.Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 300 {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				db.Collection(models.CollectionGenerationJobs).UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{"callback_at": time.Now()}})
+				cancel()
+				return
+			}
+			err = fmt.Errorf("status %d", resp.StatusCode)
+		}
+		log.Printf("⚠️ Callback for generation job %s failed (attempt %d): %v", job.ID.Hex(), attempt, err)
+	}
+}
@@ -0,0 +1,128 @@
+package generation
+
+import (
+	"RAAS/internal/models"
+
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	generationPollInterval  = time.Second
+	generationStreamTimeout = 10 * time.Minute
+)
+
+type GenerationJobHandler struct{}
+
+func NewGenerationJobHandler() *GenerationJobHandler {
+	return &GenerationJobHandler{}
+}
+
+func findGenerationJob(c *gin.Context, db *mongo.Database, userID string) (models.GenerationJob, bool) {
+	var job models.GenerationJob
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid generation job id"})
+		return job, false
+	}
+	err = db.Collection(models.CollectionGenerationJobs).FindOne(c, bson.M{"_id": id, "auth_user_id": userID}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Generation job not found"})
+		return job, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return job, false
+	}
+	return job, true
+}
+
+// GET /b1/generation-jobs?status=queued|running|succeeded|failed
+// Lists the seeker's recent generation jobs, newest first.
+func (h *GenerationJobHandler) ListGenerationJobs(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	filter := bson.M{"auth_user_id": userID}
+	if status := c.Query("status"); status != "" {
+		filter["status"] = status
+	}
+	cursor, err := db.Collection(models.CollectionGenerationJobs).Find(c, filter, options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(50).
+		SetProjection(bson.M{"result": 0, "payloads": 0}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	jobs := []models.GenerationJob{}
+	if err := cursor.All(c, &jobs); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"generation_jobs": jobs})
+}
+
+// GET /b1/generation-jobs/:id
+// Returns the status of a generation job, with its result once it succeeded.
+func (h *GenerationJobHandler) GetGenerationJob(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	job, ok := findGenerationJob(c, db, userID)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// GET /b1/generation-jobs/:id/events
+// Streams the job as server-sent "status" events whenever it changes and
+// closes the stream once the job finished.
+func (h *GenerationJobHandler) StreamGenerationJob(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	job, ok := findGenerationJob(c, db, userID)
+	if !ok {
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ticker := time.NewTicker(generationPollInterval)
+	defer ticker.Stop()
+	deadline := time.After(generationStreamTimeout)
+	sentStatus, sentAttempts := "", -1
+
+	c.Stream(func(w io.Writer) bool {
+		if job.Status != sentStatus || job.Attempts != sentAttempts {
+			c.SSEvent("status", job)
+			sentStatus, sentAttempts = job.Status, job.Attempts
+		}
+		if job.Finished() {
+			return false
+		}
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-deadline:
+			return false
+		case <-ticker.C:
+		}
+
+		var next models.GenerationJob
+		if err := db.Collection(models.CollectionGenerationJobs).FindOne(c, bson.M{"_id": job.ID}).Decode(&next); err != nil {
+			c.SSEvent("error", gin.H{"error": "Generation job not found"})
+			return false
+		}
+		job = next
+		return true
+	})
+}
@@ -0,0 +1,54 @@
+package generation
+
+import (
+	"RAAS/internal/mlclient"
+
+	"context"
+)
+
+// MLService is what generation needs from the ML service. The workers call
+// Service; tests and offline setups can swap it for NewMLService with
+// mlclient.NewStub(), or for a fake.
+type MLService interface {
+	GenerateCV(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error)
+	GenerateCoverLetter(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error)
+	ResearchJob(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error)
+}
+
+// Service is the ML service used by the generation workers.
+var Service MLService = NewMLService(mlclient.Default)
+
+type clientService struct {
+	client *mlclient.Client
+}
+
+// NewMLService returns an MLService calling the ML endpoints through client.
+func NewMLService(client *mlclient.Client) MLService {
+	return clientService{client: client}
+}
+
+func (s clientService) GenerateCV(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return s.client.PostJSON(ctx, mlclient.CV, payload)
+}
+
+func (s clientService) GenerateCoverLetter(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return s.client.PostJSON(ctx, mlclient.CoverLetter, payload)
+}
+
+func (s clientService) ResearchJob(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return s.client.PostJSON(ctx, mlclient.JobResearch, payload)
+}
+
+// CallCoverLetterAPI generates a cover letter
+func CallCoverLetterAPI(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return Service.GenerateCoverLetter(ctx, payload)
+}
+
+// CallCVAPI generates a CV
+func CallCVAPI(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return Service.GenerateCV(ctx, payload)
+}
+
+func CallJobResearchAPI(ctx context.Context, payload map[string]interface{}) (map[string]interface{}, error) {
+	return Service.ResearchJob(ctx, payload)
+}
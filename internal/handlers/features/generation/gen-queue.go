@@ -0,0 +1,440 @@
+package generation
+
+import (
+	"RAAS/internal/handlers/features/jobs"
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/mlclient"
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxGenerationAttempts = 3
+	// generationLease is how long a worker owns a running job; a job whose
+	// lease ran out (the worker died) is picked up again.
+	generationLease   = 6 * time.Minute
+	generationTimeout = 5 * time.Minute
+	generationBackoff = 30 * time.Second
+)
+
+// queued wakes an idle worker when a job is enqueued.
+var queued = make(chan struct{}, 1)
+
+// GenerationQueued signals that a generation job was enqueued.
+func GenerationQueued() <-chan struct{} {
+	return queued
+}
+
+// generationError is a failure that retrying will not fix.
+type generationError struct {
+	err   error
+	issue string
+}
+
+func (e *generationError) Error() string { return e.err.Error() }
+func (e *generationError) Unwrap() error { return e.err }
+
+func retryable(err error) bool {
+	return mlclient.Retryable(err) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// limitError reports a quota that ran out while the job was queued.
+func limitError(err error) error {
+	if errors.Is(err, repository.ErrQuotaExceeded) {
+		return &generationError{err: err, issue: "Limit Reached"}
+	}
+	return err
+}
+
+// enqueueGeneration reserves the quota a generation job needs, stores the
+// job and answers 202 with where to follow it. A job already queued or
+// running for the same document is returned instead of starting another one.
+func enqueueGeneration(c *gin.Context, db *mongo.Database, job models.GenerationJob, callbackURL string) {
+	if callbackURL != "" {
+		if err := validateCallbackURL(callbackURL); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid callback_url", "issue": err.Error()})
+			return
+		}
+		job.CallbackURL = callbackURL
+		job.CallbackKey = newCallbackKey()
+	}
+
+	coll := db.Collection(models.CollectionGenerationJobs)
+	job.ID = primitive.NewObjectID()
+	usage, err := repository.ReserveUsage(c, db, job.AuthUserID, job.JobID, job.ID, job.Kind, job.Source)
+	if errors.Is(err, repository.ErrRegenerationQueued) {
+		if existing, findErr := activeGeneration(c, coll, job); findErr == nil {
+			c.JSON(http.StatusAccepted, generationAccepted(existing, ""))
+			return
+		}
+	}
+	if errors.Is(err, repository.ErrQuotaExceeded) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error(), "issue": "Limit Reached"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to reserve quota for %s generation of job %s: %v", job.Kind, job.JobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue generation"})
+		return
+	}
+	if usage != nil {
+		job.UsageID = usage.ID
+	}
+
+	now := time.Now()
+	job.Status = models.GenerationStatusQueued
+	job.Active = true
+	job.RunAfter = now
+	job.CreatedAt = now
+
+	_, err = coll.InsertOne(c, job)
+	if err != nil && !job.UsageID.IsZero() {
+		if relErr := repository.ReleaseUsage(c, db, job.UsageID, "not queued"); relErr != nil {
+			log.Printf("❌ Failed to release quota of unqueued job %s: %v", job.JobID, relErr)
+		}
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		if existing, findErr := activeGeneration(c, coll, job); findErr == nil {
+			c.JSON(http.StatusAccepted, generationAccepted(existing, ""))
+			return
+		}
+	}
+	if err != nil {
+		log.Printf("❌ Failed to queue %s generation for job %s: %v", job.Kind, job.JobID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue generation"})
+		return
+	}
+
+	select {
+	case queued <- struct{}{}:
+	default:
+	}
+	c.JSON(http.StatusAccepted, generationAccepted(job, job.CallbackKey))
+}
+
+// activeGeneration finds the queued or running generation of the same kind
+// for the job.
+func activeGeneration(c *gin.Context, coll *mongo.Collection, job models.GenerationJob) (models.GenerationJob, error) {
+	var existing models.GenerationJob
+	err := coll.FindOne(c, bson.M{
+		"auth_user_id": job.AuthUserID, "job_id": job.JobID, "kind": job.Kind, "active": true,
+	}).Decode(&existing)
+	return existing, err
+}
+
+func generationAccepted(job models.GenerationJob, callbackSecret string) gin.H {
+	id := job.ID.Hex()
+	resp := gin.H{
+		"generation_job_id": id,
+		"job_id":            job.JobID,
+		"kind":              job.Kind,
+		"status":            job.Status,
+		"status_url":        "/b1/generation-jobs/" + id,
+		"events_url":        "/b1/generation-jobs/" + id + "/events",
+	}
+	// The secret signs the callback; it is only ever shown here
+	if callbackSecret != "" {
+		resp["callback_secret"] = callbackSecret
+	}
+	return resp
+}
+
+// generationRunners execute each job kind and return its result. Runners
+// save documents only after every ML call succeeded; the quota reserved for
+// the job is committed or released when it finishes.
+var generationRunners = map[string]func(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error){
+	models.GenerationKindCV:          runCVGeneration,
+	models.GenerationKindCoverLetter: runCoverLetterGeneration,
+	models.GenerationKindExternal:    runExternalGeneration,
+	models.GenerationKindJobResearch: runJobResearch,
+
+	models.GenerationKindCoverLetterRegeneration: runCoverLetterRegeneration,
+	models.GenerationKindMasterCV:                runMasterCVGeneration,
+}
+
+// ProcessNextGenerationJob claims the next due job and runs it. It reports
+// false when no job was due.
+func ProcessNextGenerationJob(ctx context.Context, db *mongo.Database) (bool, error) {
+	now := time.Now()
+	var job models.GenerationJob
+	err := db.Collection(models.CollectionGenerationJobs).FindOneAndUpdate(ctx,
+		bson.M{"$or": bson.A{
+			bson.M{"status": models.GenerationStatusQueued, "run_after": bson.M{"$lte": now}},
+			bson.M{"status": models.GenerationStatusRunning, "lease_until": bson.M{"$lt": now}},
+		}},
+		bson.M{
+			"$set": bson.M{"status": models.GenerationStatusRunning, "started_at": now, "lease_until": now.Add(generationLease)},
+			"$inc": bson.M{"attempts": 1},
+		},
+		options.FindOneAndUpdate().
+			SetSort(bson.D{{Key: "run_after", Value: 1}}).
+			SetReturnDocument(options.After),
+	).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var result map[string]interface{}
+	runner, ok := generationRunners[job.Kind]
+	switch {
+	case !ok:
+		err = &generationError{err: fmt.Errorf("unknown generation kind %q", job.Kind)}
+	case job.Attempts > maxGenerationAttempts:
+		// Reclaimed after its last attempt outlived the lease
+		err = &generationError{err: errors.New("generation timed out")}
+	default:
+		runCtx, cancel := context.WithTimeout(ctx, generationTimeout)
+		result, err = runner(runCtx, db, &job)
+		cancel()
+	}
+	finishGenerationJob(db, job, result, err)
+	return true, nil
+}
+
+// finishGenerationJob requeues a failed attempt with backoff, or records the
+// final outcome and notifies the callback.
+func finishGenerationJob(db *mongo.Database, job models.GenerationJob, result map[string]interface{}, runErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	coll := db.Collection(models.CollectionGenerationJobs)
+	now := time.Now()
+
+	if runErr != nil && retryable(runErr) && job.Attempts < maxGenerationAttempts {
+		delay := generationBackoff << uint(job.Attempts-1)
+		log.Printf("⚠️ Generation job %s attempt %d failed, retrying in %s: %v", job.ID.Hex(), job.Attempts, delay, runErr)
+		if _, err := coll.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": bson.M{
+			"status":    models.GenerationStatusQueued,
+			"run_after": now.Add(delay),
+			"error":     runErr.Error(),
+		}}); err != nil {
+			log.Printf("❌ Failed to requeue generation job %s: %v", job.ID.Hex(), err)
+		}
+		return
+	}
+
+	settleUsage(ctx, db, job, runErr)
+
+	expires := now.Add(models.GenerationJobRetention)
+	job.FinishedAt, job.ExpiresAt = &now, &expires
+	set := bson.M{"finished_at": now, "expires_at": expires}
+	unset := bson.M{"active": "", "payloads": "", "lease_until": ""}
+	if runErr != nil {
+		job.Status, job.Error = models.GenerationStatusFailed, runErr.Error()
+		var genErr *generationError
+		if errors.As(runErr, &genErr) {
+			job.Issue = genErr.issue
+		}
+		set["status"], set["error"], set["issue"] = job.Status, job.Error, job.Issue
+		log.Printf("❌ Generation job %s (%s, job %s) failed: %v", job.ID.Hex(), job.Kind, job.JobID, runErr)
+	} else {
+		job.Status, job.Result, job.Error = models.GenerationStatusSucceeded, result, ""
+		set["status"], set["result"] = job.Status, result
+		unset["error"] = ""
+		log.Printf("✅ Generation job %s (%s, job %s) succeeded", job.ID.Hex(), job.Kind, job.JobID)
+	}
+	if _, err := coll.UpdateOne(ctx, bson.M{"_id": job.ID}, bson.M{"$set": set, "$unset": unset}); err != nil {
+		log.Printf("❌ Failed to record outcome of generation job %s: %v", job.ID.Hex(), err)
+		return
+	}
+
+	if job.CallbackURL != "" {
+		go deliverGenerationCallback(db, job)
+	}
+}
+
+// chargeUsage reserves quota at run time for a job queued while another
+// generation held the reservation for its application, in case that one
+// failed and gave it back.
+func chargeUsage(ctx context.Context, db *mongo.Database, job *models.GenerationJob) error {
+	if !job.UsageID.IsZero() {
+		return nil
+	}
+	usage, err := repository.ReserveUsage(ctx, db, job.AuthUserID, job.JobID, job.ID, job.Kind, job.Source)
+	if err != nil || usage == nil {
+		return err
+	}
+	job.UsageID = usage.ID
+	if _, err := db.Collection(models.CollectionGenerationJobs).UpdateOne(ctx,
+		bson.M{"_id": job.ID}, bson.M{"$set": bson.M{"usage_id": usage.ID}},
+	); err != nil {
+		log.Printf("⚠️ Failed to link quota to generation job %s: %v", job.ID.Hex(), err)
+	}
+	return nil
+}
+
+// settleUsage commits the quota of a succeeded job and refunds that of a
+// failed one.
+func settleUsage(ctx context.Context, db *mongo.Database, job models.GenerationJob, runErr error) {
+	if job.UsageID.IsZero() {
+		return
+	}
+	var err error
+	if runErr == nil {
+		err = repository.CommitUsage(ctx, db, job.UsageID)
+	} else {
+		err = repository.ReleaseUsage(ctx, db, job.UsageID, "generation failed")
+	}
+	if err != nil {
+		log.Printf("❌ Failed to settle quota of generation job %s: %v", job.ID.Hex(), err)
+	}
+}
+
+// jobPayload returns the stored ML request for an endpoint.
+func jobPayload(job models.GenerationJob, endpoint string) map[string]interface{} {
+	switch p := job.Payloads[endpoint].(type) {
+	case primitive.M:
+		return p
+	case map[string]interface{}:
+		return p
+	}
+	return map[string]interface{}{}
+}
+
+func runCVGeneration(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	cvResp, err := CallCVAPI(ctx, jobPayload(*job, "cv"))
+	if err != nil {
+		return nil, fmt.Errorf("CV API failed: %w", err)
+	}
+
+	if err := chargeUsage(ctx, db, job); err != nil {
+		return nil, limitError(err)
+	}
+	if err := upsertSelectedJobApp(db, job.AuthUserID, job.JobID, "cv", "internal"); err != nil {
+		return nil, err
+	}
+	format := job.Params["cv_format"]
+	version, err := repository.SaveGeneratedDocument(ctx, db, job.AuthUserID, job.JobID, models.DocumentKindCV, cvResp, format, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save CV data: %w", err)
+	}
+
+	return map[string]interface{}{
+		"job_id":    job.JobID,
+		"cv_data":   cvResp,
+		"cv_format": format,
+		"version":   version.Version,
+	}, nil
+}
+
+func runCoverLetterGeneration(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	clResp, err := CallCoverLetterAPI(ctx, jobPayload(*job, "cover_letter"))
+	if err != nil {
+		return nil, fmt.Errorf("ML API failed: %w", err)
+	}
+
+	if err := chargeUsage(ctx, db, job); err != nil {
+		return nil, limitError(err)
+	}
+	if err := upsertSelectedJobApp(db, job.AuthUserID, job.JobID, "cover_letter", "internal"); err != nil {
+		return nil, err
+	}
+	format := job.Params["cl_format"]
+	version, err := repository.SaveGeneratedDocument(ctx, db, job.AuthUserID, job.JobID, models.DocumentKindCoverLetter, clResp, format, job.Options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save cover letter: %w", err)
+	}
+
+	return map[string]interface{}{
+		"job_id":    job.JobID,
+		"cl_data":   clResp,
+		"cl_format": format,
+		"version":   version.Version,
+	}, nil
+}
+
+func runExternalGeneration(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	cvResp, err := CallCVAPI(ctx, jobPayload(*job, "cv"))
+	if err != nil {
+		return nil, fmt.Errorf("CV generation failed: %w", err)
+	}
+	clResp, err := CallCoverLetterAPI(ctx, jobPayload(*job, "cover_letter"))
+	if err != nil {
+		return nil, fmt.Errorf("cover letter generation failed: %w", err)
+	}
+
+	userID, p := job.AuthUserID, job.Params
+
+	// Save the job first, so a failure here does not refund a finished generation
+	if _, err := db.Collection(models.CollectionExtJobs).UpdateOne(ctx,
+		bson.M{"job_id": job.JobID},
+		bson.M{"$setOnInsert": bson.M{
+			"job_id":       job.JobID,
+			"title":        p["job_title"],
+			"company":      p["company"],
+			"description":  p["description"],
+			"job_language": p["job_language"],
+			"posted_date":  time.Now(),
+		}},
+		options.Update().SetUpsert(true),
+	); err != nil {
+		return nil, fmt.Errorf("failed to save external job: %w", err)
+	}
+
+	if err := chargeUsage(ctx, db, job); err != nil {
+		return nil, limitError(err)
+	}
+	if err := upsertSelectedJobApp(db, userID, job.JobID, "cover_letter", "external"); err != nil {
+		return nil, err
+	}
+	if err := upsertSelectedJobApp(db, userID, job.JobID, "cv", "external"); err != nil {
+		return nil, err
+	}
+
+	if _, err := repository.SaveGeneratedDocument(ctx, db, userID, job.JobID, models.DocumentKindCV, cvResp, p["cv_format"], nil); err != nil {
+		return nil, fmt.Errorf("failed to save CV data: %w", err)
+	}
+	if _, err := repository.SaveGeneratedDocument(ctx, db, userID, job.JobID, models.DocumentKindCoverLetter, clResp, p["cl_format"], job.Options); err != nil {
+		return nil, fmt.Errorf("failed to save cover letter data: %w", err)
+	}
+
+	// Also update company in selected_job_applications (external)
+	if _, err := db.Collection(models.CollectionSelectedJobApps).UpdateOne(ctx,
+		bson.M{"auth_user_id": userID, "job_id": job.JobID, "source": "external"},
+		bson.M{"$set": bson.M{"company": p["company"]}},
+	); err != nil {
+		log.Printf("❌ Failed to update company in selected_job_applications: %v\n", err)
+	}
+	if _, err := jobs.ScoreJob(ctx, db, userID, job.JobID); err != nil {
+		log.Printf("⚠️ Failed to score external job %s: %v", job.JobID, err)
+	}
+
+	return map[string]interface{}{
+		"job_id":    job.JobID,
+		"cv_data":   cvResp,
+		"cl_data":   clResp,
+		"cl_format": p["cl_format"],
+		"cv_format": p["cv_format"],
+	}, nil
+}
+
+func runJobResearch(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	researchResp, err := CallJobResearchAPI(ctx, jobPayload(*job, "job_research"))
+	if err != nil {
+		return nil, fmt.Errorf("Job Research API failed: %w", err)
+	}
+
+	if _, err := db.Collection(models.CollectionJobResearch).InsertOne(ctx, models.JobResearchResult{
+		AuthUserID:  job.AuthUserID,
+		JobID:       job.JobID,
+		Response:    researchResp,
+		GeneratedAt: time.Now(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save job research result: %w", err)
+	}
+	return researchResp, nil
+}
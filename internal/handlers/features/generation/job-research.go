@@ -1,10 +1,8 @@
 package generation
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
-	"time"
 
 	"RAAS/internal/handlers/repository"
 	"RAAS/internal/models"
@@ -20,20 +18,20 @@ func NewJobResearchHandler() *JobResearchHandler {
 	return &JobResearchHandler{}
 }
 
+// POST /b2/job-research
+// Queues the research for a job at or past the interview stage and answers 202 with the
+// generation job to follow.
 func (h *JobResearchHandler) PostJobResearch(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 
 	selColl := db.Collection("selected_job_applications")
-	jobColl := db.Collection("jobs")
-	extJobColl := db.Collection("external_jobs")
-	seekerColl := db.Collection("seekers")
-	authUserColl := db.Collection("auth_users")
 	researchColl := db.Collection("job_research_results")
 
 	userID := c.MustGet("userID").(string)
 
 	var req struct {
-		JobID string `json:"job_id" binding:"required"`
+		JobID       string `json:"job_id" binding:"required"`
+		CallbackURL string `json:"callback_url"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or invalid values"})
@@ -71,117 +69,33 @@ func (h *JobResearchHandler) PostJobResearch(c *gin.Context) {
 		}
 		return
 	}
-	if !strings.EqualFold(selApp.Status, "interview") {
+	if !models.ReachedInterviewStage(selApp.Status) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Job is not in interview stage"})
 		return
 	}
 
-	var payload map[string]interface{}
-
-	if strings.EqualFold(selApp.Source, "internal") {
-		// INTERNAL — fetch from jobs
-		var job models.Job
-		if err := jobColl.FindOne(c, bson.M{"job_id": req.JobID}).Decode(&job); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
-			return
-		}
-
-		var seeker models.Seeker
-		seekerColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker)
-		var authUser models.AuthUser
-		authUserColl.FindOne(c, bson.M{"auth_user_id": userID}).Decode(&authUser)
-
-		pInfo, _ := repository.GetPersonalInfo(&seeker)
-		experienceSummaryObjs, _ := repository.GetWorkExperience(&seeker)
-		certificateObjs, _ := repository.GetCertificates(&seeker)
-		languageObjs, _ := repository.GetLanguages(&seeker)
-		pastProjects, _ := repository.GetPastProjects(&seeker)
-		educationObjs, _ := repository.GetAcademics(&seeker)
-
-		payload = map[string]interface{}{
-			"company":   job.Company,
-			"job_title": job.JobTitle,
-			"candidate_profile": map[string]interface{}{
-				"name":               fmt.Sprintf("%s %s", pInfo.FirstName, *pInfo.SecondName),
-				"designation":        seeker.PrimaryTitle,
-				"address":            pInfo.City,
-				"contact":            authUser.Phone,
-				"email":              authUser.Email,
-				"portfolio":          "",
-				"linkedin":           pInfo.LinkedInProfile,
-				"tools":              "",
-				"skills":             seeker.KeySkills,
-				"education":          educationObjs,
-				"experience_summary": experienceSummaryObjs,
-				"past_projects":      pastProjects,
-				"certifications":     certificateObjs,
-				"languages":          languageObjs,
-			},
-			"job_description": map[string]interface{}{
-				"job_title":        job.JobTitle,
-				"title":            job.Title,
-				"company":          job.Company,
-				"location":         job.Location,
-				"job_type":         job.JobType,
-				"link":             job.Link,
-				"description":      job.JobDescription,
-				"responsibilities": "",
-				"qualifications":   "",
-				"skills":           job.Skills,
-				"benefits":         "",
-			},
-			"job_link": job.Link,
-		}
-
-	} else if strings.EqualFold(selApp.Source, "external") {
-		// EXTERNAL — fetch minimal details
-		var extJob struct {
-			JobID       string    `bson:"job_id"`
-			Title       string    `bson:"title"`
-			Company     string    `bson:"company"`
-			Description string    `bson:"description"`
-			JobLang     string    `bson:"job_language"`
-			PostedDate  time.Time `bson:"posted_date"`
-		}
-		if err := extJobColl.FindOne(c, bson.M{"job_id": req.JobID}).Decode(&extJob); err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "External job not found"})
-			return
-		}
-
-		payload = map[string]interface{}{
-			"job_id":       extJob.JobID,
-			"title":        extJob.Title,
-			"company":      extJob.Company,
-			"description":  extJob.Description,
-			"job_language": extJob.JobLang,
-			"posted_date":  extJob.PostedDate,
-		}
-	} else {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job source"})
-		return
-	}
-
-	// 3. Call Job Research API
-	researchResp, err := CallJobResearchAPI(payload)
+	// Internal and external jobs are researched alike
+	job, err := repository.NewJobStore(db).GetFrom(c, req.JobID, strings.ToLower(selApp.Source))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Job Research API failed: %v", err)})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
-
-	// 4. Save result
-	_, err = researchColl.InsertOne(c, models.JobResearchResult{
-		AuthUserID:  userID,
-		JobID:       req.JobID,
-		Response:    researchResp,
-		GeneratedAt: time.Now(),
-	})
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save job research result"})
-		return
+	payload := map[string]interface{}{
+		"company":           job.Company,
+		"job_title":         job.JobTitle,
+		"candidate_profile": generationUserDetails(c, db, userID),
+		"job_description":   jobDescription(job.Job),
+		"job_link":          job.Link,
+		"job_language":      job.JobLang,
 	}
 
-	// 5. Return response
-	c.JSON(http.StatusOK, researchResp)
+	// 3. Queue the research
+	enqueueGeneration(c, db, models.GenerationJob{
+		AuthUserID: userID,
+		JobID:      req.JobID,
+		Kind:       models.GenerationKindJobResearch,
+		Payloads:   map[string]interface{}{"job_research": payload},
+	}, req.CallbackURL)
 }
 
 
@@ -0,0 +1,226 @@
+package generation
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type MasterCVHandler struct{}
+
+func NewMasterCVHandler() *MasterCVHandler {
+	return &MasterCVHandler{}
+}
+
+// POST /b1/master-cv
+// Queues the master CV in the requested languages, English and German by
+// default. Languages whose CV is up to date with the profile are not
+// regenerated unless force is set; the seeker's section edits are kept as
+// long as the profile data behind them is unchanged. Uses no quota.
+func (h *MasterCVHandler) GenerateMasterCV(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var req struct {
+		Languages   []string `json:"languages"`
+		CvFormat    string   `json:"cv_format"`
+		Force       bool     `json:"force"`
+		CallbackURL string   `json:"callback_url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or invalid values"})
+		return
+	}
+	if len(req.Languages) == 0 {
+		req.Languages = models.DefaultMasterCVLanguages
+	}
+	var languages []string
+	seen := map[string]bool{}
+	for _, l := range req.Languages {
+		language := repository.MasterCVLanguage(l)
+		if language == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "issue": l})
+			return
+		}
+		if !seen[language] {
+			seen[language] = true
+			languages = append(languages, language)
+		}
+	}
+
+	var seeker models.Seeker
+	if err := db.Collection(models.CollectionSeekers).FindOne(c, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found"})
+			return
+		}
+		log.Printf("❌ Failed to load seeker %s for master CV: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "DB error"})
+		return
+	}
+
+	payload := map[string]interface{}{
+		"user_details": generationUserDetails(c, db, userID),
+		"job_description": map[string]interface{}{
+			"job_title":   seeker.PrimaryTitle,
+			"description": "General CV covering the whole profile, not tailored to a specific job",
+		},
+	}
+
+	enqueueGeneration(c, db, models.GenerationJob{
+		AuthUserID: userID,
+		JobID:      models.MasterCVJobID,
+		Kind:       models.GenerationKindMasterCV,
+		Payloads: map[string]interface{}{
+			"cv":      payload,
+			"sources": repository.MasterCVSources(&seeker),
+		},
+		Params: map[string]string{
+			"languages": strings.Join(languages, ","),
+			"cv_format": req.CvFormat,
+			"force":     fmt.Sprint(req.Force),
+		},
+	}, req.CallbackURL)
+}
+
+// GET /b1/master-cv
+// Lists the master CV in every language it was generated in.
+func (h *MasterCVHandler) ListMasterCVs(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	cvs, err := repository.ListMasterCVs(c, db, userID)
+	if err != nil {
+		log.Printf("❌ Failed to list master CVs of %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load master CV"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"master_cvs": cvs})
+}
+
+// GET /b1/master-cv/:language
+func (h *MasterCVHandler) GetMasterCV(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	language := repository.MasterCVLanguage(c.Param("language"))
+	if language == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "issue": c.Param("language")})
+		return
+	}
+	cv, err := repository.GetMasterCV(c, db, userID, language)
+	if errors.Is(err, repository.ErrMasterCVNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Master CV not found", "issue": "Generate the master CV first"})
+		return
+	}
+	if err != nil {
+		log.Printf("❌ Failed to load %s master CV of %s: %v", language, userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load master CV"})
+		return
+	}
+	c.JSON(http.StatusOK, cv)
+}
+
+// PUT /b1/master-cv/:language/sections/:section
+// Saves the seeker's edit of a section. Regeneration keeps it until the
+// profile data the section is built from changes.
+func (h *MasterCVHandler) UpdateSection(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	language := repository.MasterCVLanguage(c.Param("language"))
+	if language == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported language", "issue": c.Param("language")})
+		return
+	}
+	var req struct {
+		Content interface{} `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing content"})
+		return
+	}
+
+	cv, err := repository.UpdateMasterCVSection(c, db, userID, language, c.Param("section"), req.Content)
+	switch {
+	case errors.Is(err, repository.ErrMasterCVNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Master CV not found", "issue": "Generate the master CV first"})
+	case errors.Is(err, repository.ErrSectionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section not found", "issue": c.Param("section")})
+	case err != nil:
+		log.Printf("❌ Failed to update %s master CV of %s: %v", language, userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update master CV"})
+	default:
+		c.JSON(http.StatusOK, cv)
+	}
+}
+
+// masterCVSources reads the section sources stored with the job.
+func masterCVSources(job models.GenerationJob) map[string]string {
+	sources := map[string]string{}
+	switch s := job.Payloads["sources"].(type) {
+	case primitive.M:
+		for k, v := range s {
+			sources[k], _ = v.(string)
+		}
+	case map[string]interface{}:
+		for k, v := range s {
+			sources[k], _ = v.(string)
+		}
+	case map[string]string:
+		return s
+	}
+	return sources
+}
+
+// runMasterCVGeneration generates each language in turn and merges it into
+// the stored master CV. Without force, languages saved before a failure are
+// up to date and skipped when the job is retried.
+func runMasterCVGeneration(ctx context.Context, db *mongo.Database, job *models.GenerationJob) (map[string]interface{}, error) {
+	base := jobPayload(*job, "cv")
+	sources := masterCVSources(*job)
+	format := job.Params["cv_format"]
+	force := job.Params["force"] == "true"
+
+	results := map[string]interface{}{}
+	for _, language := range strings.Split(job.Params["languages"], ",") {
+		existing, err := repository.GetMasterCV(ctx, db, job.AuthUserID, language)
+		if err != nil && !errors.Is(err, repository.ErrMasterCVNotFound) {
+			return nil, fmt.Errorf("failed to load %s master CV: %w", language, err)
+		}
+		if !force && err == nil && repository.MasterCVUpToDate(existing, sources) {
+			results[language] = map[string]interface{}{"status": "unchanged"}
+			continue
+		}
+
+		payload := make(map[string]interface{}, len(base)+1)
+		for k, v := range base {
+			payload[k] = v
+		}
+		payload["cv_data"] = map[string]string{"language": language, "spec": ""}
+		cvResp, err := CallCVAPI(ctx, payload)
+		if err != nil {
+			return nil, fmt.Errorf("CV API failed for %s: %w", language, err)
+		}
+
+		_, kept, err := repository.MergeMasterCV(ctx, db, job.AuthUserID, language, format, cvResp, sources, force)
+		if err != nil {
+			log.Printf("❌ Failed to save %s master CV of %s: %v", language, job.AuthUserID, err)
+			return nil, fmt.Errorf("failed to save master CV: %w", err)
+		}
+		results[language] = map[string]interface{}{"status": "generated", "kept_edits": kept}
+	}
+
+	return map[string]interface{}{"languages": results}, nil
+}
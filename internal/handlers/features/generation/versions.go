@@ -0,0 +1,165 @@
+package generation
+
+import (
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type DocumentVersionHandler struct{}
+
+func NewDocumentVersionHandler() *DocumentVersionHandler {
+	return &DocumentVersionHandler{}
+}
+
+// documentKind reads the :kind path parameter; "cl" is accepted for cover letters.
+func documentKind(c *gin.Context) (string, bool) {
+	switch c.Param("kind") {
+	case models.DocumentKindCV:
+		return models.DocumentKindCV, true
+	case models.DocumentKindCoverLetter, "cl":
+		return models.DocumentKindCoverLetter, true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be cv or cover_letter"})
+	return "", false
+}
+
+func versionNumber(c *gin.Context, value, name string) (int, bool) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name, "issue": name + " must be a positive version number"})
+		return 0, false
+	}
+	return n, true
+}
+
+func versionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version not found"})
+	case errors.Is(err, repository.ErrDocumentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Document not found"})
+	default:
+		log.Printf("❌ Document version error: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+	}
+}
+
+// GET /b1/documents/:job_id/:kind/versions
+// Lists the versions of a CV or cover letter, newest first, without content.
+func (h *DocumentVersionHandler) ListVersions(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	kind, ok := documentKind(c)
+	if !ok {
+		return
+	}
+
+	versions, err := repository.ListDocumentVersions(c, db, userID, c.Param("job_id"), kind)
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":   c.Param("job_id"),
+		"kind":     kind,
+		"versions": versions,
+	})
+}
+
+// GET /b1/documents/:job_id/:kind/versions/:version
+func (h *DocumentVersionHandler) GetVersion(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	kind, ok := documentKind(c)
+	if !ok {
+		return
+	}
+	n, ok := versionNumber(c, c.Param("version"), "version")
+	if !ok {
+		return
+	}
+
+	version, err := repository.GetDocumentVersion(c, db, userID, c.Param("job_id"), kind, n)
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// GET /b1/documents/:job_id/:kind/diff?from=&to=
+// Compares two versions; without "to" the newest version is used.
+func (h *DocumentVersionHandler) DiffVersions(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	jobID := c.Param("job_id")
+	kind, ok := documentKind(c)
+	if !ok {
+		return
+	}
+	from, ok := versionNumber(c, c.Query("from"), "from")
+	if !ok {
+		return
+	}
+
+	var to int
+	if c.Query("to") != "" {
+		if to, ok = versionNumber(c, c.Query("to"), "to"); !ok {
+			return
+		}
+	} else {
+		versions, err := repository.ListDocumentVersions(c, db, userID, jobID, kind)
+		if err != nil {
+			versionError(c, err)
+			return
+		}
+		if len(versions) == 0 {
+			versionError(c, repository.ErrVersionNotFound)
+			return
+		}
+		to = versions[0].Version
+	}
+
+	fromVersion, err := repository.GetDocumentVersion(c, db, userID, jobID, kind, from)
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+	toVersion, err := repository.GetDocumentVersion(c, db, userID, jobID, kind, to)
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, repository.DiffDocumentVersions(fromVersion, toVersion))
+}
+
+// POST /b1/documents/:job_id/:kind/versions/:version/restore
+// Makes an old version the live document again, recorded as a new version.
+func (h *DocumentVersionHandler) RestoreVersion(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+	kind, ok := documentKind(c)
+	if !ok {
+		return
+	}
+	n, ok := versionNumber(c, c.Param("version"), "version")
+	if !ok {
+		return
+	}
+
+	version, err := repository.RestoreDocumentVersion(c, db, userID, c.Param("job_id"), kind, n)
+	if err != nil {
+		versionError(c, err)
+		return
+	}
+	log.Printf("🔧 Restored %s version %d of job %s as version %d", kind, n, version.JobID, version.Version)
+	c.JSON(http.StatusOK, version)
+}
@@ -3,6 +3,8 @@ package jobs
 import (
 	"RAAS/internal/handlers/repository"
 	"RAAS/internal/models"
+	"RAAS/internal/skills"
+	"context"
 	"fmt"
 	// "log"
 	"net/http"
@@ -13,6 +15,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	// "math"
 	"strings"
@@ -53,6 +56,20 @@ func (h *MatchScoreHandler) GetMatchScores(c *gin.Context) {
         return
     }
 
+    // Jobs outside the batch scoring, such as external ones, are scored on request
+    if jobID := c.Query("job_id"); jobID != "" && len(results) == 0 {
+        score, err := ScoreJob(c, db, userID, jobID)
+        if err == repository.ErrJobNotFound {
+            c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+            return
+        }
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to score job"})
+            return
+        }
+        results = append(results, score)
+    }
+
     c.JSON(http.StatusOK, gin.H{"data": results})
 }
 
@@ -64,12 +81,14 @@ func StartJobMatchScoreCalculation(c *gin.Context, db *mongo.Database, userID st
 		return fmt.Errorf("failed to fetch seeker data: %v", err)
 	}
 
+	location := repository.LoadLocationProfile(c, db, seeker)
+
 	preferredTitles := repository.CollectPreferredTitles(seeker)
 	if len(preferredTitles) == 0 {
 		return fmt.Errorf("no preferred job titles found for seeker")
 	}
 
-	filter := repository.BuildJobFilter(preferredTitles, nil, "")
+	filter := repository.BuildJobFilter(preferredTitles, nil, "", repository.DefaultPostedWithinDays)
 	cursor, err := db.Collection("jobs").Find(c, filter)
 	if err != nil {
 		return fmt.Errorf("failed to query jobs: %v", err)
@@ -113,7 +132,7 @@ func StartJobMatchScoreCalculation(c *gin.Context, db *mongo.Database, userID st
 				}
 
 				// 2️⃣ Compute match score
-				score, err := CalculateMatchScore(seeker, job)
+				score, err := CalculateMatchScore(seeker, job, location)
 				if err != nil {
 					errChan <- fmt.Errorf("❌ Error calculating score for job %s: %v", job.JobID, err)
 					continue
@@ -152,17 +171,92 @@ func StartJobMatchScoreCalculation(c *gin.Context, db *mongo.Database, userID st
 	return nil
 }
 
+// RescoreMatchScores recalculates the seeker's stored match scores in place
+// after the data behind them changed, e.g. the location preferences. Scores
+// of jobs that no longer exist are left as they are.
+func RescoreMatchScores(ctx context.Context, db *mongo.Database, userID string) error {
+    seeker, err := repository.GetSeekerData(db, userID)
+    if err != nil {
+        return fmt.Errorf("failed to fetch seeker data: %v", err)
+    }
+    location := repository.LoadLocationProfile(ctx, db, seeker)
+    store := repository.NewJobStore(db)
+
+    matchCollection := db.Collection("match_scores")
+    cursor, err := matchCollection.Find(ctx, bson.M{"auth_user_id": userID}, options.Find().SetProjection(bson.M{"job_id": 1}))
+    if err != nil {
+        return err
+    }
+    defer cursor.Close(ctx)
+
+    var updates []mongo.WriteModel
+    for cursor.Next(ctx) {
+        var ms models.MatchScore
+        if err := cursor.Decode(&ms); err != nil {
+            continue
+        }
+        job, err := store.Get(ctx, ms.JobID)
+        if err != nil {
+            continue
+        }
+        score, err := CalculateMatchScore(seeker, job.Job, location)
+        if err != nil {
+            continue
+        }
+        updates = append(updates, mongo.NewUpdateOneModel().
+            SetFilter(bson.M{"auth_user_id": userID, "job_id": ms.JobID}).
+            SetUpdate(bson.M{"$set": bson.M{"match_score": score}}))
+    }
+    if err := cursor.Err(); err != nil {
+        return err
+    }
+    if len(updates) == 0 {
+        return nil
+    }
+    _, err = matchCollection.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+    return err
+}
+
+// ScoreJob scores one internal or external job for the seeker and stores
+// the score, replacing an earlier one.
+func ScoreJob(ctx context.Context, db *mongo.Database, userID, jobID string) (models.MatchScore, error) {
+    job, err := repository.NewJobStore(db).Get(ctx, jobID)
+    if err != nil {
+        return models.MatchScore{}, err
+    }
+    seeker, err := repository.GetSeekerData(db, userID)
+    if err != nil {
+        return models.MatchScore{}, fmt.Errorf("failed to fetch seeker data: %v", err)
+    }
+
+    score, err := CalculateMatchScore(seeker, job.Job, repository.LoadLocationProfile(ctx, db, seeker))
+    if err != nil {
+        return models.MatchScore{}, err
+    }
+    result := models.MatchScore{
+        AuthUserID: userID,
+        JobID:      jobID,
+        MatchScore: score,
+        CreatedAt:  time.Now().UTC(),
+    }
+    _, err = db.Collection("match_scores").ReplaceOne(ctx,
+        bson.M{"auth_user_id": userID, "job_id": jobID}, result, options.Replace().SetUpsert(true))
+    return result, err
+}
+
 
 // Configuration: section weights sum to 1.0
 var (
     skillsWeight = 1.0
+    // When the job's location can be judged, it takes this share from skills
+    locationWeight = 0.2
     // certsWeight  = 0.3
     // langsWeight  = 0.2
 )
 
 // CalculateMatchScore returns a match score (0.0–1.0)
-// using keyword-based matching per section.
-func CalculateMatchScore(seeker models.Seeker, job models.Job) (float64, error) {
+// using keyword-based matching per section, adjusted by location fit.
+func CalculateMatchScore(seeker models.Seeker, job models.Job, location repository.LocationProfile) (float64, error) {
     // 1. Token extraction (unchanged)
     // certificateObjs, _ := repository.GetCertificates(&seeker)
     // languageObjs, _ := repository.GetLanguages(&seeker)
@@ -195,19 +289,22 @@ func CalculateMatchScore(seeker models.Seeker, job models.Job) (float64, error)
     // }
 
     // 2. Prepare job text once
-    jobText := strings.ToLower(job.Title + " " + job.JobDescription + " " + job.Skills)
+    jobDoc := skills.Default().NewDocument(job.Title + " " + job.JobDescription + " " + job.Skills)
 	// fmt.Println("🔍 Skills Tokens:", skillsTokens)
     // fmt.Println("🔍 Certificate Tokens:", certTokens)
     // fmt.Println("🔍 Language Tokens:", langTokens)
     // fmt.Println("🔍 Job Text snippet:", jobText[:min(len(jobText), 20)])
 
     // 3. Compute per-section scores
-    skillScore := keywordMatch(skillsTokens, jobText)
+    skillScore := keywordMatch(skillsTokens, jobDoc)
     // certScore := keywordMatch(certTokens, jobText)
     // langScore := keywordMatch(langTokens, jobText)
 	// fmt.Printf("skill: %.2f, cert: %.2f, lang: %.2f\n", skillScore, certScore, langScore)
     // 4. Weighted aggregation
-    raw := skillScore*skillsWeight 
+    raw := skillScore*skillsWeight
+    if locScore, ok := repository.LocationScore(location, job); ok {
+        raw = skillScore*(skillsWeight-locationWeight) + locScore*locationWeight
+    }
     scaled := 60 + raw*40
     final := math.Round(scaled*100) / 100
 
@@ -233,30 +330,22 @@ func toLower(input []string) []string {
     return out
 }
 
-// keywordMatch computes how many unique tokens appear in jobText.
+// keywordMatch computes how many unique skills the job mentions. Skills are
+// matched on their canonical taxonomy entry ("Golang" matches "Go") and on
+// whole words otherwise ("Go" does not match "Google").
 // Returns match rate in [0,1].
-func keywordMatch(tokens []string, jobText string) float64 {
-    if len(tokens) == 0 {
+func keywordMatch(tokens []string, jobDoc *skills.Document) float64 {
+    unique := skills.Default().Canonicalize(tokens)
+    if len(unique) == 0 {
         return 0.0
     }
-    seen := make(map[string]struct{})
     matches := 0
-
-    for _, t := range tokens {
-        t = strings.ToLower(strings.TrimSpace(t))
-        if t == "" {
-            continue
-        }
-        if _, ok := seen[t]; ok {
-            continue
-        }
-        seen[t] = struct{}{}
-
-        if strings.Contains(jobText, t) {
+    for _, t := range unique {
+        if jobDoc.Has(t) {
             matches++
         }
     }
-    return float64(matches) / float64(len(seen))
+    return float64(matches) / float64(len(unique))
 }
 
 
@@ -0,0 +1,157 @@
+package jobs
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/models"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GET /b1/api/jobs/search?q=...&job_type=...&location=...&sort=relevance|date|match_score
+func JobSearchHandler(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	var query dto.JobSearchQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search parameters"})
+		return
+	}
+	query.Q = strings.TrimSpace(query.Q)
+	query.PostedWithinDays = repository.ParsePostedWithinDays(c)
+	switch query.Sort {
+	case "", repository.JobSearchSortRelevance, repository.JobSearchSortDate, repository.JobSearchSortMatchScore:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be one of relevance, date, match_score"})
+		return
+	}
+
+	pagination := c.MustGet("pagination").(gin.H)
+	offset := pagination["offset"].(int)
+	limit := pagination["limit"].(int)
+
+	result, err := repository.SearchJobs(c, db, userID, query, offset, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error searching jobs"})
+		return
+	}
+
+	seeker, err := repository.GetSeekerData(db, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching seeker data"})
+		return
+	}
+
+	// Selected applications for the jobs on this page
+	pageJobIDs := make([]string, 0, len(result.Results))
+	for _, r := range result.Results {
+		pageJobIDs = append(pageJobIDs, r.JobID)
+	}
+	selectedMap := make(map[string]models.SelectedJobApplication)
+	if len(pageJobIDs) > 0 {
+		selectedCursor, err := db.Collection("selected_job_applications").Find(c, bson.M{
+			"auth_user_id": userID,
+			"job_id":       bson.M{"$in": pageJobIDs},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching selected applications"})
+			return
+		}
+		defer selectedCursor.Close(c)
+		for selectedCursor.Next(c) {
+			var s models.SelectedJobApplication
+			if err := selectedCursor.Decode(&s); err != nil {
+				continue
+			}
+			selectedMap[s.JobID] = s
+		}
+	}
+
+	jobs := make([]dto.JobDTO, 0, len(result.Results))
+	for i, r := range result.Results {
+		selected, isSelected := selectedMap[r.JobID]
+		jobs = append(jobs, dto.JobDTO{
+			Source:      "search",
+			ID:          uint(offset + i + 1),
+			JobID:       r.JobID,
+			Title:       r.Title,
+			Company:     r.Company,
+			Location:    r.Location,
+			PostedDate:  r.PostedDate.Format("2006-01-02"),
+			Processed:   r.Processed,
+			JobType:     r.JobType,
+			Skills:      r.Skills,
+			UserSkills:  seeker.KeySkills,
+			MatchScore:  int(r.MatchScore),
+			Description: r.JobDescription,
+			JobLang:     r.JobLang,
+			JobTitle:    r.JobTitle,
+			Selected:    isSelected,
+			Relevance:   r.TextScore,
+
+			LinkViewed:  selected.ViewLink,
+			CvGenerated: selected.CvGenerated,
+			ClGenerated: selected.CoverLetterGenerated,
+		})
+	}
+
+	// Pagination links keep every search parameter except offset/limit
+	total := result.TotalCount()
+	params := url.Values{}
+	for key, values := range c.Request.URL.Query() {
+		if key == "offset" || key == "limit" {
+			continue
+		}
+		params[key] = values
+	}
+	buildLink := func(off int) string {
+		params.Set("offset", fmt.Sprint(off))
+		params.Set("limit", fmt.Sprint(limit))
+		return "/b1/api/jobs/search?" + params.Encode()
+	}
+
+	next := ""
+	if offset+limit < total {
+		next = buildLink(offset + limit)
+	}
+	prev := ""
+	if offset > 0 {
+		prevOffset := offset - limit
+		if prevOffset < 0 {
+			prevOffset = 0
+		}
+		prev = buildLink(prevOffset)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pagination": gin.H{
+			"total":    total,
+			"next":     next,
+			"prev":     prev,
+			"current":  (offset / limit) + 1,
+			"per_page": limit,
+		},
+		"facets": dto.JobSearchFacets{
+			JobType:      nonNilFacets(result.JobType),
+			Location:     nonNilFacets(result.Location),
+			JobLang:      nonNilFacets(result.JobLanguage),
+			Company:      nonNilFacets(result.Company),
+			PostedWithin: result.PostedWithinFacet(),
+		},
+		"jobs": jobs,
+	})
+}
+
+func nonNilFacets(f []dto.FacetCount) []dto.FacetCount {
+	if f == nil {
+		return []dto.FacetCount{}
+	}
+	return f
+}
@@ -2,16 +2,17 @@ package jobs
 
 import (
 	"RAAS/internal/dto"
+	"RAAS/internal/geo"
 	"RAAS/internal/handlers/repository"
 	"RAAS/internal/models"
 	"fmt"
+	"math"
 	"net/http"
-	"time"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 func JobRetrievalHandler(c *gin.Context) {
@@ -36,10 +37,6 @@ func JobRetrievalHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching applied job data"})
 		return
 	}
-	appliedSet := make(map[string]bool)
-	for _, id := range appliedJobIDs {
-		appliedSet[id] = true
-	}
 
 	// Pagination
 	pagination := c.MustGet("pagination").(gin.H)
@@ -48,83 +45,138 @@ func JobRetrievalHandler(c *gin.Context) {
 
 	// Check if recommended filter is requested
 	recommended := c.Query("recommended") == "true"
+	postedWithinDays := repository.ParsePostedWithinDays(c)
+	postedSince := repository.PostedSince(postedWithinDays)
 
 	// Step 1: Get all match scores for user, optionally filtered by match_score >= 80
 	// Step 1: Get all match scores for user, optionally filtered by match_score >= 80
 	scoreFilter := bson.M{
 		"auth_user_id": userID,
 		"created_at": bson.M{
-			"$gte": time.Now().AddDate(0, 0, -14),
+			"$gte": postedSince,
 		},
 	}
 	if recommended {
 		scoreFilter["match_score"] = bson.M{"$gte": 80}
 	}
 
-	matchCursor, err := db.Collection("match_scores").
-		Find(c,
-			scoreFilter,
-			options.Find().SetSort(bson.D{{Key: "match_score", Value: -1}}),
-		)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match scores"})
-		return
-	}
-	defer matchCursor.Close(c)
-
-	var scores []models.MatchScore
-	if err := matchCursor.All(c, &scores); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding match scores"})
-		return
-	}
-
-	// Step 2: Filter out applied jobs and collect job IDs in order
-	var matchedJobIDs []string
-	for _, ms := range scores {
-		if !appliedSet[ms.JobID] {
-			matchedJobIDs = append(matchedJobIDs, ms.JobID)
-		}
-	}
-
-	total := len(matchedJobIDs)
-	if total == 0 {
-		c.JSON(http.StatusOK, gin.H{
-			"pagination": gin.H{
-				"total":    0,
-				"next":     "",
-				"prev":     "",
-				"current":  1,
-				"per_page": limit,
-			},
-			"jobs": []dto.JobDTO{},
-		})
-		return
-	}
-
-	// Step 3: Paginate the job IDs
-	end := offset + limit
-	if end > total {
-		end = total
-	}
-	pagedJobIDs := matchedJobIDs[offset:end]
-
-	// Step 4: Build filter for jobs
+	// Build the job filters; they apply before paging so pages come back full
 	filter := bson.M{
-		"job_id": bson.M{"$in": pagedJobIDs},
+		"is_active": bson.M{"$ne": false},
 		"posted_date": bson.M{
-			"$gte": time.Now().AddDate(0, 0, -14).Format("2006-01-02"),
+			"$gte": postedSince,
 		},
 	}
 	if lang := c.Query("job_language"); lang != "" {
-		filter["job_language"] = bson.M{"$regex": lang, "$options": "i"}
+		filter["job_language"] = repository.ContainsRegex(lang)
 	}
 	if title := c.Query("title"); title != "" {
-		filter["title"] = bson.M{"$regex": title, "$options": "i"}
+		filter["title"] = repository.ContainsRegex(title)
 	}
 	if company := c.Query("company"); company != "" {
-		filter["company"] = bson.M{"$regex": company, "$options": "i"}
+		filter["company"] = repository.ContainsRegex(company)
+	}
+
+	// Location preferences (radius / remote), overridable per request
+	location := repository.LoadLocationProfile(c, db, seeker)
+	if radius, err := strconv.Atoi(c.Query("radius_km")); err == nil && radius >= 0 {
+		location.RadiusKm = radius
+		location.WillingToRelocate = false
+	}
+	if mode := c.Query("work_mode"); geo.ValidWorkModePreference(mode) {
+		location.WorkMode = mode
 	}
+	if locFilter := repository.BuildLocationFilter(location); locFilter != nil {
+		filter["$and"] = bson.A{locFilter}
+	}
+
+	if len(appliedJobIDs) > 0 {
+		scoreFilter["job_id"] = bson.M{"$nin": appliedJobIDs}
+	}
+	// Scores are joined with their job in Mongo, so pages only hold jobs passing the filters
+	jobStages := jobFilterStages(filter)
+
+	cursorMode, pageCursor, _ := repository.CursorRequest(c)
 
+	var pagedJobIDs []string
+	var cursorPage repository.CursorPage
+	total, end := 0, 0
+	if cursorMode {
+		// Keyset pagination straight on match_scores, so pages stay stable as scores change
+		var pageScores []models.MatchScore
+		pageScores, cursorPage, err = repository.AggregatePageByCursor[models.MatchScore](c, db.Collection("match_scores"), scoreFilter, "match_score", pageCursor, limit, jobStages)
+		if err == repository.ErrCursorMismatch {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination cursor"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match scores"})
+			return
+		}
+		for _, ms := range pageScores {
+			pagedJobIDs = append(pagedJobIDs, ms.JobID)
+		}
+	} else {
+		// Step 2: Count the matching jobs and take the page, in score order
+		pipeline := mongo.Pipeline{
+			{{Key: "$match", Value: scoreFilter}},
+			{{Key: "$sort", Value: bson.D{{Key: "match_score", Value: -1}, {Key: "_id", Value: -1}}}},
+		}
+		pipeline = append(pipeline, jobStages...)
+		pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+			"total": bson.A{bson.M{"$count": "n"}},
+			"page":  bson.A{bson.M{"$skip": offset}, bson.M{"$limit": limit}},
+		}}})
+		matchCursor, err := db.Collection("match_scores").Aggregate(c, pipeline)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching match scores"})
+			return
+		}
+		defer matchCursor.Close(c)
+
+		var result []struct {
+			Total []struct {
+				N int `bson:"n"`
+			} `bson:"total"`
+			Page []models.MatchScore `bson:"page"`
+		}
+		if err := matchCursor.All(c, &result); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error decoding match scores"})
+			return
+		}
+		if len(result) > 0 && len(result[0].Total) > 0 {
+			total = result[0].Total[0].N
+		}
+
+		if total == 0 {
+			c.JSON(http.StatusOK, gin.H{
+				"pagination": gin.H{
+					"total":    0,
+					"next":     "",
+					"prev":     "",
+					"current":  1,
+					"per_page": limit,
+				},
+				"jobs": []dto.JobDTO{},
+			})
+			return
+		}
+
+		// Step 3: Paginate the job IDs
+		if offset > total {
+			offset = total
+		}
+		end = offset + limit
+		if end > total {
+			end = total
+		}
+		for _, ms := range result[0].Page {
+			pagedJobIDs = append(pagedJobIDs, ms.JobID)
+		}
+	}
+
+	// Step 4: Fetch the jobs of the page
+	filter["job_id"] = bson.M{"$in": pagedJobIDs}
 	jobCursor, err := db.Collection("jobs").Find(c, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Error fetching job data"})
@@ -183,7 +235,7 @@ func JobRetrievalHandler(c *gin.Context) {
 			Title:       job.Title,
 			Company:     job.Company,
 			Location:    job.Location,
-			PostedDate:  job.PostedDate,
+			PostedDate:  job.PostedDate.Format("2006-01-02"),
 			Processed:   job.Processed,
 			JobType:     job.JobType,
 			Skills:      job.Skills,
@@ -193,6 +245,8 @@ func JobRetrievalHandler(c *gin.Context) {
 			JobLang:     job.JobLang,
 			JobTitle:    job.JobTitle,
 			Selected:    isSelected,
+			WorkMode:    job.WorkMode,
+			DistanceKm:  distanceKm(location, job),
 
 			LinkViewed:   selected.ViewLink,
 			CvGenerated:  selected.CvGenerated,
@@ -201,11 +255,25 @@ func JobRetrievalHandler(c *gin.Context) {
 		index++
 	}
 
+	if jobs == nil {
+		jobs = []dto.JobDTO{}
+	}
+	if cursorMode {
+		c.JSON(http.StatusOK, gin.H{
+			"pagination": repository.CursorPagination(c, cursorPage),
+			"jobs":       jobs,
+		})
+		return
+	}
+
 	// Step 6: Build pagination
 	querySuffix := ""
 	if recommended {
 		querySuffix = "&recommended=true"
 	}
+	if postedWithinDays != repository.DefaultPostedWithinDays {
+		querySuffix += fmt.Sprintf("&posted_within_days=%d", postedWithinDays)
+	}
 
 	nextPage := ""
 	if end < total {
@@ -231,3 +299,31 @@ func JobRetrievalHandler(c *gin.Context) {
 		"jobs": jobs,
 	})
 }
+
+// jobFilterStages keeps the match scores whose job passes filter.
+func jobFilterStages(filter bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from": "jobs",
+			"let":  bson.M{"job_id": "$job_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$eq": bson.A{"$job_id", "$$job_id"}}}},
+				bson.M{"$match": filter},
+				bson.M{"$limit": 1},
+				bson.M{"$project": bson.M{"_id": 1}},
+			},
+			"as": "_job",
+		}}},
+		{{Key: "$match", Value: bson.M{"_job": bson.M{"$ne": bson.A{}}}}},
+		{{Key: "$project", Value: bson.M{"_job": 0}}},
+	}
+}
+
+// distanceKm returns the rounded distance from the seeker's home to the job, if both are known.
+func distanceKm(location repository.LocationProfile, job models.Job) *float64 {
+	if location.Home == nil || job.GeoLocation == nil {
+		return nil
+	}
+	d := math.Round(geo.DistanceKm(location.Home, job.GeoLocation))
+	return &d
+}
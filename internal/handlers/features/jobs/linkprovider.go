@@ -2,7 +2,7 @@ package jobs
 
 import (
 
-	"RAAS/internal/models"
+	"RAAS/internal/handlers/repository"
 
 	"fmt"
 	"net/http"
@@ -51,13 +51,11 @@ func (h *LinkProviderHandler) PostAndGetLink(c *gin.Context) {
 	}
 
 
-	// Retrieve JobLink from the unified Job model
-	jobCollection := db.Collection("jobs")
-	var job models.Job
-	err := jobCollection.FindOne(c, bson.M{"job_id": jobID}).Decode(&job)
+	// Retrieve JobLink from the internal or external job
+	job, app, err := repository.NewJobStore(db).ForApplication(c, authUserID, jobID)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			fmt.Println("🚫 Job not found in jobs table")
+		if err == repository.ErrJobNotFound {
+			fmt.Println("🚫 Job not found in jobs or external_jobs")
 			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 			return
 		}
@@ -66,12 +64,19 @@ func (h *LinkProviderHandler) PostAndGetLink(c *gin.Context) {
 		return
 	}
 
-	// Build and return the response
+	// Build and return the response; external jobs only have the link the
+	// seeker tracked with the application
 	response := LinkResponseDTO{
 		JobID:   job.JobID,
 		JobLink: job.JobLink,
 		Source:  job.Source,
 	}
+	if response.JobLink == "" {
+		response.JobLink = app.JobLink
+	}
+	if response.Source == "" {
+		response.Source = job.Origin
+	}
 
 	// Update view_link = true
 	_, err = selectedJobCollection.UpdateOne(c,
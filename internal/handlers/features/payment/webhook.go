@@ -77,6 +77,8 @@ func (h *PaymentHandler) Webhook(c *gin.Context) {
 		"subscription_period":           plan.Period,
 		"external_application_count":    plan.ExternalLimit,
 		"internal_application_count":    plan.InternalLimit,
+		"external_application_credit":   0,
+		"internal_application_credit":   0,
 		"proficiency_test":              plan.ProficiencyLimit,
 
 		// use invoice's first line period as subscription interval
@@ -111,6 +113,8 @@ func (h *PaymentHandler) Webhook(c *gin.Context) {
         "$unset": bson.M{
             "external_application_count":  "",
             "internal_application_count":  "",
+            "external_application_credit": "",
+            "internal_application_credit": "",
             "proficiency_test":            "",
             "subscription_interval_start": "",
             "subscription_interval_end":   "",
@@ -0,0 +1,132 @@
+package settings
+
+import (
+	"RAAS/internal/models"
+
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OnboardingFlowHandler lets admins manage onboarding flow definitions.
+type OnboardingFlowHandler struct{}
+
+func NewOnboardingFlowHandler() *OnboardingFlowHandler {
+	return &OnboardingFlowHandler{}
+}
+
+type onboardingFlowRequest struct {
+	Tier           string                  `json:"tier"`
+	Experiment     string                  `json:"experiment"`
+	RolloutPercent int                     `json:"rollout_percent"`
+	Steps          []models.OnboardingStep `json:"steps" binding:"required"`
+	IsActive       bool                    `json:"is_active"`
+}
+
+// GET /b1/admin/onboarding-flows
+// Lists stored flows; the built-in default is included when none is stored.
+func (h *OnboardingFlowHandler) ListFlows(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+
+	cursor, err := db.Collection(models.CollectionOnboardingFlows).Find(c, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch onboarding flows"})
+		return
+	}
+	flows := []models.OnboardingFlow{}
+	if err := cursor.All(c, &flows); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode onboarding flows"})
+		return
+	}
+
+	hasDefault := false
+	for _, f := range flows {
+		hasDefault = hasDefault || (f.Key == models.DefaultOnboardingFlowKey && f.IsActive)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"flows":            flows,
+		"built_in_default": models.DefaultOnboardingFlow(),
+		"default_stored":   hasDefault,
+	})
+}
+
+// PUT /b1/admin/onboarding-flows/:key
+// Creates or replaces a flow. Users keep the flow they were assigned at
+// sign-up, so changes apply to their remaining steps.
+func (h *OnboardingFlowHandler) UpsertFlow(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	key := strings.TrimSpace(c.Param("key"))
+
+	var req onboardingFlowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+	if msg := validateOnboardingFlow(key, req); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	now := time.Now()
+	var flow models.OnboardingFlow
+	err := db.Collection(models.CollectionOnboardingFlows).FindOneAndUpdate(c,
+		bson.M{"key": key},
+		bson.M{
+			"$set": bson.M{
+				"tier":            req.Tier,
+				"experiment":      req.Experiment,
+				"rollout_percent": req.RolloutPercent,
+				"steps":           req.Steps,
+				"is_active":       req.IsActive,
+				"updated_at":      now,
+			},
+			"$setOnInsert": bson.M{"key": key, "created_at": now},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&flow)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save onboarding flow"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Onboarding flow saved", "flow": flow})
+}
+
+// validateOnboardingFlow returns an error message for an invalid flow definition.
+func validateOnboardingFlow(key string, req onboardingFlowRequest) string {
+	if key == "" {
+		return "Flow key is required"
+	}
+	if len(req.Steps) == 0 {
+		return "A flow needs at least one step"
+	}
+	if req.RolloutPercent < 0 || req.RolloutPercent > 100 {
+		return "rollout_percent must be between 0 and 100"
+	}
+	if req.Experiment == "" && req.RolloutPercent != 0 {
+		return "rollout_percent only applies to experiments"
+	}
+	if key == models.DefaultOnboardingFlowKey && (req.Tier != "" || req.Experiment != "") {
+		return "The default flow cannot target a tier or experiment"
+	}
+
+	seen := map[string]bool{}
+	for _, s := range req.Steps {
+		if s.Key == "" {
+			return "Every step needs a key"
+		}
+		if seen[s.Key] {
+			return fmt.Sprintf("Step %q is listed twice", s.Key)
+		}
+		seen[s.Key] = true
+	}
+	return ""
+}
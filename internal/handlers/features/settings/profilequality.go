@@ -0,0 +1,37 @@
+package settings
+
+import (
+	"RAAS/internal/handlers/repository"
+
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// GET /b1/jobprofile/quality
+// Ranked suggestions for improving the profile, with estimated match-score impact.
+func (h *SeekerHandler) GetProfileQuality(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	userID := c.MustGet("userID").(string)
+
+	seeker, err := repository.GetSeekerData(db, userID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch seeker"})
+		}
+		return
+	}
+
+	quality, err := repository.AnalyzeProfileQuality(c, db, seeker)
+	if err != nil {
+		log.Printf("❌ Profile quality analysis failed for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to analyse profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, quality)
+}
@@ -2,9 +2,11 @@ package settings
 
 import (
 	"RAAS/internal/dto"
+	"RAAS/internal/handlers/features/jobs"
 	"RAAS/internal/models"
 
 	"context"
+	"log"
 	"net/http"
 	"time"
 
@@ -70,6 +72,9 @@ func (h *SettingsHandler) GetPreferences(c *gin.Context) {
 		Language:     prefs.Language,
 		Timezone:     prefs.Timezone,
 		CookiePolicy: prefs.CookiePolicy,
+		PreferredRadiusKm:  prefs.PreferredRadiusKm,
+		WillingToRelocate:  prefs.WillingToRelocate,
+		WorkModePreference: prefs.WorkModePreference,
 		CreatedAt:    prefs.CreatedAt,
 		UpdatedAt:    prefs.UpdatedAt,
 	}
@@ -91,19 +96,37 @@ func (h *SettingsHandler) UpdatePreferences(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	set := bson.M{
+		"language":      req.Language,
+		"timezone":      req.Timezone,
+		"cookie_policy": req.CookiePolicy,
+		"updated_at":    time.Now(),
+	}
+	if req.PreferredRadiusKm != nil {
+		set["preferred_radius_km"] = *req.PreferredRadiusKm
+	}
+	if req.WillingToRelocate != nil {
+		set["willing_to_relocate"] = *req.WillingToRelocate
+	}
+	if req.WorkModePreference != nil {
+		set["work_mode_preference"] = *req.WorkModePreference
+	}
+
 	update := bson.M{
-		"$set": bson.M{
-			"language":      req.Language,
-			"timezone":      req.Timezone,
-			"cookie_policy": req.CookiePolicy,
-			"updated_at":    time.Now(),
-		},
+		"$set": set,
 		"$setOnInsert": bson.M{
 			"auth_user_id": userID,
 			"created_at":   time.Now(),
 		},
 	}
 
+	// Keep the stored location preferences to tell whether they change
+	var previous models.UserPreferences
+	if err := preferencesCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&previous); err != nil && err != mongo.ErrNoDocuments {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve preferences"})
+		return
+	}
+
 	opts := options.Update().SetUpsert(true)
 	res, err := preferencesCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update, opts)
 if err != nil {
@@ -111,6 +134,16 @@ if err != nil {
 	return
 }
 
+	// Location preferences feed the match score, so stored scores are rescored
+	locationChanged := (req.PreferredRadiusKm != nil && *req.PreferredRadiusKm != previous.PreferredRadiusKm) ||
+		(req.WillingToRelocate != nil && *req.WillingToRelocate != previous.WillingToRelocate) ||
+		(req.WorkModePreference != nil && *req.WorkModePreference != previous.WorkModePreference)
+	if locationChanged {
+		if err := jobs.RescoreMatchScores(c, db, userID); err != nil {
+			log.Printf("⚠️ Failed to rescore match scores for %s: %v", userID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"issue":     "Preferences updated successfully",
 		"matched":     res.MatchedCount,
@@ -0,0 +1,150 @@
+package settings
+
+import (
+	"RAAS/internal/models"
+	"RAAS/internal/skills"
+
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SkillTaxonomyHandler lets admins edit the skills taxonomy. Edits are stored
+// as overrides of the bundled skills.
+type SkillTaxonomyHandler struct{}
+
+func NewSkillTaxonomyHandler() *SkillTaxonomyHandler {
+	return &SkillTaxonomyHandler{}
+}
+
+type skillRequest struct {
+	Name     string   `json:"name" binding:"required"`
+	Aliases  []string `json:"aliases"`
+	Category string   `json:"category" binding:"required"`
+	Related  []string `json:"related"`
+}
+
+var skillIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// GET /b1/admin/skills
+// Lists the active taxonomy together with the stored overrides.
+func (h *SkillTaxonomyHandler) ListSkills(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+
+	cursor, err := db.Collection(models.CollectionSkillTaxonomy).Find(c, bson.M{},
+		options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch skill overrides"})
+		return
+	}
+	overrides := []skills.Skill{}
+	if err := cursor.All(c, &overrides); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to decode skill overrides"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"skills":    skills.Default().All(),
+		"overrides": overrides,
+	})
+}
+
+// PUT /b1/admin/skills/:id
+// Creates a skill or replaces a bundled one.
+func (h *SkillTaxonomyHandler) UpsertSkill(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	id := strings.TrimSpace(c.Param("id"))
+
+	var req skillRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request payload"})
+		return
+	}
+	if !skillIDPattern.MatchString(id) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Skill id must be lowercase letters, digits, '-' or '_'"})
+		return
+	}
+	taxonomy := skills.Default()
+	for _, r := range req.Related {
+		if _, ok := taxonomy.Get(r); !ok && r != id {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown related skill: " + r})
+			return
+		}
+	}
+
+	skill := skills.Skill{
+		ID:       id,
+		Name:     strings.TrimSpace(req.Name),
+		Aliases:  cleanList(req.Aliases),
+		Category: strings.TrimSpace(req.Category),
+		Related:  cleanList(req.Related),
+	}
+	if _, err := db.Collection(models.CollectionSkillTaxonomy).ReplaceOne(c,
+		bson.M{"_id": id}, skill, options.Replace().SetUpsert(true)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save skill"})
+		return
+	}
+
+	applyTaxonomyChange(c, db)
+	c.JSON(http.StatusOK, gin.H{"issue": "Skill saved", "skill": skill})
+}
+
+// DELETE /b1/admin/skills/:id
+// Removes a custom skill, or hides a bundled one.
+func (h *SkillTaxonomyHandler) DeleteSkill(c *gin.Context) {
+	db := c.MustGet("db").(*mongo.Database)
+	id := strings.TrimSpace(c.Param("id"))
+	coll := db.Collection(models.CollectionSkillTaxonomy)
+
+	bundled := false
+	for _, s := range skills.Bundled() {
+		bundled = bundled || s.ID == id
+	}
+
+	var err error
+	if bundled {
+		_, err = coll.ReplaceOne(c, bson.M{"_id": id}, skills.Skill{ID: id, Disabled: true}, options.Replace().SetUpsert(true))
+	} else {
+		var res *mongo.DeleteResult
+		res, err = coll.DeleteOne(c, bson.M{"_id": id})
+		if err == nil && res.DeletedCount == 0 {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Skill not found"})
+			return
+		}
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete skill"})
+		return
+	}
+
+	applyTaxonomyChange(c, db)
+	c.JSON(http.StatusOK, gin.H{"issue": "Skill deleted"})
+}
+
+// applyTaxonomyChange reloads the taxonomy and queues every job for
+// re-tagging by the job expiry worker.
+func applyTaxonomyChange(ctx context.Context, db *mongo.Database) {
+	if err := skills.Reload(ctx, db.Collection(models.CollectionSkillTaxonomy)); err != nil {
+		log.Printf("⚠️ Failed to reload skill taxonomy: %v", err)
+	}
+	if err := skills.ResetJobTags(ctx, db.Collection(models.CollectionJobs)); err != nil {
+		log.Printf("⚠️ Failed to reset job skill tags: %v", err)
+	}
+}
+
+func cleanList(in []string) []string {
+	out := []string{}
+	for _, v := range in {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
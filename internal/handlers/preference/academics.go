@@ -5,7 +5,6 @@ import (
 	"log"
 	"net/http"
 	"time"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
@@ -40,57 +39,23 @@ func (h *AcademicsHandler) CreateAcademics(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// 1️⃣ Fetch seeker
-	var seeker models.Seeker
-	if err := seekers.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+	// 1️⃣ Add entry
+	item := repository.NewAcademics(input)
+	if err := repository.PushSectionItem(ctx, seekers, userID, repository.SectionAcademics, item); err != nil {
 		status := http.StatusInternalServerError
-		issue := "Something went wrong while retrieving your profile. Please try again."
-
+		issue := "Failed to update your education records. Please retry."
 		if err == mongo.ErrNoDocuments {
 			status = http.StatusNotFound
 			issue = "We couldn't find your account. Please contact support."
 		}
-
-		log.Printf("❌ DB fetch error [CreateAcademics] user=%s: %v", userID, err)
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
-		return
-	}
-
-	// 2️⃣ Update academics
-	if err := repository.AppendToAcademics(&seeker, input); err != nil {
-		log.Printf("❌ Processing error [CreateAcademics] user=%s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Could not save your educational record. Try again shortly.",
-		})
-		return
-	}
-
-	update := bson.M{"$set": bson.M{"academics": seeker.Academics}}
-	updateResult, err := seekers.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update)
-	if err != nil || updateResult.MatchedCount == 0 {
-		status := http.StatusInternalServerError
-		issue := "Failed to update your education records. Please retry."
-
-		if updateResult.MatchedCount == 0 {
-			status = http.StatusNotFound
-			issue = "Your account couldn't be updated. Please refresh and try again."
-		}
-
 		log.Printf("❌ DB update error [CreateAcademics] user=%s: %v", userID, err)
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
+		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
 		return
 	}
 
-	// 3️⃣ Update timeline
+	// 2️⃣ Update timeline
 	if err := func() error {
-		_, _, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, userID, "academics_completed")
+		_, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepAcademics)
 		return err
 	}(); err != nil {
 		log.Printf("⚠️ Timeline update error [CreateAcademics] user=%s: %v", userID, err)
@@ -104,6 +69,7 @@ func (h *AcademicsHandler) CreateAcademics(c *gin.Context) {
 	// ✅ Success
 	c.JSON(http.StatusOK, gin.H{
 		"issue": "Academics added successfully",
+		"id":    item.ID,
 	})
 }
 
@@ -158,16 +124,6 @@ func (h *AcademicsHandler) UpdateAcademics(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid academics index",
-		})
-		return
-	}
-
 	var input dto.AcademicsRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -180,54 +136,13 @@ func (h *AcademicsHandler) UpdateAcademics(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Academics []bson.M `bson:"academics"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionAcademics, "Academics entry")
+	if !ok {
 		return
 	}
 
-	if index > len(seeker.Academics) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Academics index is out of range",
-		})
-		return
-	}
-
-	seeker.Academics[index-1] = bson.M{
-		"degree":         input.Degree,
-		"institution":    input.Institution,
-		"city":           input.City,
-		"field_of_study": input.FieldOfStudy,
-		"start_date":     input.StartDate,
-		"end_date":       input.EndDate,
-		"achievements":   input.Description,
-		"updated_at":     time.Now(),
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"academics": seeker.Academics,
-		},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to update academics entry",
-		})
+	if err := repository.ReplaceSectionItem(ctx, seekersCollection, userID, repository.SectionAcademics, id, repository.NewAcademics(input)); err != nil {
+		sectionItemError(c, err, "Academics entry", "Failed to update academics entry")
 		return
 	}
 
@@ -242,58 +157,16 @@ func (h *AcademicsHandler) DeleteAcademics(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid academics index",
-		})
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Academics []bson.M `bson:"academics"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionAcademics, "Academics entry")
+	if !ok {
 		return
 	}
 
-	if index > len(seeker.Academics) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Academics index is out of range",
-		})
-		return
-	}
-
-	seeker.Academics = append(seeker.Academics[:index-1], seeker.Academics[index:]...)
-
-	update := bson.M{
-		"$set": bson.M{
-			"academics": seeker.Academics,
-		},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to delete academics entry",
-		})
+	if err := repository.DeleteSectionItem(ctx, seekersCollection, userID, repository.SectionAcademics, id); err != nil {
+		sectionItemError(c, err, "Academics entry", "Failed to delete academics entry")
 		return
 	}
 
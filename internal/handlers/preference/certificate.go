@@ -5,7 +5,6 @@ import (
     "log"
     "net/http"
     "time"
-    "strconv"
     
     "RAAS/internal/dto"
     "RAAS/internal/handlers/repository"
@@ -13,7 +12,6 @@ import (
 
     "github.com/gin-gonic/gin"
     "go.mongodb.org/mongo-driver/bson"
-    "go.mongodb.org/mongo-driver/bson/primitive"
     "go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -40,40 +38,15 @@ func (h *CertificateHandler) CreateCertificate(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// 🔍 Fetch seeker
-	var seeker models.Seeker
-	if err := seekers.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+	// ➕ Add entry
+	item := repository.NewCertificate(input)
+	if err := repository.PushSectionItem(ctx, seekers, userID, repository.SectionCertificates, item); err != nil {
 		status := http.StatusInternalServerError
-		issue := "Failed to retrieve your profile. Try again later."
+		issue := "Failed to save your certificate. Please try again."
 		if err == mongo.ErrNoDocuments {
 			status = http.StatusNotFound
 			issue = "Could not find your account. Please log in again."
 		}
-		log.Printf("❌ DB fetch error [CreateCertificate] user=%s: %v", userID, err)
-		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
-		return
-	}
-
-	// ➕ Append to certificates
-	if err := repository.AppendToCertificates(&seeker, input); err != nil {
-		log.Printf("❌ Append error [CreateCertificate] user=%s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Could not save your certificate details. Try again shortly.",
-		})
-		return
-	}
-
-	// 📝 Save updated certificates
-	update := bson.M{"$set": bson.M{"certificates": seeker.Certificates}}
-	updateResult, err := seekers.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update)
-	if err != nil || updateResult.MatchedCount == 0 {
-		status := http.StatusInternalServerError
-		issue := "Failed to save your certificate. Please try again."
-		if updateResult.MatchedCount == 0 {
-			status = http.StatusNotFound
-			issue = "Your account was not found to update."
-		}
 		log.Printf("❌ DB update error [CreateCertificate] user=%s: %v", userID, err)
 		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
 		return
@@ -81,7 +54,7 @@ func (h *CertificateHandler) CreateCertificate(c *gin.Context) {
 
 	// ✅ Timeline update
 	if err := func() error {
-		_, _, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, userID, "certificates_completed")
+		_, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepCertificates)
 		return err
 	}(); err != nil {
 		log.Printf("⚠️ Timeline update error [CreateCertificate] user=%s: %v", userID, err)
@@ -93,7 +66,10 @@ func (h *CertificateHandler) CreateCertificate(c *gin.Context) {
 	}
 
 	// ✅ Success
-	c.JSON(http.StatusOK, gin.H{"issue": "Certificate added successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"issue": "Certificate added successfully",
+		"id":    item.ID,
+	})
 }
 
 
@@ -140,21 +116,15 @@ func (h *CertificateHandler) GetCertificates(c *gin.Context) {
 
     var response []dto.CertificateResponse
     for _, cert := range certificatesRaw {
-        name, _ := cert["certificate_name"].(string)
-        ctype, _ := cert["certificate_type"].(string)
-        providerPtr, _ := cert["provider"].(string)
-        completionRaw, _ := cert["completion_date"].(primitive.DateTime)
-        createdRaw, _ := cert["created_at"].(primitive.DateTime)
-        updatedRaw, _ := cert["updated_at"].(primitive.DateTime)
-
         response = append(response, dto.CertificateResponse{
+            ID:               cert.ID,
             AuthUserID:       userID,
-            CertificateName:  name,
-            CertificateType:  ctype,
-            Provider:         &providerPtr,
-            CompletionDate:   completionRaw.Time(),
-            CreatedAt:        createdRaw.Time(),
-            UpdatedAt:        updatedRaw.Time(),
+            CertificateName:  cert.CertificateName,
+            CertificateType:  cert.CertificateType,
+            Provider:         cert.Provider,
+            CompletionDate:   cert.CompletionDate,
+            CreatedAt:        cert.CreatedAt,
+            UpdatedAt:        cert.UpdatedAt,
         })
     }
 
@@ -166,16 +136,6 @@ func (h *CertificateHandler) UpdateCertificate(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid certificate index",
-		})
-		return
-	}
-
 	var input dto.CertificateRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -188,113 +148,41 @@ func (h *CertificateHandler) UpdateCertificate(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Certificates []bson.M `bson:"certificates"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
-		return
-	}
-
-	if index > len(seeker.Certificates) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Certificate index is out of range",
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionCertificates, "Certificate")
+	if !ok {
 		return
 	}
 
-	// Update certificate at index - 1
-	seeker.Certificates[index-1] = bson.M{
-		"certificate_name": input.CertificateName,
-		"certificate_type": input.CertificateType,
-		"provider":         input.Provider,
-		"completion_date":  input.CompletionDate,
-		"updated_at":       time.Now(),
-	}
-
-	update := bson.M{
-		"$set": bson.M{"certificates": seeker.Certificates},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to update certificate",
-		})
+	if err := repository.ReplaceSectionItem(ctx, seekersCollection, userID, repository.SectionCertificates, id, repository.NewCertificate(input)); err != nil {
+		sectionItemError(c, err, "Certificate", "Failed to update certificate")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"issue": "Certificate updated successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"issue": "Certificate updated successfully",
+	})
 }
 
+
 func (h *CertificateHandler) DeleteCertificate(c *gin.Context) {
 	userID := c.MustGet("userID").(string)
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid certificate index",
-		})
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Certificates []bson.M `bson:"certificates"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
-		return
-	}
-
-	if index > len(seeker.Certificates) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Certificate index is out of range",
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionCertificates, "Certificate")
+	if !ok {
 		return
 	}
 
-	seeker.Certificates = append(seeker.Certificates[:index-1], seeker.Certificates[index:]...)
-
-	update := bson.M{
-		"$set": bson.M{"certificates": seeker.Certificates},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to delete certificate",
-		})
+	if err := repository.DeleteSectionItem(ctx, seekersCollection, userID, repository.SectionCertificates, id); err != nil {
+		sectionItemError(c, err, "Certificate", "Failed to delete certificate")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"issue": "Certificate deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"issue": "Certificate deleted successfully",
+	})
 }
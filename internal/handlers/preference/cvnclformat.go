@@ -1,6 +1,8 @@
 package preference
 
 import (
+	"RAAS/internal/render"
+
 	"net/http"
 	"time"
 
@@ -14,22 +16,10 @@ type FormatRequest struct {
 	Format string `json:"format" binding:"required"`
 }
 
-// Allowed formats for CV and CL
-var allowedCvFormats = map[string]bool{
-	"minimalist": true,
-	"classic":    true,
-	"modern":     true,
-	"elegant":    true,
-	"creative":   true,
-}
+// Allowed formats for CV and CL, one per rendering template
+var allowedCvFormats = render.Formats(render.KindCV)
 
-var allowedClFormats = map[string]bool{
-	"formal":     true,
-	"casual":     true,
-	"concise":    true,
-	"narrative":  true,
-	"technical":  true,
-}
+var allowedClFormats = render.Formats(render.KindCoverLetter)
 
 // FormatHandler groups the format-related handlers
 type FormatHandler struct{}
@@ -1,18 +1,42 @@
 package preference
 
 import (
+	"archive/zip"
 	"bytes"
-	"encoding/json"
+	"context"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"strings"
+
+	"RAAS/internal/dto"
+	"RAAS/internal/handlers/features/jobs"
+	"RAAS/internal/handlers/repository"
+	"RAAS/internal/mlclient"
 
 	"github.com/gin-gonic/gin"
-	"RAAS/core/config"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
+const maxResumeFileBytes = 10 << 20 // 10 MB
+
+// Supported resume formats
+const (
+	resumeFormatPDF  = "pdf"
+	resumeFormatDOCX = "docx"
+)
+
+var resumeMimeTypes = map[string]string{
+	resumeFormatPDF:  "application/pdf",
+	resumeFormatDOCX: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
 type DataExtractionHandler struct{}
 
 // NewDataExtractionHandler creates a new instance of DataExtractionHandler
@@ -20,14 +44,22 @@ func NewDataExtractionHandler() *DataExtractionHandler {
 	return &DataExtractionHandler{}
 }
 
-// ExtractFromPDF handles the file upload and forwards the PDF to the resume API
-func (h *DataExtractionHandler) ExtractFromPDF(c *gin.Context) {
+// ExtractResume handles a PDF or DOCX upload: the resume API extracts it and
+// the result is mapped into a draft of profile entries for review.
+func (h *DataExtractionHandler) ExtractResume(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+	db := c.MustGet("db").(*mongo.Database)
+
 	// Parse the uploaded file
 	fileHeader, err := c.FormFile("file")
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or invalid file: " + err.Error()})
 		return
 	}
+	if fileHeader.Size > maxResumeFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "File must be at most 10 MB"})
+		return
+	}
 
 	// Open the file
 	file, err := fileHeader.Open()
@@ -37,68 +69,236 @@ func (h *DataExtractionHandler) ExtractFromPDF(c *gin.Context) {
 	}
 	defer file.Close()
 
+	data, err := io.ReadAll(io.LimitReader(file, maxResumeFileBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	format, text, err := resumeFormat(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "issue": "Upload your resume as a PDF or DOCX file."})
+		return
+	}
+
 	// Call the external resume API
-	response, err := CallResumeAPI(file, fileHeader.Filename)
+	response, err := CallResumeAPI(c, data, fileHeader.Filename, format, text)
+	if errors.Is(err, mlclient.ErrCircuitOpen) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Resume extraction is temporarily unavailable", "issue": "Please try again in a few minutes."})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Resume API error: " + err.Error()})
 		return
 	}
 
-	// Return the API response as JSON
-	c.JSON(http.StatusOK, response)
+	draft := repository.MapResumeExtraction(response)
+	imp, err := repository.CreateResumeImport(c, db, userID, filepath.Base(fileHeader.Filename), format, draft)
+	if err != nil {
+		log.Printf("❌ Failed to store resume import for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store the extracted resume"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"issue":  "Resume extracted. Review the draft and apply what you want to keep.",
+		"import": imp,
+	})
 }
 
-// UploadPDFToResumeAPI uploads the given file to the resume extractor API
-func callResumeAPI(file multipart.File, filename string) (map[string]interface{}, error) {
-	// Prepare multipart/form-data body
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+// GetResumeImport returns a stored draft
+func (h *DataExtractionHandler) GetResumeImport(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+	db := c.MustGet("db").(*mongo.Database)
 
-	part, err := writer.CreateFormFile("file", filepath.Base(filename))
+	id, err := primitive.ObjectIDFromHex(c.Param("import_id"))
 	if err != nil {
-		return nil, fmt.Errorf("create form file: %w", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import ID"})
+		return
 	}
 
-	_, err = io.Copy(part, file)
+	imp, err := repository.GetResumeImport(c, db, userID, id)
+	if errors.Is(err, repository.ErrResumeImportNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resume import not found", "issue": "The draft may have expired. Upload your resume again."})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resume import"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"import": imp})
+}
+
+// ApplyResumeImport saves all or part of a draft to the profile in one call
+func (h *DataExtractionHandler) ApplyResumeImport(c *gin.Context) {
+	userID := c.MustGet("userID").(string)
+	db := c.MustGet("db").(*mongo.Database)
+
+	id, err := primitive.ObjectIDFromHex(c.Param("import_id"))
 	if err != nil {
-		return nil, fmt.Errorf("copy file: %w", err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid import ID"})
+		return
 	}
 
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close writer: %w", err)
+	var req dto.ResumeImportApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "issue": "Some of the selected entries are missing required fields."})
+		return
 	}
 
-	// Prepare HTTP request
-	req, err := http.NewRequest("POST", config.Cfg.Cloud.DataExtractionAPI, &buf)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+	var skipped map[string]string
+	if req.All {
+		imp, err := repository.GetResumeImport(c, db, userID, id)
+		if errors.Is(err, repository.ErrResumeImportNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Resume import not found", "issue": "The draft may have expired. Upload your resume again."})
+			return
+		} else if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch resume import"})
+			return
+		}
+		req, skipped = repository.ResumeDraftSelection(imp.Draft)
+	}
+	for _, l := range req.Languages {
+		if !validProficiency(c, l.ProficiencyLevel) {
+			return
+		}
+	}
+
+	result, err := repository.ApplyResumeImport(c, db, userID, id, req)
+	switch {
+	case errors.Is(err, repository.ErrResumeImportNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resume import not found", "issue": "The draft may have expired. Upload your resume again."})
+		return
+	case errors.Is(err, repository.ErrResumeImportApplied):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "issue": "This resume was already imported."})
+		return
+	case errors.Is(err, repository.ErrNothingSelected):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "issue": "Select at least one entry to import.", "skipped": skipped})
+		return
+	case err == mongo.ErrNoDocuments:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Seeker not found", "issue": "We couldn't find your account. Please log in again."})
+		return
+	case err != nil:
+		log.Printf("❌ Resume import failed for user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "issue": "We couldn't save your resume data. Please try again."})
+		return
+	}
+	result.Skipped = skipped
+
+	// Trigger job matching once the profile is complete, as SetKeySkills does
+	if result.Completed {
+		if err := jobs.StartJobMatchScoreCalculation(c, db, userID); err != nil {
+			log.Printf("❌ Job match process error: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"issue": "Resume data saved to your profile", "result": result})
+}
+
+// resumeFormat detects PDF and DOCX files by their content. For DOCX the
+// document text is returned as well.
+func resumeFormat(data []byte) (string, string, error) {
+	if bytes.HasPrefix(data, []byte("%PDF-")) {
+		return resumeFormatPDF, "", nil
 	}
-	req.Header.Set("Authorization", "Bearer "+config.Cfg.Cloud.GEN_API_KEY)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	req.Header.Set("Accept", "application/json")
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		text, err := docxText(data)
+		if err != nil {
+			return "", "", fmt.Errorf("unreadable DOCX file: %w", err)
+		}
+		return resumeFormatDOCX, text, nil
+	}
+	return "", "", errors.New("only PDF and DOCX files are supported")
+}
 
-	// Execute request
-	resp, err := http.DefaultClient.Do(req)
+// docxText extracts the plain text of a DOCX document, one paragraph per line.
+func docxText(data []byte) (string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
+		return "", err
 	}
-	defer resp.Body.Close()
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", err
+		}
+		defer rc.Close()
+
+		var sb strings.Builder
+		dec := xml.NewDecoder(io.LimitReader(rc, 4*maxResumeFileBytes))
+		inText := false
+		for {
+			tok, err := dec.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return "", err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				switch t.Name.Local {
+				case "t":
+					inText = true
+				case "tab":
+					sb.WriteByte('\t')
+				case "br":
+					sb.WriteByte('\n')
+				}
+			case xml.EndElement:
+				switch t.Name.Local {
+				case "t":
+					inText = false
+				case "p":
+					sb.WriteByte('\n')
+				}
+			case xml.CharData:
+				if inText {
+					sb.Write(t)
+				}
+			}
+		}
+		return strings.TrimSpace(sb.String()), nil
+	}
+	return "", errors.New("word/document.xml not found")
+}
+
+// callResumeAPI uploads the resume to the resume extractor API. DOCX uploads
+// also carry the extracted document text in the "text" field.
+func callResumeAPI(ctx context.Context, data []byte, filename, format, text string) (map[string]interface{}, error) {
+	// Prepare multipart/form-data body
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %s", string(body))
+	partHeader := make(map[string][]string)
+	partHeader["Content-Disposition"] = []string{fmt.Sprintf(`form-data; name="file"; filename=%q`, filepath.Base(filename))}
+	partHeader["Content-Type"] = []string{resumeMimeTypes[format]}
+	part, err := writer.CreatePart(partHeader)
+	if err != nil {
+		return nil, fmt.Errorf("create form file: %w", err)
+	}
+	if _, err := part.Write(data); err != nil {
+		return nil, fmt.Errorf("copy file: %w", err)
+	}
+	if err := writer.WriteField("format", format); err != nil {
+		return nil, fmt.Errorf("write format: %w", err)
+	}
+	if text != "" {
+		if err := writer.WriteField("text", text); err != nil {
+			return nil, fmt.Errorf("write text: %w", err)
+		}
 	}
 
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("close writer: %w", err)
 	}
 
-	return result, nil
+	return mlclient.PostMultipart(ctx, mlclient.ResumeExtraction, buf.Bytes(), writer.FormDataContentType())
 }
 
 // CallResumeAPI handles external usage, wraps `callResumeAPI`
-func CallResumeAPI(file multipart.File, filename string) (map[string]interface{}, error) {
-	return callResumeAPI(file, filename)
+func CallResumeAPI(ctx context.Context, data []byte, filename, format, text string) (map[string]interface{}, error) {
+	return callResumeAPI(ctx, data, filename, format, text)
 }
-
@@ -7,6 +7,7 @@ import (
     "time"
 
     "RAAS/internal/dto"
+    "RAAS/internal/handlers/repository"
     "RAAS/internal/models"
 
     "github.com/gin-gonic/gin"
@@ -25,7 +26,6 @@ func (h *JobTitleHandler) CreateJobTitleOnce(c *gin.Context) {
     userID := c.MustGet("userID").(string)
     db := c.MustGet("db").(*mongo.Database)
     seekers := db.Collection("seekers")
-    timelines := db.Collection("user_entry_timelines")
 
     var input dto.JobTitleInput
     if err := c.ShouldBind(&input); err != nil {
@@ -90,7 +90,7 @@ func (h *JobTitleHandler) CreateJobTitleOnce(c *gin.Context) {
         return
     }
 
-    if _, err := timelines.UpdateOne(ctx, bson.M{"auth_user_id": userID}, bson.M{"$set": bson.M{"job_titles_completed": true}}); err != nil {
+    if _, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepJobTitles); err != nil {
         log.Printf("Timeline update error [CreateJobTitleOnce] user=%s: %v", userID, err)
     }
 
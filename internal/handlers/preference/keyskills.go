@@ -4,6 +4,7 @@ import (
     "context"
     "log"
     "net/http"
+    "strconv"
     "strings"
     "time"
 
@@ -11,6 +12,7 @@ import (
     "RAAS/internal/models"
     "RAAS/internal/handlers/repository"
     "RAAS/internal/handlers/features/jobs"
+    "RAAS/internal/skills"
 
     "github.com/gin-gonic/gin"
     "go.mongodb.org/mongo-driver/bson"
@@ -39,15 +41,9 @@ func (h *KeySkillsHandler) SetKeySkills(c *gin.Context) {
 		return
 	}
 
-	// Clean and deduplicate skills
-	skillSet := make(map[string]struct{})
-	for _, s := range input.Skills {
-		s = strings.TrimSpace(strings.ReplaceAll(s, "\n", ""))
-		if s != "" {
-			skillSet[s] = struct{}{}
-		}
-	}
-	if len(skillSet) == 0 {
+	// Clean, deduplicate and map known skills to their canonical names
+	cleaned := skills.Default().Canonicalize(input.Skills)
+	if len(cleaned) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "no valid skills provided",
 			"issue": "Please include at least one valid skill.",
@@ -55,11 +51,6 @@ func (h *KeySkillsHandler) SetKeySkills(c *gin.Context) {
 		return
 	}
 
-	cleaned := make([]string, 0, len(skillSet))
-	for s := range skillSet {
-		cleaned = append(cleaned, s)
-	}
-
 	seekers := db.Collection("seekers")
 	var existing struct {
 		KeySkills []string `bson:"key_skills"`
@@ -99,7 +90,7 @@ func (h *KeySkillsHandler) SetKeySkills(c *gin.Context) {
 	}
 
 	// Update timeline step
-	completed, _, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, userID, "key_skills_completed")
+	completed, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepKeySkills)
 	if err != nil {
 		log.Printf("❌ Timeline update error [SetKeySkills] user=%s: %v", userID, err)
 	}
@@ -158,3 +149,47 @@ func (h *KeySkillsHandler) GetKeySkills(c *gin.Context) {
         UpdatedAt:  seeker.UpdatedAt,
     })
 }
+
+// SuggestSkills autocompletes skill names from the skills taxonomy
+func (h *KeySkillsHandler) SuggestSkills(c *gin.Context) {
+    query := strings.TrimSpace(c.Query("q"))
+    if query == "" {
+        c.JSON(http.StatusBadRequest, gin.H{
+            "error": "missing q",
+            "issue": "Type a few letters of a skill to get suggestions.",
+        })
+        return
+    }
+
+    limit := 10
+    if v := c.Query("limit"); v != "" {
+        n, err := strconv.Atoi(v)
+        if err != nil || n < 1 || n > 50 {
+            c.JSON(http.StatusBadRequest, gin.H{
+                "error": "invalid limit",
+                "issue": "limit must be between 1 and 50.",
+            })
+            return
+        }
+        limit = n
+    }
+
+    taxonomy := skills.Default()
+    suggestions := make([]dto.SkillSuggestion, 0, limit)
+    for _, s := range taxonomy.Suggest(query, limit) {
+        related := make([]string, 0, len(s.Related))
+        for _, id := range s.Related {
+            if r, ok := taxonomy.Get(id); ok {
+                related = append(related, r.Name)
+            }
+        }
+        suggestions = append(suggestions, dto.SkillSuggestion{
+            ID:       s.ID,
+            Name:     s.Name,
+            Category: s.Category,
+            Related:  related,
+        })
+    }
+
+    c.JSON(http.StatusOK, gin.H{"suggestions": suggestions})
+}
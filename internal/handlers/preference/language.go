@@ -9,7 +9,7 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
 	"fmt"
 
@@ -41,68 +41,31 @@ func (h *LanguageHandler) CreateLanguage(c *gin.Context) {
 	}
 
 	// ✅ Validate proficiency level
-	validLevels := map[string]bool{
-		"beginner": true, "intermediate": true, "fluent": true, "native": true,
-	}
-	if !validLevels[input.ProficiencyLevel] {
-		msg := fmt.Sprintf("Invalid proficiency level: %s", input.ProficiencyLevel)
-		log.Printf("❌ Validation error [CreateLanguage] user=%s: %s", userID, msg)
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": msg,
-			"issue": "Proficiency level must be one of: beginner, intermediate, fluent, native.",
-		})
+	if !validProficiency(c, input.ProficiencyLevel) {
+		log.Printf("❌ Validation error [CreateLanguage] user=%s: invalid proficiency %q", userID, input.ProficiencyLevel)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// 🔍 Fetch seeker
-	var seeker models.Seeker
-	if err := seekers.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+	// ➕ Add entry
+	item := repository.NewLanguage(input)
+	if err := repository.PushSectionItem(ctx, seekers, userID, repository.SectionLanguages, item); err != nil {
 		status := http.StatusInternalServerError
-		issue := "Could not retrieve your profile. Please try again."
-
+		issue := "Failed to update your language records. Please retry."
 		if err == mongo.ErrNoDocuments {
 			status = http.StatusNotFound
 			issue = "No account found. It might have been removed or reset."
 		}
-
-		log.Printf("❌ DB fetch error [CreateLanguage] user=%s: %v", userID, err)
-		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
-		return
-	}
-
-	// ➕ Add language to seeker
-	if err := repository.AppendToLanguages(&seeker, input, ""); err != nil {
-		log.Printf("❌ Append error [CreateLanguage] user=%s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Could not save your language details. Try again shortly.",
-		})
-		return
-	}
-
-	// 📝 Update languages in DB
-	update := bson.M{"$set": bson.M{"languages": seeker.Languages}}
-	updateResult, err := seekers.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update)
-	if err != nil || updateResult.MatchedCount == 0 {
-		status := http.StatusInternalServerError
-		issue := "Failed to update your language records. Please retry."
-
-		if updateResult.MatchedCount == 0 {
-			status = http.StatusNotFound
-			issue = "Your account couldn't be updated. Please refresh and try again."
-		}
-
-		log.Printf("❌ Update error [CreateLanguage] user=%s: %v", userID, err)
+		log.Printf("❌ DB update error [CreateLanguage] user=%s: %v", userID, err)
 		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
 		return
 	}
 
 	// ✅ Update timeline progress
 	if err := func() error {
-		_, _, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, userID, "languages_completed")
+		_, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepLanguages)
 		return err
 	}(); err != nil {
 		log.Printf("⚠️ Timeline update error [CreateLanguage] user=%s: %v", userID, err)
@@ -114,7 +77,10 @@ func (h *LanguageHandler) CreateLanguage(c *gin.Context) {
 	}
 
 	// ✅ Success
-	c.JSON(http.StatusOK, gin.H{"issue": "Language added successfully"})
+	c.JSON(http.StatusOK, gin.H{
+		"issue": "Language added successfully",
+		"id":    item.ID,
+	})
 }
 
 // GetLanguages handles the retrieval of a user's languages
@@ -171,16 +137,6 @@ func (h *LanguageHandler) UpdateLanguage(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid language index",
-		})
-		return
-	}
-
 	var input dto.LanguageRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -189,53 +145,20 @@ func (h *LanguageHandler) UpdateLanguage(c *gin.Context) {
 		})
 		return
 	}
+	if !validProficiency(c, input.ProficiencyLevel) {
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Languages []bson.M `bson:"languages"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionLanguages, "Language")
+	if !ok {
 		return
 	}
 
-	if index > len(seeker.Languages) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Language index is out of range",
-		})
-		return
-	}
-
-	seeker.Languages[index-1] = bson.M{
-		"language":    input.LanguageName,
-		"proficiency": input.ProficiencyLevel,
-		"updated_at":  time.Now(),
-	}
-
-	update := bson.M{
-		"$set": bson.M{
-			"languages": seeker.Languages,
-		},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to update language",
-		})
+	if err := repository.ReplaceSectionItem(ctx, seekersCollection, userID, repository.SectionLanguages, id, repository.NewLanguage(input)); err != nil {
+		sectionItemError(c, err, "Language", "Failed to update language")
 		return
 	}
 
@@ -250,58 +173,16 @@ func (h *LanguageHandler) DeleteLanguage(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid language index",
-		})
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		Languages []bson.M `bson:"languages"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		issue := "Failed to retrieve seeker"
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			issue = "Seeker not found"
-		}
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
-		return
-	}
-
-	if index > len(seeker.Languages) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Language index is out of range",
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionLanguages, "Language")
+	if !ok {
 		return
 	}
 
-	seeker.Languages = append(seeker.Languages[:index-1], seeker.Languages[index:]...)
-
-	update := bson.M{
-		"$set": bson.M{
-			"languages": seeker.Languages,
-		},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to delete language",
-		})
+	if err := repository.DeleteSectionItem(ctx, seekersCollection, userID, repository.SectionLanguages, id); err != nil {
+		sectionItemError(c, err, "Language", "Failed to delete language")
 		return
 	}
 
@@ -309,3 +190,18 @@ func (h *LanguageHandler) DeleteLanguage(c *gin.Context) {
 		"issue": "Language deleted successfully",
 	})
 }
+
+// validProficiency checks a proficiency level, writing the error response
+// when it is not one of models.LanguageProficiencyLevels.
+func validProficiency(c *gin.Context, level string) bool {
+	for _, l := range models.LanguageProficiencyLevels {
+		if l == level {
+			return true
+		}
+	}
+	c.JSON(http.StatusBadRequest, gin.H{
+		"error": fmt.Sprintf("Invalid proficiency level: %s", level),
+		"issue": "Proficiency level must be one of: " + strings.Join(models.LanguageProficiencyLevels, ", ") + ".",
+	})
+	return false
+}
@@ -3,6 +3,8 @@ package preference
 import (
 	"RAAS/internal/dto"
 	"RAAS/internal/models"
+	"RAAS/internal/geo"
+	"RAAS/internal/handlers/features/jobs"
 	"RAAS/internal/handlers/repository"
 
 	"context"
@@ -56,6 +58,7 @@ func (h *PersonalInfoHandler) CreatePersonalInfo(c *gin.Context) {
 		return
 	}
 
+	previousHome := repository.SeekerHomePoint(seeker)
 	if err := repository.SetPersonalInfo(&seeker, &input); err != nil {
 		log.Printf("❌ Failed to process personal info for user %s: %v", userID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
@@ -77,8 +80,8 @@ func (h *PersonalInfoHandler) CreatePersonalInfo(c *gin.Context) {
 	}
 
 	// ✅ Update timeline and get next step
-	_ , _, err = repository.UpdateTimelineStepAndCheckCompletion(
-		ctx, db, userID, "personal_info_completed",
+	_ , _, err = repository.CompleteOnboardingStep(
+		ctx, db, userID, models.OnboardingStepPersonalInfo,
 	)
 	if err != nil {
 		log.Printf("⚠️ Timeline update failed for user %s: %v", userID, err)
@@ -89,6 +92,13 @@ func (h *PersonalInfoHandler) CreatePersonalInfo(c *gin.Context) {
 		return
 	}
 
+	// The home city feeds the distance part of the match score
+	if !geo.SamePlace(previousHome, repository.SeekerHomePoint(seeker)) {
+		if err := jobs.RescoreMatchScores(c, db, userID); err != nil {
+			log.Printf("⚠️ Failed to rescore match scores for %s: %v", userID, err)
+		}
+	}
+
 	response := gin.H{"issue": "Personal info saved successfully"}
 	if repository.IsFieldFilled(seeker.PersonalInfo) {
 		response["issue"] = "Personal info updated successfully"
@@ -0,0 +1,39 @@
+package preference
+
+import (
+	"RAAS/internal/handlers/repository"
+
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// sectionItemID resolves the :id route parameter of a profile section entry,
+// writing the error response when there is no such entry.
+func sectionItemID(c *gin.Context, ctx context.Context, seekers *mongo.Collection, userID, section, label string) (primitive.ObjectID, bool) {
+	id, err := repository.ResolveSectionItemID(ctx, seekers, userID, section, c.Param("id"))
+	if err != nil {
+		sectionItemError(c, err, label, "Failed to retrieve seeker")
+		return id, false
+	}
+	return id, true
+}
+
+// sectionItemError writes the response for a failed profile section change.
+func sectionItemError(c *gin.Context, err error, label, issue string) {
+	if errors.Is(err, repository.ErrSectionItemNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "not_found",
+			"issue": label + " not found",
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"error": err.Error(),
+		"issue": issue,
+	})
+}
@@ -10,12 +10,10 @@ import (
 	"log"
 	"net/http"
 	"time"
-	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/mongo/options"
 )
 type WorkExperienceHandler struct{}
 
@@ -42,58 +40,23 @@ func (h *WorkExperienceHandler) CreateWorkExperience(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	// 1️⃣ Fetch Seeker
-	var seeker models.Seeker
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+	// 1️⃣ Add entry
+	item := repository.NewWorkExperience(input)
+	if err := repository.PushSectionItem(ctx, seekersCollection, userID, repository.SectionWorkExperiences, item); err != nil {
 		status := http.StatusInternalServerError
-		issue := "Could not retrieve your profile. Please try again."
-
+		issue := "Unable to save your work experience."
 		if err == mongo.ErrNoDocuments {
 			status = http.StatusNotFound
 			issue = "No account found. It might have been removed or reset."
 		}
-
-		log.Printf("❌ Seeker fetch failed for user %s: %v", userID, err)
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
-		return
-	}
-
-	// 2️⃣ Append Experience
-	if err := repository.AppendToWorkExperience(&seeker, input); err != nil {
-		log.Printf("❌ Processing error for user %s: %v", userID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to process your work experience. Try again.",
-		})
-		return
-	}
-
-	// 3️⃣ Update Seeker Record
-	update := bson.M{"$set": bson.M{"work_experiences": seeker.WorkExperiences}}
-	updateResult, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update)
-	if err != nil || updateResult.MatchedCount == 0 {
-		log.Printf("❌ Seeker update failed for user %s: %v", userID, err)
-		status := http.StatusInternalServerError
-		issue := "Unable to save your work experience."
-
-		if updateResult.MatchedCount == 0 {
-			status = http.StatusNotFound
-			issue = "Your account wasn't found to update. Please refresh and try again."
-		}
-
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": issue,
-		})
+		log.Printf("❌ DB update error [CreateWorkExperience] user=%s: %v", userID, err)
+		c.JSON(status, gin.H{"error": err.Error(), "issue": issue})
 		return
 	}
 
-	// 4️⃣ Mark Step as Completed
+	// 2️⃣ Mark Step as Completed
 	if err := func() error {
-		_, _, err := repository.UpdateTimelineStepAndCheckCompletion(ctx, db, userID, "work_experiences_completed")
+		_, _, err := repository.CompleteOnboardingStep(ctx, db, userID, models.OnboardingStepWorkExperiences)
 		return err
 	}(); err != nil {
 		log.Printf("⚠️ Timeline update failed for user %s: %v", userID, err)
@@ -107,6 +70,7 @@ func (h *WorkExperienceHandler) CreateWorkExperience(c *gin.Context) {
 	// ✅ Success
 	c.JSON(http.StatusOK, gin.H{
 		"issue": "Work experience added successfully.",
+		"id":    item.ID,
 	})
 }
 
@@ -161,16 +125,6 @@ func (h *WorkExperienceHandler) UpdateWorkExperience(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid work experience index",
-		})
-		return
-	}
-
 	var input dto.WorkExperienceRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
@@ -183,56 +137,13 @@ func (h *WorkExperienceHandler) UpdateWorkExperience(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		WorkExperiences []bson.M `bson:"work_experiences"`
-	}
-	projection := bson.M{"work_experiences": 1}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}, options.FindOne().SetProjection(projection)).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		msg := "Failed to retrieve seeker"
-
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			msg = "Seeker not found"
-		}
-
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": msg,
-		})
-		return
-	}
-
-	if index > len(seeker.WorkExperiences) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Work experience index is out of range",
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionWorkExperiences, "Work experience")
+	if !ok {
 		return
 	}
 
-	seeker.WorkExperiences[index-1] = bson.M{
-		"auth_user_id":         userID,
-		"job_title":            input.JobTitle,
-		"company_name":         input.CompanyName,
-		"start_date":           input.StartDate,
-		"end_date":             input.EndDate,
-		"key_responsibilities": input.KeyResponsibilities,
-		"location":             input.Location,
-		"created_at":           input.StartDate,
-		"updated_at":           time.Now(),
-	}
-
-	update := bson.M{
-		"$set": bson.M{"work_experiences": seeker.WorkExperiences},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to update work experience",
-		})
+	if err := repository.ReplaceSectionItem(ctx, seekersCollection, userID, repository.SectionWorkExperiences, id, repository.NewWorkExperience(input)); err != nil {
+		sectionItemError(c, err, "Work experience", "Failed to update work experience")
 		return
 	}
 
@@ -247,58 +158,16 @@ func (h *WorkExperienceHandler) DeleteWorkExperience(c *gin.Context) {
 	db := c.MustGet("db").(*mongo.Database)
 	seekersCollection := db.Collection("seekers")
 
-	indexStr := c.Param("id")
-	index, err := strconv.Atoi(indexStr)
-	if err != nil || index <= 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid_index",
-			"issue": "Invalid work experience index",
-		})
-		return
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var seeker struct {
-		WorkExperiences []bson.M `bson:"work_experiences"`
-	}
-	if err := seekersCollection.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
-		status := http.StatusInternalServerError
-		msg := "Failed to retrieve seeker"
-
-		if err == mongo.ErrNoDocuments {
-			status = http.StatusNotFound
-			msg = "Seeker not found"
-		}
-
-		c.JSON(status, gin.H{
-			"error": err.Error(),
-			"issue": msg,
-		})
+	id, ok := sectionItemID(c, ctx, seekersCollection, userID, repository.SectionWorkExperiences, "Work experience")
+	if !ok {
 		return
 	}
 
-	if index > len(seeker.WorkExperiences) {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "index_out_of_range",
-			"issue": "Work experience index is out of range",
-		})
-		return
-	}
-
-	seeker.WorkExperiences = append(seeker.WorkExperiences[:index-1], seeker.WorkExperiences[index:]...)
-
-	update := bson.M{
-		"$set": bson.M{"work_experiences": seeker.WorkExperiences},
-		"$currentDate": bson.M{"updated_at": true},
-	}
-
-	if _, err := seekersCollection.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-			"issue": "Failed to delete work experience",
-		})
+	if err := repository.DeleteSectionItem(ctx, seekersCollection, userID, repository.SectionWorkExperiences, id); err != nil {
+		sectionItemError(c, err, "Work experience", "Failed to delete work experience")
 		return
 	}
 
@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrApplicationNotFound is returned when the user has no application for the job.
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrApplicationConflict is returned when the application changed while being updated.
+	ErrApplicationConflict = errors.New("application was modified concurrently")
+)
+
+// TransitionError reports a status change the pipeline does not allow.
+type TransitionError struct {
+	From    string
+	To      string
+	Allowed []string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot move application from %q to %q", e.From, e.To)
+}
+
+// GetApplication loads the user's application for a job.
+func GetApplication(ctx context.Context, db *mongo.Database, userID, jobID string) (models.SelectedJobApplication, error) {
+	var app models.SelectedJobApplication
+	err := db.Collection(models.CollectionSelectedJobApps).
+		FindOne(ctx, bson.M{"auth_user_id": userID, "job_id": jobID}).
+		Decode(&app)
+	if err == mongo.ErrNoDocuments {
+		return app, ErrApplicationNotFound
+	}
+	return app, err
+}
+
+// TransitionApplication moves an application to a new status if the pipeline
+// allows it, recording the change in its status history. The update is
+// conditional on the status it was read with, so concurrent changes cannot
+// skip a validation.
+func TransitionApplication(ctx context.Context, db *mongo.Database, userID, jobID, to, note string) (models.SelectedJobApplication, error) {
+	app, err := GetApplication(ctx, db, userID, jobID)
+	if err != nil {
+		return app, err
+	}
+
+	from := models.NormalizeApplicationStatus(app.Status)
+	if !models.CanTransitionApplication(from, to) {
+		return app, &TransitionError{From: from, To: to, Allowed: models.AllowedApplicationTransitions(from)}
+	}
+
+	now := time.Now()
+	change := models.StatusChange{From: from, To: to, Note: note, ChangedAt: now}
+	set := bson.M{"status": to, "updated_at": now}
+	if to == models.ApplicationStatusInterview {
+		change.InterviewRound = app.InterviewRound + 1
+		set["interview_round"] = change.InterviewRound
+	}
+	if to == models.ApplicationStatusApplied {
+		set["applied_at"] = now
+	}
+
+	var updated models.SelectedJobApplication
+	err = db.Collection(models.CollectionSelectedJobApps).FindOneAndUpdate(ctx,
+		bson.M{"_id": app.ID, "status": app.Status},
+		bson.M{"$set": set, "$push": bson.M{"status_history": change}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if err == mongo.ErrNoDocuments {
+		return app, ErrApplicationConflict
+	}
+	if err != nil {
+		return app, err
+	}
+
+	if to == models.ApplicationStatusApplied {
+		_, err = db.Collection(models.CollectionSeekers).UpdateOne(ctx,
+			bson.M{"auth_user_id": userID},
+			bson.M{"$inc": bson.M{
+				"total_applications":        1,
+				"weekly_applications_count": 1,
+			}},
+		)
+		if err != nil {
+			log.Printf("❌ Failed to increment seeker counters for user %s: %v", userID, err)
+		}
+	}
+	return updated, nil
+}
+
+// ManualApplication is an application the user tracks by hand, e.g. one sent
+// through a company website. It never consumes generation quota.
+type ManualApplication struct {
+	Company   string
+	Title     string
+	JobLink   string
+	Channel   string
+	Status    string
+	AppliedAt *time.Time
+	Note      string
+}
+
+// ManualApplicationStatuses are the statuses a manual entry may start in.
+var ManualApplicationStatuses = []string{
+	models.ApplicationStatusSaved,
+	models.ApplicationStatusApplied,
+	models.ApplicationStatusScreening,
+	models.ApplicationStatusInterview,
+	models.ApplicationStatusOffer,
+	models.ApplicationStatusRejected,
+}
+
+// CreateManualApplication inserts a manually tracked application. Its history
+// starts with "applied" at the given date, followed by the current status.
+func CreateManualApplication(ctx context.Context, db *mongo.Database, userID string, m ManualApplication) (models.SelectedJobApplication, error) {
+	now := time.Now()
+	status := m.Status
+	if status == "" {
+		status = models.ApplicationStatusApplied
+	}
+
+	app := models.SelectedJobApplication{
+		ID:           primitive.NewObjectID(),
+		AuthUserID:   userID,
+		JobID:        "manual-" + uuid.New().String(),
+		Status:       status,
+		Source:       models.ApplicationSourceManual,
+		Company:      m.Company,
+		Title:        m.Title,
+		JobLink:      m.JobLink,
+		Channel:      m.Channel,
+		SelectedDate: now,
+		UpdatedAt:    &now,
+	}
+
+	if status != models.ApplicationStatusSaved {
+		appliedAt := now
+		if m.AppliedAt != nil {
+			appliedAt = *m.AppliedAt
+		}
+		app.AppliedAt = &appliedAt
+		app.SelectedDate = appliedAt
+		app.StatusHistory = append(app.StatusHistory, models.StatusChange{To: models.ApplicationStatusApplied, ChangedAt: appliedAt})
+	}
+	if status != models.ApplicationStatusApplied {
+		change := models.StatusChange{To: status, ChangedAt: now}
+		if len(app.StatusHistory) > 0 {
+			change.From = models.ApplicationStatusApplied
+		}
+		if status == models.ApplicationStatusInterview {
+			app.InterviewRound = 1
+			change.InterviewRound = 1
+		}
+		app.StatusHistory = append(app.StatusHistory, change)
+	}
+	if note := strings.TrimSpace(m.Note); note != "" {
+		app.Notes = []models.ApplicationNote{{ID: primitive.NewObjectID(), Text: note, CreatedAt: now}}
+	}
+
+	if _, err := db.Collection(models.CollectionSelectedJobApps).InsertOne(ctx, app); err != nil {
+		return app, err
+	}
+
+	if app.AppliedAt != nil {
+		// Counts towards application stats, but not towards generation quota
+		if _, err := db.Collection(models.CollectionSeekers).UpdateOne(ctx,
+			bson.M{"auth_user_id": userID},
+			bson.M{"$inc": bson.M{"total_applications": 1}},
+		); err != nil {
+			log.Printf("❌ Failed to increment seeker counters for user %s: %v", userID, err)
+		}
+	}
+	return app, nil
+}
+
+// ManualApplicationExists reports whether the user already tracks an application with this link.
+func ManualApplicationExists(ctx context.Context, db *mongo.Database, userID, jobLink string) (bool, error) {
+	if jobLink == "" {
+		return false, nil
+	}
+	n, err := db.Collection(models.CollectionSelectedJobApps).CountDocuments(ctx, bson.M{
+		"auth_user_id": userID,
+		"job_link":     jobLink,
+		"status":       bson.M{"$ne": models.ApplicationStatusDeleted},
+	})
+	return n > 0, err
+}
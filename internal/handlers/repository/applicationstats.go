@@ -0,0 +1,327 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BoardCard is the compact application shown in a tracker board column.
+type BoardCard struct {
+	JobID          string     `bson:"job_id" json:"job_id"`
+	Title          string     `bson:"title" json:"title"`
+	Company        string     `bson:"company" json:"company"`
+	Source         string     `bson:"source" json:"source"`
+	SelectedDate   time.Time  `bson:"selected_date" json:"selected_date"`
+	InterviewRound int        `bson:"interview_round,omitempty" json:"interview_round,omitempty"`
+	NextAction     string     `bson:"next_action,omitempty" json:"next_action,omitempty"`
+	NextActionAt   *time.Time `bson:"next_action_at,omitempty" json:"next_action_at,omitempty"`
+	UpdatedAt      *time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// BoardColumn is one pipeline status with its total count and newest cards.
+type BoardColumn struct {
+	Status string      `json:"status"`
+	Count  int         `json:"count"`
+	Cards  []BoardCard `json:"cards"`
+}
+
+// applicationJobTitleStages looks up the job title from jobs or external_jobs
+// depending on source. Manual applications already carry their own title.
+func applicationJobTitleStages() []bson.D {
+	lookup := func(from, as string) bson.D {
+		return bson.D{{Key: "$lookup", Value: bson.M{
+			"from":         from,
+			"localField":   "job_id",
+			"foreignField": "job_id",
+			"as":           as,
+		}}}
+	}
+	return []bson.D{
+		lookup(models.CollectionJobs, "_internal_job"),
+		lookup(models.CollectionExtJobs, "_external_job"),
+		{{Key: "$addFields", Value: bson.M{
+			"title": bson.M{"$ifNull": bson.A{
+				"$title",
+				bson.M{"$cond": bson.A{
+					bson.M{"$eq": bson.A{"$source", "external"}},
+					bson.M{"$arrayElemAt": bson.A{"$_external_job.title", 0}},
+					bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$_internal_job.title", 0}}, ""}},
+				}},
+			}},
+		}}},
+		{{Key: "$project", Value: bson.M{"_internal_job": 0, "_external_job": 0}}},
+	}
+}
+
+// ApplicationBoard groups all of the user's applications into pipeline
+// columns. Each column holds its total count and at most perColumn cards.
+func ApplicationBoard(ctx context.Context, db *mongo.Database, userID string, perColumn int) ([]BoardColumn, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"auth_user_id": userID,
+			"status":       bson.M{"$ne": models.ApplicationStatusDeleted},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "updated_at", Value: -1}, {Key: "selected_date", Value: -1}}}},
+	}
+	pipeline = append(pipeline, applicationJobTitleStages()...)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$group", Value: bson.M{
+			"_id":   "$status",
+			"count": bson.M{"$sum": 1},
+			"cards": bson.M{"$push": bson.M{
+				"job_id":          "$job_id",
+				"title":           "$title",
+				"company":         "$company",
+				"source":          "$source",
+				"selected_date":   "$selected_date",
+				"interview_round": "$interview_round",
+				"next_action":     "$next_action",
+				"next_action_at":  "$next_action_at",
+				"updated_at":      "$updated_at",
+			}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{
+			"count": 1,
+			"cards": bson.M{"$slice": bson.A{"$cards", perColumn}},
+		}}},
+	)
+
+	cursor, err := db.Collection(models.CollectionSelectedJobApps).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		Status string      `bson:"_id"`
+		Count  int         `bson:"count"`
+		Cards  []BoardCard `bson:"cards"`
+	}
+	if err := cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	columns := make([]BoardColumn, len(models.ApplicationPipelineOrder))
+	index := make(map[string]int, len(columns))
+	for i, status := range models.ApplicationPipelineOrder {
+		columns[i] = BoardColumn{Status: status, Cards: []BoardCard{}}
+		index[status] = i
+	}
+	for _, g := range groups {
+		i, ok := index[models.NormalizeApplicationStatus(g.Status)]
+		if !ok {
+			continue
+		}
+		columns[i].Count += g.Count
+		if room := perColumn - len(columns[i].Cards); room > 0 {
+			if len(g.Cards) > room {
+				g.Cards = g.Cards[:room]
+			}
+			columns[i].Cards = append(columns[i].Cards, g.Cards...)
+		}
+	}
+	return columns, nil
+}
+
+// FunnelStats counts applications at each funnel stage.
+type FunnelStats struct {
+	Key           string  `bson:"_id" json:"key,omitempty"`
+	Total         int     `bson:"total" json:"total"`
+	Submitted     int     `bson:"submitted" json:"submitted"`
+	Responded     int     `bson:"responded" json:"responded"`
+	Interviewed   int     `bson:"interviewed" json:"interviewed"`
+	Offered       int     `bson:"offered" json:"offered"`
+	ResponseRate  float64 `bson:"-" json:"response_rate"`
+	InterviewRate float64 `bson:"-" json:"interview_conversion"`
+	OfferRate     float64 `bson:"-" json:"offer_rate"`
+}
+
+func (f *FunnelStats) computeRates() {
+	f.ResponseRate = ratio(f.Responded, f.Submitted)
+	f.InterviewRate = ratio(f.Interviewed, f.Submitted)
+	f.OfferRate = ratio(f.Offered, f.Submitted)
+}
+
+func ratio(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*1000) / 1000
+}
+
+// ApplicationAnalytics summarizes how the user's applications convert.
+type ApplicationAnalytics struct {
+	Overall              FunnelStats    `json:"overall"`
+	MedianDaysToResponse *float64       `json:"median_days_to_first_response"`
+	ByStatus             map[string]int `json:"by_status"`
+	BySource             []FunnelStats  `json:"by_source"`
+	ByCompany            []FunnelStats  `json:"by_company"`
+}
+
+// funnelGroup sums the per-application funnel flags added by ApplicationAnalyticsFor.
+func funnelGroup(key interface{}) bson.D {
+	flag := func(field string) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{"$" + field, 1, 0}}}
+	}
+	return bson.D{{Key: "$group", Value: bson.M{
+		"_id":         key,
+		"total":       bson.M{"$sum": 1},
+		"submitted":   flag("_submitted"),
+		"responded":   flag("_responded"),
+		"interviewed": flag("_interviewed"),
+		"offered":     flag("_offered"),
+	}}}
+}
+
+// ApplicationAnalyticsFor computes response rate, interview conversion, time
+// to first response and breakdowns by source and company from status history.
+func ApplicationAnalyticsFor(ctx context.Context, db *mongo.Database, userID string, topCompanies int) (*ApplicationAnalytics, error) {
+	historyTo := bson.M{"$ifNull": bson.A{"$status_history.to", bson.A{}}}
+	firstChangeTo := func(statuses []string) bson.M {
+		return bson.M{"$min": bson.M{"$map": bson.M{
+			"input": bson.M{"$filter": bson.M{
+				"input": bson.M{"$ifNull": bson.A{"$status_history", bson.A{}}},
+				"as":    "h",
+				"cond":  bson.M{"$in": bson.A{"$$h.to", statuses}},
+			}},
+			"as": "h",
+			"in": "$$h.changed_at",
+		}}}
+	}
+	reached := func(statuses ...string) bson.M {
+		or := bson.A{bson.M{"$in": bson.A{"$status", statuses}}}
+		for _, s := range statuses {
+			or = append(or, bson.M{"$in": bson.A{s, historyTo}})
+		}
+		return bson.M{"$or": or}
+	}
+	// A withdrawn application was only sent when its history reached applied
+	sentStatuses := []string{"selected"}
+	for _, s := range models.SubmittedApplicationStatuses {
+		if s != models.ApplicationStatusWithdrawn {
+			sentStatuses = append(sentStatuses, s)
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"auth_user_id": userID,
+			"status":       bson.M{"$ne": models.ApplicationStatusDeleted},
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"_first_response_at": firstChangeTo(models.EmployerResponseStatuses),
+			"_applied_time": bson.M{"$ifNull": bson.A{
+				"$applied_at",
+				bson.M{"$ifNull": bson.A{firstChangeTo([]string{models.ApplicationStatusApplied}), "$selected_date"}},
+			}},
+			"_submitted":   reached(sentStatuses...),
+			"_interviewed": reached(models.ApplicationStatusInterview),
+			"_offered":     reached(models.ApplicationStatusOffer, models.ApplicationStatusAccepted, "selected"),
+		}}},
+		{{Key: "$addFields", Value: bson.M{
+			"_responded": bson.M{"$and": bson.A{
+				"$_submitted",
+				bson.M{"$or": bson.A{
+					bson.M{"$ne": bson.A{"$_first_response_at", nil}},
+					bson.M{"$in": bson.A{"$status", models.EmployerResponseStatuses}},
+				}},
+			}},
+			"_days_to_response": bson.M{"$cond": bson.A{
+				bson.M{"$and": bson.A{
+					bson.M{"$ne": bson.A{"$_first_response_at", nil}},
+					bson.M{"$ne": bson.A{"$_applied_time", nil}},
+				}},
+				bson.M{"$divide": bson.A{
+					bson.M{"$subtract": bson.A{"$_first_response_at", "$_applied_time"}},
+					86400000,
+				}},
+				nil,
+			}},
+		}}},
+		{{Key: "$facet", Value: bson.M{
+			"overall":    bson.A{funnelGroup(nil)},
+			"by_source":  bson.A{funnelGroup("$source"), bson.D{{Key: "$sort", Value: bson.M{"total": -1}}}},
+			"by_company": bson.A{funnelGroup("$company"), bson.D{{Key: "$sort", Value: bson.M{"total": -1}}}, bson.D{{Key: "$limit", Value: topCompanies}}},
+			"by_status": bson.A{
+				bson.D{{Key: "$group", Value: bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}}},
+			},
+			"response_days": bson.A{
+				bson.D{{Key: "$match", Value: bson.M{"_submitted": true, "_days_to_response": bson.M{"$gte": 0}}}},
+				bson.D{{Key: "$project", Value: bson.M{"_id": 0, "days": "$_days_to_response"}}},
+			},
+		}}},
+	}
+
+	cursor, err := db.Collection(models.CollectionSelectedJobApps).Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var facets []struct {
+		Overall   []FunnelStats `bson:"overall"`
+		BySource  []FunnelStats `bson:"by_source"`
+		ByCompany []FunnelStats `bson:"by_company"`
+		ByStatus  []struct {
+			Status string `bson:"_id"`
+			Count  int    `bson:"count"`
+		} `bson:"by_status"`
+		ResponseDays []struct {
+			Days float64 `bson:"days"`
+		} `bson:"response_days"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return nil, err
+	}
+
+	out := &ApplicationAnalytics{ByStatus: map[string]int{}, BySource: []FunnelStats{}, ByCompany: []FunnelStats{}}
+	if len(facets) == 0 {
+		return out, nil
+	}
+	f := facets[0]
+
+	if len(f.Overall) > 0 {
+		out.Overall = f.Overall[0]
+		out.Overall.Key = ""
+	}
+	out.Overall.computeRates()
+	for _, s := range f.BySource {
+		s.computeRates()
+		out.BySource = append(out.BySource, s)
+	}
+	for _, s := range f.ByCompany {
+		s.computeRates()
+		out.ByCompany = append(out.ByCompany, s)
+	}
+	for _, s := range f.ByStatus {
+		out.ByStatus[models.NormalizeApplicationStatus(s.Status)] += s.Count
+	}
+
+	days := make([]float64, 0, len(f.ResponseDays))
+	for _, d := range f.ResponseDays {
+		days = append(days, d.Days)
+	}
+	out.MedianDaysToResponse = median(days)
+	return out, nil
+}
+
+func median(values []float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sort.Float64s(values)
+	mid := len(values) / 2
+	m := values[mid]
+	if len(values)%2 == 0 {
+		m = (values[mid-1] + values[mid]) / 2
+	}
+	m = math.Round(m*10) / 10
+	return &m
+}
@@ -0,0 +1,342 @@
+package repository
+
+import (
+	"RAAS/internal/mlclient"
+	"RAAS/internal/models"
+	"RAAS/internal/skills"
+
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCVNotFound is returned when no CV was generated for the job.
+var ErrCVNotFound = errors.New("CV not found")
+
+const (
+	// A CV of this many words reads as complete without overflowing two pages
+	atsMinWords = 350
+	atsMaxWords = 900
+	// Sentences longer than these word counts lower the readability score
+	atsIdealSentenceWords = 20
+	atsLongSentenceWords  = 30
+	// Text fields with fewer words are labels, dates or list items, not prose
+	atsMinProseWords = 4
+
+	// Weights of the partial scores in the overall ATS score
+	atsKeywordWeight     = 0.5
+	atsSectionWeight     = 0.2
+	atsLengthWeight      = 0.15
+	atsReadabilityWeight = 0.15
+)
+
+// atsSections are the CV keys that hold each standard section. Keys are
+// compared in the normalized form of mlclient.NormalizeKey.
+var atsSections = map[string][]string{
+	"contact":        {"email", "contact", "phone"},
+	"summary":        {"summary", "profile", "profilesummary", "about", "objective", "professionalsummary"},
+	"experience":     {"experience", "experiences", "workexperience", "workexperiences", "experiencesummary", "employment", "professionalexperience"},
+	"education":      {"education", "academics", "qualifications"},
+	"skills":         {"skills", "keyskills", "technicalskills"},
+	"projects":       {"projects", "pastprojects"},
+	"certifications": {"certifications", "certificates"},
+	"languages":      {"languages"},
+}
+
+var (
+	atsRequiredSections = []string{"contact", "summary", "experience", "education", "skills"}
+	atsOptionalSections = []string{"projects", "certifications", "languages"}
+)
+
+// atsContactKeys hold personal details that are not prose.
+var atsContactKeys = map[string]bool{
+	"name": true, "email": true, "contact": true, "phone": true, "address": true,
+	"linkedin": true, "portfolio": true, "startdate": true, "enddate": true,
+}
+
+// AnalyzeATS compares a CV with the job it was generated for.
+func AnalyzeATS(data map[string]interface{}, job models.Job) models.ATSAnalysis {
+	cv, _ := plainJSON(data).(map[string]interface{})
+	cv = mlclient.Unwrap(cv)
+	var texts []string
+	var prose []string
+	collectCVText(cv, "", &texts, &prose)
+	doc := skills.Default().NewDocument(strings.Join(texts, "\n"))
+
+	words := 0
+	for _, t := range texts {
+		words += len(strings.Fields(t))
+	}
+
+	a := models.ATSAnalysis{
+		Keywords:    atsKeywords(doc, job),
+		Sections:    atsSectionCoverage(cv),
+		Length:      models.ATSLength{Words: words, Score: atsLengthScore(words)},
+		Readability: atsReadability(prose),
+	}
+	a.Score = int(math.Round(atsKeywordWeight*float64(a.Keywords.Score) +
+		atsSectionWeight*float64(a.Sections.Score) +
+		atsLengthWeight*float64(a.Length.Score) +
+		atsReadabilityWeight*float64(a.Readability.Score)))
+	return a
+}
+
+func collectCVText(v interface{}, key string, texts, prose *[]string) {
+	switch val := v.(type) {
+	case string:
+		if val = strings.TrimSpace(val); val == "" {
+			return
+		}
+		*texts = append(*texts, val)
+		if !atsContactKeys[key] && len(strings.Fields(val)) >= atsMinProseWords {
+			*prose = append(*prose, val)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			collectCVText(val[k], mlclient.NormalizeKey(k), texts, prose)
+		}
+	case []interface{}:
+		for _, item := range val {
+			collectCVText(item, key, texts, prose)
+		}
+	}
+}
+
+func atsKeywords(doc *skills.Document, job models.Job) models.ATSKeywords {
+	taxonomy := skills.Default()
+	title := job.JobTitle
+	if title == "" {
+		title = job.Title
+	}
+	terms := strings.FieldsFunc(job.Skills, func(r rune) bool { return r == ',' || r == ';' || r == '|' || r == '\n' })
+	ids := append(append([]string{}, job.SkillIDs...), taxonomy.Extract(title+"\n"+job.JobDescription)...)
+	for _, id := range ids {
+		if s, ok := taxonomy.Get(id); ok {
+			terms = append(terms, s.Name)
+		}
+	}
+
+	kw := models.ATSKeywords{Matched: []string{}, Missing: []string{}}
+	for _, term := range taxonomy.Canonicalize(terms) {
+		if doc.Has(term) {
+			kw.Matched = append(kw.Matched, term)
+		} else {
+			kw.Missing = append(kw.Missing, term)
+		}
+	}
+	kw.TitleMatched = title != "" && doc.Has(title)
+
+	// The job title is worth a tenth of the keyword score
+	coverage := 1.0
+	if total := len(kw.Matched) + len(kw.Missing); total > 0 {
+		coverage = float64(len(kw.Matched)) / float64(total)
+	}
+	score := coverage * 90
+	if kw.TitleMatched || title == "" {
+		score += 10
+	}
+	kw.Score = int(math.Round(score))
+	return kw
+}
+
+func hasCVContent(v interface{}) bool {
+	switch val := v.(type) {
+	case string:
+		return strings.TrimSpace(val) != ""
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return v != nil
+}
+
+func atsSectionCoverage(cv map[string]interface{}) models.ATSSections {
+	present := map[string]bool{}
+	for key, v := range cv {
+		key = mlclient.NormalizeKey(key)
+		for section, keys := range atsSections {
+			for _, k := range keys {
+				if key == k && hasCVContent(v) {
+					present[section] = true
+				}
+			}
+		}
+	}
+
+	sec := models.ATSSections{Present: []string{}, Missing: []string{}, Optional: []string{}}
+	for _, s := range atsRequiredSections {
+		if present[s] {
+			sec.Present = append(sec.Present, s)
+		} else {
+			sec.Missing = append(sec.Missing, s)
+		}
+	}
+	for _, s := range atsOptionalSections {
+		if present[s] {
+			sec.Optional = append(sec.Optional, s)
+		}
+	}
+	sec.Score = int(math.Round(100 * float64(len(sec.Present)) / float64(len(atsRequiredSections))))
+	return sec
+}
+
+func atsLengthScore(words int) int {
+	switch {
+	case words < atsMinWords:
+		return int(math.Round(100 * float64(words) / atsMinWords))
+	case words > atsMaxWords:
+		// Lose a point for every ten words too many
+		return int(math.Max(0, math.Round(100-float64(words-atsMaxWords)/10)))
+	}
+	return 100
+}
+
+// splitSentences breaks prose at sentence ends. Every text field ends a
+// sentence, so bullet points without a full stop count on their own.
+func splitSentences(text string) []string {
+	var sentences []string
+	start := 0
+	runes := []rune(text)
+	for i, r := range runes {
+		if (r == '.' || r == '!' || r == '?') && (i+1 == len(runes) || unicode.IsSpace(runes[i+1])) {
+			if s := strings.TrimSpace(string(runes[start : i+1])); s != "" {
+				sentences = append(sentences, s)
+			}
+			start = i + 1
+		}
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		sentences = append(sentences, s)
+	}
+	return sentences
+}
+
+// countSyllables estimates syllables by vowel groups, dropping a silent
+// final "e".
+func countSyllables(word string) int {
+	word = strings.ToLower(strings.TrimFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }))
+	count, vowel := 0, false
+	for _, r := range word {
+		isVowel := strings.ContainsRune("aeiouyäöüéèà", r)
+		if isVowel && !vowel {
+			count++
+		}
+		vowel = isVowel
+	}
+	if count > 1 && strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le") {
+		count--
+	}
+	if count == 0 {
+		count = 1
+	}
+	return count
+}
+
+func atsReadability(prose []string) models.ATSReadability {
+	r := models.ATSReadability{}
+	words, syllables := 0, 0
+	for _, p := range prose {
+		for _, s := range splitSentences(p) {
+			fields := strings.Fields(s)
+			r.Sentences++
+			words += len(fields)
+			if len(fields) > atsLongSentenceWords {
+				r.LongSentences++
+			}
+			for _, w := range fields {
+				syllables += countSyllables(w)
+			}
+		}
+	}
+	if r.Sentences == 0 || words == 0 {
+		return r
+	}
+
+	avg := float64(words) / float64(r.Sentences)
+	r.AvgSentenceWords = math.Round(avg*10) / 10
+	r.FleschReadingEase = math.Round((206.835-1.015*avg-84.6*float64(syllables)/float64(words))*10) / 10
+
+	score := 100.0
+	if avg > atsIdealSentenceWords {
+		score -= (avg - atsIdealSentenceWords) * 5
+	}
+	score -= 50 * float64(r.LongSentences) / float64(r.Sentences)
+	r.Score = int(math.Max(0, math.Round(score)))
+	return r
+}
+
+// AnalyzeCV returns the ATS analysis of the seeker's CV for a job. The
+// stored analysis is reused until the CV changes.
+func AnalyzeCV(ctx context.Context, db *mongo.Database, userID, jobID string) (models.ATSAnalysis, error) {
+	var cv models.CVData
+	if err := db.Collection(models.CollectionCV).FindOne(ctx, bson.M{"auth_user_id": userID, "job_id": jobID}).Decode(&cv); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ATSAnalysis{}, ErrCVNotFound
+		}
+		return models.ATSAnalysis{}, err
+	}
+
+	coll := db.Collection(models.CollectionATSAnalyses)
+	hash := hashSource(cv.CVData)
+	var stored models.ATSAnalysis
+	err := coll.FindOne(ctx, bson.M{"auth_user_id": userID, "job_id": jobID}).Decode(&stored)
+	if err == nil && stored.CVHash == hash {
+		return stored, nil
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return stored, err
+	}
+
+	job, _, err := NewJobStore(db).ForApplication(ctx, userID, jobID)
+	if err != nil {
+		return models.ATSAnalysis{}, err
+	}
+
+	analysis := AnalyzeATS(cv.CVData, job.Job)
+	analysis.AuthUserID = userID
+	analysis.JobID = jobID
+	analysis.Source = job.Origin
+	analysis.CVVersion = cv.Version
+	analysis.CVHash = hash
+	analysis.AnalyzedAt = time.Now()
+
+	err = coll.FindOneAndReplace(ctx, bson.M{"auth_user_id": userID, "job_id": jobID}, analysis,
+		options.FindOneAndReplace().SetUpsert(true).SetReturnDocument(options.After)).Decode(&analysis)
+	return analysis, err
+}
+
+// ATSScores returns the stored ATS score of each analysed job.
+func ATSScores(ctx context.Context, db *mongo.Database, userID string, jobIDs []string) (map[string]int, error) {
+	scores := map[string]int{}
+	if len(jobIDs) == 0 {
+		return scores, nil
+	}
+	cursor, err := db.Collection(models.CollectionATSAnalyses).Find(ctx,
+		bson.M{"auth_user_id": userID, "job_id": bson.M{"$in": jobIDs}},
+		options.Find().SetProjection(bson.M{"job_id": 1, "ats_score": 1}))
+	if err != nil {
+		return scores, err
+	}
+	var list []models.ATSAnalysis
+	if err := cursor.All(ctx, &list); err != nil {
+		return scores, err
+	}
+	for _, a := range list {
+		scores[a.JobID] = a.Score
+	}
+	return scores, nil
+}
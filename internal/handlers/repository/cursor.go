@@ -0,0 +1,227 @@
+package repository
+
+import (
+	"RAAS/core/security"
+
+	"context"
+	"errors"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrCursorMismatch is returned when a cursor was issued for a different list ordering.
+var ErrCursorMismatch = errors.New("cursor does not belong to this list")
+
+// CursorPage is the result metadata of one cursor-paginated query.
+type CursorPage struct {
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+	PerPage    int
+}
+
+// CursorRequest reports whether the request asked for cursor pagination and
+// returns the decoded cursor (nil on the first page) and the page size.
+func CursorRequest(c *gin.Context) (bool, *security.PageCursor, int) {
+	pagination := c.MustGet("pagination").(gin.H)
+	limit := pagination["limit"].(int)
+	if pagination["mode"] != "cursor" {
+		return false, nil, limit
+	}
+	cur, _ := pagination["cursor"].(*security.PageCursor)
+	return true, cur, limit
+}
+
+// keysetFilter selects documents after (or before, for backward cursors) the
+// cursor position in a descending sortField, _id order. Documents with a
+// missing sort field sort last.
+func keysetFilter(sortField string, cur *security.PageCursor) bson.M {
+	if sortField == "_id" {
+		if cur.Backward {
+			return bson.M{"_id": bson.M{"$gt": cur.ID}}
+		}
+		return bson.M{"_id": bson.M{"$lt": cur.ID}}
+	}
+
+	idOp, valueOp := "$lt", "$lt"
+	if cur.Backward {
+		idOp, valueOp = "$gt", "$gt"
+	}
+
+	if cur.Value == nil {
+		if cur.Backward {
+			return bson.M{"$or": bson.A{
+				bson.M{sortField: bson.M{"$ne": nil}},
+				bson.M{sortField: nil, "_id": bson.M{idOp: cur.ID}},
+			}}
+		}
+		return bson.M{sortField: nil, "_id": bson.M{idOp: cur.ID}}
+	}
+
+	or := bson.A{
+		bson.M{sortField: bson.M{valueOp: cur.Value}},
+		bson.M{sortField: cur.Value, "_id": bson.M{idOp: cur.ID}},
+	}
+	if !cur.Backward {
+		or = append(or, bson.M{sortField: nil})
+	}
+	return bson.M{"$or": or}
+}
+
+// FindPageByCursor returns one page of documents ordered by sortField and
+// _id descending, starting after cur. A nil cursor returns the first page.
+func FindPageByCursor[T any](ctx context.Context, coll *mongo.Collection, filter bson.M, sortField string, cur *security.PageCursor, limit int) ([]T, CursorPage, error) {
+	query, sort, err := keysetQuery(filter, sortField, cur)
+	if err != nil {
+		return nil, CursorPage{PerPage: limit}, err
+	}
+	cursor, err := coll.Find(ctx, query, options.Find().SetSort(sort).SetLimit(int64(limit+1)))
+	if err != nil {
+		return nil, CursorPage{PerPage: limit}, err
+	}
+	return readCursorPage[T](ctx, cursor, sortField, cur, limit)
+}
+
+// AggregatePageByCursor is FindPageByCursor for documents that must also
+// pass stages, such as a $lookup into another collection. The stages run
+// after the keyset sort, so only as many documents are read as the page needs.
+func AggregatePageByCursor[T any](ctx context.Context, coll *mongo.Collection, filter bson.M, sortField string, cur *security.PageCursor, limit int, stages mongo.Pipeline) ([]T, CursorPage, error) {
+	query, sort, err := keysetQuery(filter, sortField, cur)
+	if err != nil {
+		return nil, CursorPage{PerPage: limit}, err
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$sort", Value: sort}},
+	}
+	pipeline = append(pipeline, stages...)
+	pipeline = append(pipeline, bson.D{{Key: "$limit", Value: limit + 1}})
+	cursor, err := coll.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, CursorPage{PerPage: limit}, err
+	}
+	return readCursorPage[T](ctx, cursor, sortField, cur, limit)
+}
+
+// keysetQuery returns the filter and sort of the page after cur.
+func keysetQuery(filter bson.M, sortField string, cur *security.PageCursor) (bson.M, bson.D, error) {
+	query := bson.M{}
+	for k, v := range filter {
+		query[k] = v
+	}
+	dir := -1
+	if cur != nil {
+		if cur.Field != sortField {
+			return nil, nil, ErrCursorMismatch
+		}
+		query = bson.M{"$and": bson.A{query, keysetFilter(sortField, cur)}}
+		if cur.Backward {
+			dir = 1
+		}
+	}
+
+	sort := bson.D{{Key: sortField, Value: dir}}
+	if sortField != "_id" {
+		sort = append(sort, bson.E{Key: "_id", Value: dir})
+	}
+	return query, sort, nil
+}
+
+// readCursorPage decodes up to limit documents of a query that fetched one
+// extra document to tell whether another page follows.
+func readCursorPage[T any](ctx context.Context, cursor *mongo.Cursor, sortField string, cur *security.PageCursor, limit int) ([]T, CursorPage, error) {
+	page := CursorPage{PerPage: limit}
+	defer cursor.Close(ctx)
+
+	var raws []bson.Raw
+	for cursor.Next(ctx) {
+		raws = append(raws, append(bson.Raw(nil), cursor.Current...))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, page, err
+	}
+
+	more := len(raws) > limit
+	if more {
+		raws = raws[:limit]
+	}
+	if cur != nil && cur.Backward {
+		for i, j := 0, len(raws)-1; i < j; i, j = i+1, j-1 {
+			raws[i], raws[j] = raws[j], raws[i]
+		}
+		page.HasPrev, page.HasNext = more, true
+	} else {
+		page.HasNext, page.HasPrev = more, cur != nil
+	}
+
+	items := make([]T, 0, len(raws))
+	for _, raw := range raws {
+		var item T
+		if err := bson.Unmarshal(raw, &item); err != nil {
+			return nil, page, err
+		}
+		items = append(items, item)
+	}
+
+	var err error
+	if len(raws) > 0 {
+		if page.HasNext {
+			if page.NextCursor, err = cursorAt(raws[len(raws)-1], sortField, false); err != nil {
+				return nil, page, err
+			}
+		}
+		if page.HasPrev {
+			if page.PrevCursor, err = cursorAt(raws[0], sortField, true); err != nil {
+				return nil, page, err
+			}
+		}
+	}
+	return items, page, nil
+}
+
+func cursorAt(doc bson.Raw, sortField string, backward bool) (string, error) {
+	cur := security.PageCursor{Field: sortField, Backward: backward}
+	if err := doc.Lookup("_id").Unmarshal(&cur.ID); err != nil {
+		return "", err
+	}
+	if val, err := doc.LookupErr(sortField); err == nil {
+		if err := val.Unmarshal(&cur.Value); err != nil {
+			return "", err
+		}
+	}
+	return security.EncodeCursor(cur)
+}
+
+// CursorPagination builds the uniform pagination block for cursor-mode
+// responses, with next/prev links that keep the request's other query params.
+func CursorPagination(c *gin.Context, page CursorPage) gin.H {
+	link := func(token string) string {
+		if token == "" {
+			return ""
+		}
+		q := url.Values{}
+		for k, v := range c.Request.URL.Query() {
+			q[k] = v
+		}
+		q.Del("offset")
+		q.Del("paginate")
+		q.Set("cursor", token)
+		return c.Request.URL.Path + "?" + q.Encode()
+	}
+
+	return gin.H{
+		"mode":        "cursor",
+		"next":        link(page.NextCursor),
+		"prev":        link(page.PrevCursor),
+		"next_cursor": page.NextCursor,
+		"prev_cursor": page.PrevCursor,
+		"has_next":    page.HasNext,
+		"has_prev":    page.HasPrev,
+		"per_page":    page.PerPage,
+	}
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/models"
+
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Diff operations
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// lcsLimit bounds the list sizes aligned by longest common subsequence;
+// longer lists are compared index by index.
+const lcsLimit = 500
+
+// DiffDocumentVersions compares the content of two versions of a document.
+func DiffDocumentVersions(from, to models.DocumentVersion) dto.DocumentDiff {
+	diff := dto.DocumentDiff{Kind: to.Kind, From: from.Version, To: to.Version, Changes: []dto.DocumentChange{}}
+	diffValue("", plainJSON(from.Data), plainJSON(to.Data), &diff.Changes)
+	for _, ch := range diff.Changes {
+		switch ch.Op {
+		case DiffAdded:
+			diff.Added++
+		case DiffRemoved:
+			diff.Removed++
+		default:
+			diff.Changed++
+		}
+	}
+	return diff
+}
+
+// plainJSON converts BSON-decoded values into plain JSON values, so the
+// same content compares equal whatever driver types it was decoded into.
+func plainJSON(v interface{}) interface{} {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if json.Unmarshal(raw, &out) != nil {
+		return v
+	}
+	return out
+}
+
+func diffValue(path string, a, b interface{}, changes *[]dto.DocumentChange) {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		if bv, ok := b.(map[string]interface{}); ok {
+			diffObject(path, av, bv, changes)
+			return
+		}
+	case []interface{}:
+		if bv, ok := b.([]interface{}); ok {
+			diffList(path, av, bv, changes)
+			return
+		}
+	}
+	if !reflect.DeepEqual(a, b) {
+		*changes = append(*changes, dto.DocumentChange{Path: path, Op: DiffChanged, From: a, To: b})
+	}
+}
+
+func diffObject(path string, a, b map[string]interface{}, changes *[]dto.DocumentChange) {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		p := k
+		if path != "" {
+			p = path + "." + k
+		}
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case !inA:
+			*changes = append(*changes, dto.DocumentChange{Path: p, Op: DiffAdded, To: bv})
+		case !inB:
+			*changes = append(*changes, dto.DocumentChange{Path: p, Op: DiffRemoved, From: av})
+		default:
+			diffValue(p, av, bv, changes)
+		}
+	}
+}
+
+// diffList aligns two lists on their unchanged items, so inserting an entry
+// is reported as one addition rather than a change of every later entry.
+// Between two unchanged items, entries that still look alike (objects
+// sharing most of their fields) are compared field by field.
+func diffList(path string, a, b []interface{}, changes *[]dto.DocumentChange) {
+	at := func(i int) string { return fmt.Sprintf("%s[%d]", path, i) }
+
+	i, j := 0, 0
+	for _, m := range commonItems(a, b, reflect.DeepEqual) {
+		diffRun(a[i:m[0]], b[j:m[1]], i, j, at, changes)
+		i, j = m[0]+1, m[1]+1
+	}
+	diffRun(a[i:], b[j:], i, j, at, changes)
+}
+
+// diffRun compares the unmatched items between two unchanged ones; i and j
+// are the offsets of the run in the old and new list.
+func diffRun(a, b []interface{}, i, j int, at func(int) string, changes *[]dto.DocumentChange) {
+	x, y := 0, 0
+	for _, m := range commonItems(a, b, similar) {
+		pairRun(a[x:m[0]], b[y:m[1]], i+x, j+y, at, changes)
+		diffValue(at(j+m[1]), a[m[0]], b[m[1]], changes)
+		x, y = m[0]+1, m[1]+1
+	}
+	pairRun(a[x:], b[y:], i+x, j+y, at, changes)
+}
+
+// pairRun compares items position by position and reports the surplus of
+// either side as removed or added.
+func pairRun(a, b []interface{}, i, j int, at func(int) string, changes *[]dto.DocumentChange) {
+	n := 0
+	for ; n < len(a) && n < len(b); n++ {
+		diffValue(at(j+n), a[n], b[n], changes)
+	}
+	for k := n; k < len(a); k++ {
+		*changes = append(*changes, dto.DocumentChange{Path: at(i + k), Op: DiffRemoved, From: a[k]})
+	}
+	for k := n; k < len(b); k++ {
+		*changes = append(*changes, dto.DocumentChange{Path: at(j + k), Op: DiffAdded, To: b[k]})
+	}
+}
+
+// similar reports whether two objects keep at least half of their fields.
+func similar(a, b interface{}) bool {
+	am, ok := a.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	bm, ok := b.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	same := 0
+	for k, v := range am {
+		if w, ok := bm[k]; ok && reflect.DeepEqual(v, w) {
+			same++
+		}
+	}
+	size := len(am)
+	if len(bm) > size {
+		size = len(bm)
+	}
+	return same > 0 && same*2 >= size
+}
+
+// commonItems returns the index pairs of the longest common subsequence of
+// items of a and b that match by eq.
+func commonItems(a, b []interface{}, eq func(x, y interface{}) bool) [][2]int {
+	if len(a) == 0 || len(b) == 0 || len(a) > lcsLimit || len(b) > lcsLimit {
+		return nil
+	}
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if eq(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var pairs [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case eq(a[i], b[j]):
+			pairs = append(pairs, [2]int{i, j})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return pairs
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+	"RAAS/internal/render"
+
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrDocumentNotFound is returned when no CV or cover letter was generated for the job.
+	ErrDocumentNotFound = errors.New("document not found")
+	// ErrUnknownTemplate is returned for template names not offered for the document kind.
+	ErrUnknownTemplate = errors.New("unknown template")
+)
+
+// RenderSource is the seeker data shared by the documents of one download.
+type RenderSource struct {
+	Profile   render.Profile
+	Templates map[string]string // preferred template per document kind
+}
+
+// LoadRenderSource reads the profile, contact details, photo and preferred
+// templates of a seeker.
+func LoadRenderSource(ctx context.Context, db *mongo.Database, userID string) (RenderSource, error) {
+	var seeker models.Seeker
+	if err := db.Collection(models.CollectionSeekers).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+		return RenderSource{}, err
+	}
+	src := RenderSource{
+		Profile:   render.Profile{Title: seeker.PrimaryTitle},
+		Templates: map[string]string{render.KindCV: seeker.CvFormat, render.KindCoverLetter: seeker.ClFormat},
+	}
+
+	if info, err := GetPersonalInfo(&seeker); err == nil {
+		name := info.FirstName
+		if info.SecondName != nil {
+			name += " " + *info.SecondName
+		}
+		src.Profile.Name = strings.TrimSpace(name)
+		if info.City != nil {
+			src.Profile.City = *info.City
+		}
+		if info.LinkedInProfile != nil {
+			src.Profile.LinkedIn = *info.LinkedInProfile
+		}
+	}
+
+	var authUser models.AuthUser
+	if err := db.Collection(models.CollectionAuthUsers).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&authUser); err == nil {
+		src.Profile.Email, src.Profile.Phone = authUser.Email, authUser.Phone
+	}
+
+	var pic struct {
+		Image []byte `bson:"image"`
+	}
+	if err := db.Collection(models.CollectionProfilePic).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&pic); err == nil {
+		src.Profile.Photo = pic.Image
+	}
+	return src, nil
+}
+
+// RenderGeneratedDocument renders the CV or cover letter generated for a
+// job. The template is the requested one, else the one stored with the
+// document, else the seeker's preference, else the default.
+func RenderGeneratedDocument(ctx context.Context, db *mongo.Database, src RenderSource, userID, jobID, kind, format, template string) (render.File, error) {
+	if template != "" {
+		if _, ok := render.LookupTemplate(kind, template); !ok {
+			return render.File{}, ErrUnknownTemplate
+		}
+	}
+
+	filter := bson.M{"auth_user_id": userID, "job_id": jobID}
+	var (
+		doc  render.Document
+		name string
+	)
+	switch kind {
+	case render.KindCV:
+		var cv models.CVData
+		if err := db.Collection(models.CollectionCV).FindOne(ctx, filter).Decode(&cv); err != nil {
+			return render.File{}, documentError(err)
+		}
+		doc = render.CVDocument(cv.CVData, src.Profile)
+		template = firstTemplate(kind, template, cv.CvFormat, src.Templates[kind])
+		name = render.FileName(doc.Header.Name, "CV")
+	case render.KindCoverLetter:
+		var cl models.CoverLetterData
+		if err := db.Collection(models.CollectionCoverLetters).FindOne(ctx, filter).Decode(&cl); err != nil {
+			return render.File{}, documentError(err)
+		}
+		doc = render.CoverLetterDocument(cl.CLData, src.Profile)
+		template = firstTemplate(kind, template, cl.ClFormat, src.Templates[kind])
+		name = render.FileName(doc.Header.Name, "Cover_Letter")
+	default:
+		return render.File{}, ErrDocumentNotFound
+	}
+
+	return render.Render(doc, render.TemplateFor(kind, template), format, name)
+}
+
+// firstTemplate returns the first of names that is a template of kind.
+func firstTemplate(kind string, names ...string) string {
+	for _, n := range names {
+		if _, ok := render.LookupTemplate(kind, n); ok {
+			return n
+		}
+	}
+	return ""
+}
+
+func documentError(err error) error {
+	if err == mongo.ErrNoDocuments {
+		return ErrDocumentNotFound
+	}
+	return err
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrVersionNotFound is returned when a document has no such version.
+var ErrVersionNotFound = errors.New("document version not found")
+
+// documentFields names the live collection and fields of each document kind.
+var documentFields = map[string]struct{ collection, data, format string }{
+	models.DocumentKindCV:          {models.CollectionCV, "cv_data", "cv_format"},
+	models.DocumentKindCoverLetter: {models.CollectionCoverLetters, "cl_data", "cl_format"},
+}
+
+func versionFilter(userID, jobID, kind string) bson.M {
+	return bson.M{"auth_user_id": userID, "job_id": jobID, "kind": kind}
+}
+
+// RecordDocumentVersion appends a version to the history of a document,
+// marks it as the live document's current version and prunes versions
+// beyond the seeker's retention cap. opts are the cover letter options the
+// content was generated with, if any.
+func RecordDocumentVersion(ctx context.Context, db *mongo.Database, userID, jobID, kind string, data map[string]interface{}, format, author string, restoredFrom int, opts *models.CoverLetterOptions) (models.DocumentVersion, error) {
+	fields, ok := documentFields[kind]
+	if !ok {
+		return models.DocumentVersion{}, ErrDocumentNotFound
+	}
+	versions := db.Collection(models.CollectionDocumentVersions)
+	v := models.DocumentVersion{
+		AuthUserID:   userID,
+		JobID:        jobID,
+		Kind:         kind,
+		Author:       author,
+		Format:       format,
+		Data:         data,
+		RestoredFrom: restoredFrom,
+		Options:      opts,
+		CreatedAt:    time.Now(),
+	}
+
+	// The unique index rejects a number taken by a concurrent save; retry with the next one
+	for attempt := 0; ; attempt++ {
+		latest, err := latestVersion(ctx, versions, userID, jobID, kind)
+		if err != nil {
+			return v, err
+		}
+		v.Version = latest + 1
+		res, err := versions.InsertOne(ctx, v)
+		if err == nil {
+			v.ID, _ = res.InsertedID.(primitive.ObjectID)
+			break
+		}
+		if !mongo.IsDuplicateKeyError(err) || attempt == 2 {
+			return v, err
+		}
+	}
+
+	if _, err := db.Collection(fields.collection).UpdateOne(ctx,
+		bson.M{"auth_user_id": userID, "job_id": jobID},
+		bson.M{"$set": bson.M{"version": v.Version}},
+	); err != nil {
+		return v, err
+	}
+	return v, pruneDocumentVersions(ctx, db, userID, jobID, kind)
+}
+
+func latestVersion(ctx context.Context, versions *mongo.Collection, userID, jobID, kind string) (int, error) {
+	var latest models.DocumentVersion
+	err := versions.FindOne(ctx, versionFilter(userID, jobID, kind),
+		options.FindOne().SetSort(bson.D{{Key: "version", Value: -1}}).SetProjection(bson.M{"version": 1}),
+	).Decode(&latest)
+	if err == mongo.ErrNoDocuments {
+		return 0, nil
+	}
+	return latest.Version, err
+}
+
+// pruneDocumentVersions deletes the oldest versions beyond the cap of the
+// seeker's subscription tier.
+func pruneDocumentVersions(ctx context.Context, db *mongo.Database, userID, jobID, kind string) error {
+	var seeker struct {
+		SubscriptionTier string `bson:"subscription_tier"`
+	}
+	_ = db.Collection(models.CollectionSeekers).FindOne(ctx, bson.M{"auth_user_id": userID},
+		options.FindOne().SetProjection(bson.M{"subscription_tier": 1})).Decode(&seeker)
+
+	versions := db.Collection(models.CollectionDocumentVersions)
+	cursor, err := versions.Find(ctx, versionFilter(userID, jobID, kind), options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetSkip(int64(models.VersionRetention(seeker.SubscriptionTier))).
+		SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return err
+	}
+	var stale []struct {
+		ID interface{} `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &stale); err != nil || len(stale) == 0 {
+		return err
+	}
+	ids := make(bson.A, 0, len(stale))
+	for _, s := range stale {
+		ids = append(ids, s.ID)
+	}
+	_, err = versions.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
+// UpdateDocument replaces the content of a live CV or cover letter and
+// records it as a new version. A document without history gets its previous
+// content recorded first, so the edit can be undone.
+func UpdateDocument(ctx context.Context, db *mongo.Database, userID, jobID, kind string, data map[string]interface{}, format string, restoredFrom int) (models.DocumentVersion, error) {
+	return replaceDocument(ctx, db, userID, jobID, kind, data, format, models.VersionAuthorUser, restoredFrom, nil)
+}
+
+// RegenerateDocument replaces a live document with newly generated content
+// and records it as a generator version with the options used.
+func RegenerateDocument(ctx context.Context, db *mongo.Database, userID, jobID, kind string, data map[string]interface{}, format string, opts *models.CoverLetterOptions) (models.DocumentVersion, error) {
+	return replaceDocument(ctx, db, userID, jobID, kind, data, format, models.VersionAuthorGenerator, 0, opts)
+}
+
+// SaveGeneratedDocument stores newly generated content as the live document
+// and records it as a generator version. An existing document, left by an
+// earlier generation for the same job, is replaced and keeps its history.
+func SaveGeneratedDocument(ctx context.Context, db *mongo.Database, userID, jobID, kind string, data map[string]interface{}, format string, opts *models.CoverLetterOptions) (models.DocumentVersion, error) {
+	fields, ok := documentFields[kind]
+	if !ok {
+		return models.DocumentVersion{}, ErrDocumentNotFound
+	}
+	_, err := db.Collection(fields.collection).InsertOne(ctx, bson.M{
+		"auth_user_id": userID,
+		"job_id":       jobID,
+		fields.data:    data,
+		fields.format:  format,
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return replaceDocument(ctx, db, userID, jobID, kind, data, format, models.VersionAuthorGenerator, 0, opts)
+	}
+	if err != nil {
+		return models.DocumentVersion{}, err
+	}
+	version, err := RecordDocumentVersion(ctx, db, userID, jobID, kind, data, format, models.VersionAuthorGenerator, 0, opts)
+	if err == nil {
+		refreshATSAnalysis(ctx, db, userID, jobID, kind)
+	}
+	return version, err
+}
+
+func replaceDocument(ctx context.Context, db *mongo.Database, userID, jobID, kind string, data map[string]interface{}, format, author string, restoredFrom int, opts *models.CoverLetterOptions) (models.DocumentVersion, error) {
+	fields, ok := documentFields[kind]
+	if !ok {
+		return models.DocumentVersion{}, ErrDocumentNotFound
+	}
+
+	var previous bson.M
+	err := db.Collection(fields.collection).FindOneAndUpdate(ctx,
+		bson.M{"auth_user_id": userID, "job_id": jobID},
+		bson.M{"$set": bson.M{fields.data: data, fields.format: format}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&previous)
+	if err == mongo.ErrNoDocuments {
+		return models.DocumentVersion{}, ErrDocumentNotFound
+	} else if err != nil {
+		return models.DocumentVersion{}, err
+	}
+
+	if _, versioned := previous["version"]; !versioned {
+		oldData, _ := previous[fields.data].(bson.M)
+		oldFormat, _ := previous[fields.format].(string)
+		if _, err := RecordDocumentVersion(ctx, db, userID, jobID, kind, oldData, oldFormat, models.VersionAuthorGenerator, 0, nil); err != nil {
+			return models.DocumentVersion{}, err
+		}
+	}
+	version, err := RecordDocumentVersion(ctx, db, userID, jobID, kind, data, format, author, restoredFrom, opts)
+	if err == nil {
+		refreshATSAnalysis(ctx, db, userID, jobID, kind)
+	}
+	return version, err
+}
+
+// refreshATSAnalysis redoes the stored ATS analysis after the CV changed, so
+// the tracker never shows the score of an older CV.
+func refreshATSAnalysis(ctx context.Context, db *mongo.Database, userID, jobID, kind string) {
+	if kind != models.DocumentKindCV {
+		return
+	}
+	if _, err := AnalyzeCV(ctx, db, userID, jobID); err != nil {
+		log.Printf("⚠️ Failed to analyse CV of job %s: %v", jobID, err)
+	}
+}
+
+// ListDocumentVersions returns the history of a document, newest first,
+// without the content.
+func ListDocumentVersions(ctx context.Context, db *mongo.Database, userID, jobID, kind string) ([]models.DocumentVersion, error) {
+	cursor, err := db.Collection(models.CollectionDocumentVersions).Find(ctx, versionFilter(userID, jobID, kind), options.Find().
+		SetSort(bson.D{{Key: "version", Value: -1}}).
+		SetProjection(bson.M{"data": 0}))
+	if err != nil {
+		return nil, err
+	}
+	versions := []models.DocumentVersion{}
+	err = cursor.All(ctx, &versions)
+	return versions, err
+}
+
+// GetDocumentVersion loads one version with its content.
+func GetDocumentVersion(ctx context.Context, db *mongo.Database, userID, jobID, kind string, version int) (models.DocumentVersion, error) {
+	filter := versionFilter(userID, jobID, kind)
+	filter["version"] = version
+	var v models.DocumentVersion
+	err := db.Collection(models.CollectionDocumentVersions).FindOne(ctx, filter).Decode(&v)
+	if err == mongo.ErrNoDocuments {
+		return v, ErrVersionNotFound
+	}
+	return v, err
+}
+
+// RestoreDocumentVersion makes the content of an old version live again as
+// a new version; the history itself is never rewritten.
+func RestoreDocumentVersion(ctx context.Context, db *mongo.Database, userID, jobID, kind string, version int) (models.DocumentVersion, error) {
+	old, err := GetDocumentVersion(ctx, db, userID, jobID, kind, version)
+	if err != nil {
+		return old, err
+	}
+	return replaceDocument(ctx, db, userID, jobID, kind, old.Data, old.Format, models.VersionAuthorUser, old.Version, old.Options)
+}
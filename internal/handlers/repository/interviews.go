@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+	"RAAS/utils"
+
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ApplicationJobInfo returns the title and company of the job behind an
+// application, looking in external_jobs for external applications.
+func ApplicationJobInfo(ctx context.Context, db *mongo.Database, app models.SelectedJobApplication) (title, company string) {
+	if app.Source == models.ApplicationSourceManual {
+		return app.Title, app.Company
+	}
+	job, _ := NewJobStore(db).GetFrom(ctx, app.JobID, app.Source)
+	if job.Company == "" {
+		job.Company = app.Company
+	}
+	return job.Title, job.Company
+}
+
+// InterviewICSEvent converts an interview into a calendar event.
+func InterviewICSEvent(e models.InterviewEvent) utils.ICSEvent {
+	summary := fmt.Sprintf("Interview (round %d)", e.Round)
+	if e.Company != "" {
+		summary = fmt.Sprintf("Interview round %d: %s", e.Round, e.Company)
+	}
+
+	var desc []string
+	if e.Title != "" {
+		desc = append(desc, "Position: "+e.Title)
+	}
+	if e.Interviewer != "" {
+		desc = append(desc, "Interviewer: "+e.Interviewer)
+	}
+	if e.VideoLink != "" {
+		desc = append(desc, "Join: "+e.VideoLink)
+	}
+	if e.Notes != "" {
+		desc = append(desc, e.Notes)
+	}
+
+	location := e.Location
+	if location == "" {
+		location = e.VideoLink
+	}
+
+	return utils.ICSEvent{
+		UID:         e.ID.Hex() + "@interviews.raas",
+		Start:       e.StartsAt,
+		End:         e.EndsAt(),
+		Summary:     summary,
+		Description: strings.Join(desc, "\n"),
+		Location:    location,
+		URL:         e.VideoLink,
+		Updated:     e.UpdatedAt,
+		Cancelled:   e.Cancelled,
+	}
+}
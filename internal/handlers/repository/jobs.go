@@ -5,7 +5,10 @@ import (
 
 	"context"
 	"fmt"
+	"strconv"
 	"time"
+
+	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 )
@@ -56,7 +59,7 @@ func FetchAppliedJobIDs(ctx context.Context, collection *mongo.Collection, userI
 	filter := bson.M{
 		"auth_user_id": userID,
 		"status": bson.M{
-			"$in": []string{"applied", "interview", "rejected", "selected"},
+			"$in": append([]string{"selected"}, models.SubmittedApplicationStatuses...),
 		},
 	}
 
@@ -80,18 +83,45 @@ func FetchAppliedJobIDs(ctx context.Context, collection *mongo.Collection, userI
 }
 
 
-func BuildJobFilter(preferredTitles, appliedJobIDs []string, jobLang string) bson.M {
+// Retrieval window for job postings, in days.
+const (
+	DefaultPostedWithinDays = 14
+	MaxPostedWithinDays     = 90
+)
+
+// ParsePostedWithinDays reads the "posted_within_days" query parameter,
+// falling back to DefaultPostedWithinDays and capping at MaxPostedWithinDays.
+func ParsePostedWithinDays(c *gin.Context) int {
+	days, err := strconv.Atoi(c.Query("posted_within_days"))
+	if err != nil || days <= 0 {
+		return DefaultPostedWithinDays
+	}
+	if days > MaxPostedWithinDays {
+		return MaxPostedWithinDays
+	}
+	return days
+}
+
+// PostedSince returns the earliest posted_date included in a window of the given days.
+func PostedSince(days int) time.Time {
+	if days <= 0 {
+		days = DefaultPostedWithinDays
+	}
+	return time.Now().AddDate(0, 0, -days)
+}
+
+func BuildJobFilter(preferredTitles, appliedJobIDs []string, jobLang string, postedWithinDays int) bson.M {
 	var andConditions []bson.M
 
-	// Step 0: Filter jobs within last 14 days
-	twoWeeksAgo := time.Now().AddDate(0, 0, -14).Format("2006-01-02")
-	andConditions = append(andConditions, bson.M{"posted_date": bson.M{"$gte": twoWeeksAgo}})
+	// Step 0: Active jobs posted within the retrieval window
+	andConditions = append(andConditions, models.ActiveJobFilter())
+	andConditions = append(andConditions, bson.M{"posted_date": bson.M{"$gte": PostedSince(postedWithinDays)}})
 
 	// Step 1: Preferred job titles from user
 	if len(preferredTitles) > 0 {
 		var titleConditions []bson.M
 		for _, title := range preferredTitles {
-			titleConditions = append(titleConditions, bson.M{"title": bson.M{"$regex": title, "$options": "i"}})
+			titleConditions = append(titleConditions, bson.M{"title": ContainsRegex(title)})
 		}
 		andConditions = append(andConditions, bson.M{"$or": titleConditions})
 	}
@@ -103,7 +133,7 @@ func BuildJobFilter(preferredTitles, appliedJobIDs []string, jobLang string) bso
 
 	// Step 3: Optional job language filter
 	if jobLang != "" {
-		andConditions = append(andConditions, bson.M{"job_language": bson.M{"$regex": jobLang, "$options": "i"}})
+		andConditions = append(andConditions, bson.M{"job_language": ContainsRegex(jobLang)})
 	}
 
 	return bson.M{"$and": andConditions}
@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/models"
+
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Sort modes accepted by the job search endpoint.
+const (
+	JobSearchSortRelevance  = "relevance"
+	JobSearchSortDate       = "date"
+	JobSearchSortMatchScore = "match_score"
+)
+
+// Buckets reported by the "posted_within" facet, in days.
+var postedWithinBuckets = []int{1, 3, 7, 14, 30}
+
+// ExactMatchRegex builds a case-insensitive regex that matches the user input
+// literally (anchored), so query parameters cannot inject regex operators.
+func ExactMatchRegex(value string) bson.M {
+	return bson.M{"$regex": "^" + regexp.QuoteMeta(strings.TrimSpace(value)) + "$", "$options": "i"}
+}
+
+// ContainsRegex builds a case-insensitive "contains" regex from escaped user input.
+func ContainsRegex(value string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(strings.TrimSpace(value)), "$options": "i"}
+}
+
+// buildJobSearchMatch builds the $match stage shared by results and facets.
+func buildJobSearchMatch(q dto.JobSearchQuery) bson.M {
+	match := bson.M{
+		"is_active":   bson.M{"$ne": false},
+		"posted_date": bson.M{"$gte": PostedSince(q.PostedWithinDays)},
+	}
+	if q.Q != "" {
+		match["$text"] = bson.M{"$search": q.Q}
+	}
+	if q.JobType != "" {
+		match["job_type"] = ExactMatchRegex(q.JobType)
+	}
+	if q.Location != "" {
+		match["location"] = ContainsRegex(q.Location)
+	}
+	if q.JobLang != "" {
+		match["job_language"] = ExactMatchRegex(q.JobLang)
+	}
+	if q.Company != "" {
+		match["company"] = ContainsRegex(q.Company)
+	}
+	return match
+}
+
+func facetCount(field string) bson.A {
+	return bson.A{
+		bson.M{"$match": bson.M{field: bson.M{"$nin": bson.A{nil, ""}}}},
+		bson.M{"$group": bson.M{"_id": "$" + field, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": 20},
+		bson.M{"$project": bson.M{"_id": 0, "value": "$_id", "count": 1}},
+	}
+}
+
+func postedWithinFacet() bson.A {
+	group := bson.M{"_id": nil}
+	for _, days := range postedWithinBuckets {
+		group[bucketKey(days)] = bson.M{"$sum": bson.M{"$cond": bson.A{
+			bson.M{"$gte": bson.A{"$posted_date", PostedSince(days)}}, 1, 0,
+		}}}
+	}
+	return bson.A{bson.M{"$group": group}}
+}
+
+func bucketKey(days int) string {
+	return fmt.Sprintf("d%d", days)
+}
+
+// BuildJobSearchPipeline returns an aggregation that yields a single document
+// with "results", "total" and one array per facet.
+func BuildJobSearchPipeline(userID string, q dto.JobSearchQuery, offset, limit int) mongo.Pipeline {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: buildJobSearchMatch(q)}},
+	}
+	if q.Q != "" {
+		pipeline = append(pipeline, bson.D{{Key: "$addFields", Value: bson.M{
+			"text_score": bson.M{"$meta": "textScore"},
+		}}})
+	}
+
+	// Attach the user's match score (defaults to 50 like GetMatchScoreForJob)
+	pipeline = append(pipeline,
+		bson.D{{Key: "$lookup", Value: bson.M{
+			"from": models.CollectionMatchScores,
+			"let":  bson.M{"jid": "$job_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": bson.M{"$expr": bson.M{"$and": bson.A{
+					bson.M{"$eq": bson.A{"$job_id", "$$jid"}},
+					bson.M{"$eq": bson.A{"$auth_user_id", userID}},
+				}}}},
+				bson.M{"$project": bson.M{"_id": 0, "match_score": 1}},
+			},
+			"as": "score",
+		}}},
+		bson.D{{Key: "$addFields", Value: bson.M{
+			"match_score": bson.M{"$ifNull": bson.A{bson.M{"$arrayElemAt": bson.A{"$score.match_score", 0}}, 50}},
+		}}},
+		bson.D{{Key: "$project", Value: bson.M{"score": 0}}},
+	)
+
+	if q.MinMatchScore > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"match_score": bson.M{"$gte": q.MinMatchScore}}}})
+	}
+
+	var sort bson.D
+	switch q.Sort {
+	case JobSearchSortDate:
+		sort = bson.D{{Key: "posted_date", Value: -1}, {Key: "_id", Value: 1}}
+	case JobSearchSortMatchScore:
+		sort = bson.D{{Key: "match_score", Value: -1}, {Key: "_id", Value: 1}}
+	default:
+		if q.Q != "" {
+			sort = bson.D{{Key: "text_score", Value: -1}, {Key: "match_score", Value: -1}, {Key: "_id", Value: 1}}
+		} else {
+			sort = bson.D{{Key: "match_score", Value: -1}, {Key: "posted_date", Value: -1}, {Key: "_id", Value: 1}}
+		}
+	}
+
+	pipeline = append(pipeline, bson.D{{Key: "$facet", Value: bson.M{
+		"results": bson.A{
+			bson.M{"$sort": sort},
+			bson.M{"$skip": offset},
+			bson.M{"$limit": limit},
+		},
+		"total":         bson.A{bson.M{"$count": "count"}},
+		"job_type":      facetCount("job_type"),
+		"location":      facetCount("location"),
+		"job_language":  facetCount("job_language"),
+		"company":       facetCount("company"),
+		"posted_within": postedWithinFacet(),
+	}}})
+
+	return pipeline
+}
+
+// JobSearchResult is the decoded output of BuildJobSearchPipeline.
+type JobSearchResult struct {
+	Results []struct {
+		models.Job `bson:",inline"`
+		MatchScore float64 `bson:"match_score"`
+		TextScore  float64 `bson:"text_score"`
+	} `bson:"results"`
+	Total []struct {
+		Count int `bson:"count"`
+	} `bson:"total"`
+	JobType      []dto.FacetCount `bson:"job_type"`
+	Location     []dto.FacetCount `bson:"location"`
+	JobLanguage  []dto.FacetCount `bson:"job_language"`
+	Company      []dto.FacetCount `bson:"company"`
+	PostedWithin []bson.M         `bson:"posted_within"`
+}
+
+// SearchJobs runs the search aggregation against the jobs collection.
+func SearchJobs(ctx context.Context, db *mongo.Database, userID string, q dto.JobSearchQuery, offset, limit int) (*JobSearchResult, error) {
+	cursor, err := db.Collection(models.CollectionJobs).Aggregate(ctx, BuildJobSearchPipeline(userID, q, offset, limit))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var out JobSearchResult
+	if cursor.Next(ctx) {
+		if err := cursor.Decode(&out); err != nil {
+			return nil, err
+		}
+	}
+	return &out, cursor.Err()
+}
+
+// TotalCount returns the number of jobs that matched before pagination.
+func (r *JobSearchResult) TotalCount() int {
+	if len(r.Total) == 0 {
+		return 0
+	}
+	return r.Total[0].Count
+}
+
+// PostedWithinFacet flattens the posted_within group into ordered buckets.
+func (r *JobSearchResult) PostedWithinFacet() []dto.PostedWithinCount {
+	buckets := make([]dto.PostedWithinCount, 0, len(postedWithinBuckets))
+	var counts bson.M
+	if len(r.PostedWithin) > 0 {
+		counts = r.PostedWithin[0]
+	}
+	for _, days := range postedWithinBuckets {
+		n := 0
+		switch v := counts[bucketKey(days)].(type) {
+		case int32:
+			n = int(v)
+		case int64:
+			n = int(v)
+		}
+		buckets = append(buckets, dto.PostedWithinCount{Days: days, Count: n})
+	}
+	return buckets
+}
+
+// SearchCriteriaToQuery converts stored saved-search criteria into a search query.
+func SearchCriteriaToQuery(criteria models.SearchCriteria, postedWithinDays int) dto.JobSearchQuery {
+	return dto.JobSearchQuery{
+		Q:                criteria.Q,
+		JobType:          criteria.JobType,
+		Location:         criteria.Location,
+		JobLang:          criteria.JobLang,
+		Company:          criteria.Company,
+		MinMatchScore:    criteria.MinMatchScore,
+		PostedWithinDays: postedWithinDays,
+		Sort:             JobSearchSortDate,
+	}
+}
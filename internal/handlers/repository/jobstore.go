@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrJobNotFound is returned when a job is in neither jobs nor external_jobs.
+var ErrJobNotFound = errors.New("job not found")
+
+// JobStore resolves jobs by id from the internal and external collections
+// into one normalized type, so callers need not branch on the source.
+type JobStore interface {
+	// Get looks in jobs first, then in external_jobs.
+	Get(ctx context.Context, jobID string) (models.ResolvedJob, error)
+	// GetFrom looks only in the collection of an application source; an
+	// empty or manual source looks in both.
+	GetFrom(ctx context.Context, jobID, source string) (models.ResolvedJob, error)
+	// ForApplication resolves the job behind the seeker's application.
+	ForApplication(ctx context.Context, userID, jobID string) (models.ResolvedJob, models.SelectedJobApplication, error)
+}
+
+type mongoJobStore struct {
+	db *mongo.Database
+}
+
+func NewJobStore(db *mongo.Database) JobStore {
+	return &mongoJobStore{db: db}
+}
+
+func (s *mongoJobStore) Get(ctx context.Context, jobID string) (models.ResolvedJob, error) {
+	return s.GetFrom(ctx, jobID, "")
+}
+
+func (s *mongoJobStore) GetFrom(ctx context.Context, jobID, source string) (models.ResolvedJob, error) {
+	if source != models.ApplicationSourceExternal {
+		job, err := s.internal(ctx, jobID)
+		if err != ErrJobNotFound || source == models.ApplicationSourceInternal {
+			return job, err
+		}
+	}
+	return s.external(ctx, jobID)
+}
+
+func (s *mongoJobStore) ForApplication(ctx context.Context, userID, jobID string) (models.ResolvedJob, models.SelectedJobApplication, error) {
+	var app models.SelectedJobApplication
+	err := s.db.Collection(models.CollectionSelectedJobApps).FindOne(ctx, bson.M{"auth_user_id": userID, "job_id": jobID}).Decode(&app)
+	if err != nil && err != mongo.ErrNoDocuments {
+		return models.ResolvedJob{}, app, err
+	}
+	job, err := s.GetFrom(ctx, jobID, app.Source)
+	return job, app, err
+}
+
+func (s *mongoJobStore) internal(ctx context.Context, jobID string) (models.ResolvedJob, error) {
+	var job models.Job
+	err := s.db.Collection(models.CollectionJobs).FindOne(ctx, bson.M{"job_id": jobID}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return models.ResolvedJob{}, ErrJobNotFound
+	}
+	if err != nil {
+		return models.ResolvedJob{}, err
+	}
+	return models.ResolvedJob{Job: job, Origin: models.ApplicationSourceInternal}, nil
+}
+
+func (s *mongoJobStore) external(ctx context.Context, jobID string) (models.ResolvedJob, error) {
+	var job models.ExternalJob
+	err := s.db.Collection(models.CollectionExtJobs).FindOne(ctx, bson.M{"job_id": jobID}).Decode(&job)
+	if err == mongo.ErrNoDocuments {
+		return models.ResolvedJob{}, ErrJobNotFound
+	}
+	if err != nil {
+		return models.ResolvedJob{}, err
+	}
+	return models.ResolvedJob{Job: job.AsJob(), Origin: models.ApplicationSourceExternal}, nil
+}
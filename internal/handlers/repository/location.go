@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"RAAS/internal/geo"
+	"RAAS/internal/models"
+
+	"context"
+	"math"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultPreferredRadiusKm is used for scoring when the seeker has a home
+// location but no radius preference.
+const DefaultPreferredRadiusKm = 50
+
+// LocationProfile bundles what retrieval and scoring need to know about where
+// a seeker wants to work.
+type LocationProfile struct {
+	Home              *geo.Point
+	RadiusKm          int // 0 = no hard limit
+	WillingToRelocate bool
+	WorkMode          string
+}
+
+// GetUserPreferences returns the user's preferences, or zero values if none are stored.
+func GetUserPreferences(ctx context.Context, db *mongo.Database, userID string) (models.UserPreferences, error) {
+	var prefs models.UserPreferences
+	err := db.Collection("preferences").FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&prefs)
+	if err == mongo.ErrNoDocuments {
+		return prefs, nil
+	}
+	return prefs, err
+}
+
+// SeekerHomePoint returns the stored geocode of the seeker's city, resolving
+// it from the gazetteer for profiles saved before geocoding existed.
+func SeekerHomePoint(seeker models.Seeker) *geo.Point {
+	if seeker.PersonalInfo == nil {
+		return nil
+	}
+	if raw, ok := seeker.PersonalInfo["geo_location"]; ok && raw != nil {
+		var p geo.Point
+		if data, err := bson.Marshal(raw); err == nil && bson.Unmarshal(data, &p) == nil && len(p.Coordinates) == 2 {
+			return &p
+		}
+	}
+	city := DereferenceString(GetOptionalField(seeker.PersonalInfo, "city"))
+	country := DereferenceString(GetOptionalField(seeker.PersonalInfo, "country"))
+	if c, ok := geo.LookupCity(city, country); ok {
+		return c.Point()
+	}
+	return nil
+}
+
+// LoadLocationProfile combines the seeker's home city with their location preferences.
+func LoadLocationProfile(ctx context.Context, db *mongo.Database, seeker models.Seeker) LocationProfile {
+	prefs, _ := GetUserPreferences(ctx, db, seeker.AuthUserID)
+	mode := prefs.WorkModePreference
+	if mode == "" {
+		mode = geo.WorkModeAny
+	}
+	return LocationProfile{
+		Home:              SeekerHomePoint(seeker),
+		RadiusKm:          prefs.PreferredRadiusKm,
+		WillingToRelocate: prefs.WillingToRelocate,
+		WorkMode:          mode,
+	}
+}
+
+// BuildLocationFilter returns a jobs filter enforcing the seeker's radius and
+// work mode, or nil when nothing should be filtered. Jobs whose location could
+// not be geocoded are kept, since their distance is unknown.
+func BuildLocationFilter(p LocationProfile) bson.M {
+	var and []bson.M
+
+	if p.WorkMode == geo.WorkModeRemote {
+		and = append(and, bson.M{"work_mode": geo.WorkModeRemote})
+	}
+
+	if p.Home != nil && p.RadiusKm > 0 && !p.WillingToRelocate && p.WorkMode != geo.WorkModeRemote {
+		within := bson.M{"geo_location": bson.M{"$geoWithin": bson.M{
+			"$centerSphere": bson.A{p.Home.Coordinates, geo.RadiusInRadians(float64(p.RadiusKm))},
+		}}}
+		or := bson.A{within, bson.M{"geo_location": bson.M{"$exists": false}}}
+		if p.WorkMode != geo.WorkModeOnsite {
+			or = append(or, bson.M{"work_mode": geo.WorkModeRemote})
+		}
+		and = append(and, bson.M{"$or": or})
+	}
+
+	switch len(and) {
+	case 0:
+		return nil
+	case 1:
+		return and[0]
+	default:
+		return bson.M{"$and": and}
+	}
+}
+
+// LocationScore rates how well a job's location fits the seeker in [0,1].
+// ok is false when there is not enough information to judge.
+func LocationScore(p LocationProfile, job models.Job) (score float64, ok bool) {
+	mode := job.WorkMode
+	if mode == "" {
+		mode = geo.DetectWorkMode(job.Location, job.JobType, job.Title)
+	}
+
+	// Work mode fit
+	modeFit := 1.0
+	switch p.WorkMode {
+	case geo.WorkModeRemote:
+		if mode == geo.WorkModeHybrid {
+			modeFit = 0.6
+		} else if mode == geo.WorkModeOnsite {
+			modeFit = 0.2
+		}
+	case geo.WorkModeOnsite:
+		if mode == geo.WorkModeRemote {
+			modeFit = 0.7
+		}
+	}
+
+	if mode == geo.WorkModeRemote {
+		return modeFit, true
+	}
+	if p.Home == nil || job.GeoLocation == nil {
+		if p.WorkMode == geo.WorkModeAny || p.WorkMode == "" {
+			return 0, false
+		}
+		return modeFit, true
+	}
+
+	radius := float64(p.RadiusKm)
+	if radius <= 0 {
+		radius = DefaultPreferredRadiusKm
+	}
+	distance := geo.DistanceKm(p.Home, job.GeoLocation)
+
+	distanceFit := 1.0
+	if distance > radius {
+		if p.WillingToRelocate {
+			distanceFit = 0.7
+		} else {
+			// Linear decay to 0 at three times the preferred radius
+			distanceFit = math.Max(0, 1-(distance-radius)/(2*radius))
+		}
+	}
+	return distanceFit * modeFit, true
+}
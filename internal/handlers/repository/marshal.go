@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"RAAS/internal/dto"
+	"RAAS/internal/geo"
 	"RAAS/internal/models"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // --- GENERAL MARSHAL/UNMARSHAL HELPERS ---
@@ -90,6 +92,9 @@ func SetPersonalInfo(seeker *models.Seeker, personalInfo *dto.PersonalInfoReques
 		"created_at":        createdAt,
 		"updated_at":        time.Now(),
 	}
+	if city, ok := geo.LookupCity(DereferenceString(personalInfo.City), DereferenceString(personalInfo.Country)); ok {
+		personalInfoBson["geo_location"] = city.Point()
+	}
 
 	seeker.PersonalInfo = personalInfoBson
 
@@ -102,44 +107,27 @@ func SetPersonalInfo(seeker *models.Seeker, personalInfo *dto.PersonalInfoReques
 // WORK EXPERIENCE
 // =======================
 
-func GetWorkExperience(seeker *models.Seeker) ([]bson.M, error) {
-    if len(seeker.WorkExperiences) == 0 {
-        return []bson.M{}, nil
-    }
-    return seeker.WorkExperiences, nil
-}
-
-// SetWorkExperience sets the work experiences for a Seeker using an array of bson.M.
-func SetWorkExperience(seeker *models.Seeker, workExperiences []bson.M) error {
-    seeker.WorkExperiences = workExperiences
-    return nil
-}
-
-
-func AppendToWorkExperience(seeker *models.Seeker, newWorkExperience dto.WorkExperienceRequest) error {
-    // Check if the WorkExperiences array is nil or empty, if so, initialize it
-    if seeker.WorkExperiences == nil {
-        seeker.WorkExperiences = []bson.M{}
-    }
-
-    // Append the new work experience as a bson.M document
-	workExperienceBson := bson.M{
-		"job_title":            newWorkExperience.JobTitle,
-		"company_name":         newWorkExperience.CompanyName,
-		"location":             newWorkExperience.Location,           // ✅ optional field
-		"start_date":           newWorkExperience.StartDate,
-		"end_date":             newWorkExperience.EndDate,            // ✅ optional
-		"key_responsibilities": newWorkExperience.KeyResponsibilities,
-		"created_at":           time.Now(),
-		"updated_at":           time.Now(),
-		
+func GetWorkExperience(seeker *models.Seeker) ([]models.WorkExperience, error) {
+	if len(seeker.WorkExperiences) == 0 {
+		return []models.WorkExperience{}, nil
+	}
+	return seeker.WorkExperiences, nil
+}
+
+// NewWorkExperience builds a work experience entry with a fresh id.
+func NewWorkExperience(req dto.WorkExperienceRequest) models.WorkExperience {
+	now := time.Now()
+	return models.WorkExperience{
+		ID:                  primitive.NewObjectID(),
+		JobTitle:            req.JobTitle,
+		CompanyName:         req.CompanyName,
+		Location:            req.Location,
+		StartDate:           req.StartDate,
+		EndDate:             req.EndDate,
+		KeyResponsibilities: req.KeyResponsibilities,
+		CreatedAt:           now,
+		UpdatedAt:           now,
 	}
-
-
-    // Append the work experience to the array
-    seeker.WorkExperiences = append(seeker.WorkExperiences, workExperienceBson)
-
-    return nil
 }
 
 // =======================
@@ -147,166 +135,103 @@ func AppendToWorkExperience(seeker *models.Seeker, newWorkExperience dto.WorkExp
 // =======================
 
 // GetAcademics retrieves the education information of the seeker
-func GetAcademics(seeker *models.Seeker) ([]bson.M, error) {
-    if len(seeker.Academics) == 0 {
-        return []bson.M{}, nil
-    }
-    return seeker.Academics, nil
-}
-
-// SetAcademicssets the education information for a Seeker using an array of bson.M.
-func SetAcademics(seeker *models.Seeker, academics []bson.M) error {
-    seeker.Academics = academics
-    return nil
-}
-
-// AppendToAcademicsadds a new education entry to the Seeker's education list
-func AppendToAcademics(seeker *models.Seeker, newAcademics dto.AcademicsRequest) error {
-    // Check if the Educations array is nil or empty, if so, initialize it
-    if seeker.Academics == nil {
-        seeker.Academics = []bson.M{}
-    }
-
-    // Create a new education entry as a bson.M document
-    academicsBson := bson.M{
-		"institution":   	newAcademics.Institution,
-        "degree":        	newAcademics.Degree,
-		"city" : 			newAcademics.City,
-        "field_of_study": 	newAcademics.FieldOfStudy,
-        "start_date":    	newAcademics.StartDate,
-        "end_date":      	newAcademics.EndDate,
-        "achievements":  	newAcademics.Description,
-		"created_at":           time.Now(),
-		"updated_at":           time.Now(),
-    }
-
-    // Append the new education entry to the Educations array
-    seeker.Academics= append(seeker.Academics, academicsBson)
-
-    return nil
+func GetAcademics(seeker *models.Seeker) ([]models.Academics, error) {
+	if len(seeker.Academics) == 0 {
+		return []models.Academics{}, nil
+	}
+	return seeker.Academics, nil
+}
+
+// NewAcademics builds an education entry with a fresh id.
+func NewAcademics(req dto.AcademicsRequest) models.Academics {
+	now := time.Now()
+	return models.Academics{
+		ID:           primitive.NewObjectID(),
+		Institution:  req.Institution,
+		City:         req.City,
+		Degree:       req.Degree,
+		FieldOfStudy: req.FieldOfStudy,
+		StartDate:    req.StartDate,
+		EndDate:      req.EndDate,
+		Achievements: req.Description,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
 }
 
-
 // =======================
 // PAST PROJECT
 // =======================
 
 // GetPastProjects retrieves the past projects of the seeker
-func GetPastProjects(seeker *models.Seeker) ([]bson.M, error) {
-    if len(seeker.PastProjects) == 0 {
-        return []bson.M{}, nil
-    }
-    return seeker.PastProjects, nil
-}
-
-// SetPastProjects sets the past projects for a Seeker using an array of bson.M.
-func SetPastProjects(seeker *models.Seeker, projects []bson.M) error {
-    seeker.PastProjects = projects
-    return nil
-}
-
-// AppendToPastProjects adds a new past project entry to the Seeker's project list
-func AppendToPastProjects(seeker *models.Seeker, newProject dto.PastProjectRequest) error {
-    // Check if the PastProjects array is nil or empty, if so, initialize it
-    if seeker.PastProjects == nil {
-        seeker.PastProjects = []bson.M{}
-    }
-
-    // Create a new project entry as a bson.M document
-    projectBson := bson.M{
-        "project_name":        newProject.ProjectName,
-        "institution":         newProject.Institution,
-        "start_date":          newProject.StartDate,
-        "end_date":            newProject.EndDate,
-        "project_description": newProject.ProjectDescription,
-		"created_at":           time.Now(),
-		"updated_at":           time.Now(),
-		
-    }
-
-    // Append the new project entry
-    seeker.PastProjects = append(seeker.PastProjects, projectBson)
-
-    return nil
+func GetPastProjects(seeker *models.Seeker) ([]models.PastProject, error) {
+	if len(seeker.PastProjects) == 0 {
+		return []models.PastProject{}, nil
+	}
+	return seeker.PastProjects, nil
+}
+
+// NewPastProject builds a past project entry with a fresh id.
+func NewPastProject(req dto.PastProjectRequest) models.PastProject {
+	now := time.Now()
+	return models.PastProject{
+		ID:                 primitive.NewObjectID(),
+		ProjectName:        req.ProjectName,
+		Institution:        req.Institution,
+		StartDate:          req.StartDate,
+		EndDate:            req.EndDate,
+		ProjectDescription: req.ProjectDescription,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
 }
 
 // =======================
 // LANGUAGES
 // =======================
 
-
 // GetLanguages retrieves the language information of the seeker
-func GetLanguages(seeker *models.Seeker) ([]bson.M, error) {
-    if len(seeker.Languages) == 0 {
-        return []bson.M{}, nil
-    }
-    return seeker.Languages, nil
-}
-
-// SetLanguages sets the language information for a Seeker using an array of bson.M
-func SetLanguages(seeker *models.Seeker, languages []bson.M) error {
-    seeker.Languages = languages
-    return nil
-}
-// AppendToLanguages adds a new language entry to the Seeker's languages list
-func AppendToLanguages(seeker *models.Seeker, newLanguage dto.LanguageRequest, languageFile string) error {
-    // Check if the Languages array is nil or empty, if so, initialize it
-    if seeker.Languages == nil {
-        seeker.Languages = []bson.M{}
-    }
-
-    // Create a new language entry as a bson.M document
-    languageBson := bson.M{
-        "language":         newLanguage.LanguageName,
-        "proficiency":      newLanguage.ProficiencyLevel,
-		"created_at":           time.Now(),
-		"updated_at":           time.Now(),
-    }
-
-    // Append the new language entry to the Languages array
-    seeker.Languages = append(seeker.Languages, languageBson)
-
-    return nil
+func GetLanguages(seeker *models.Seeker) ([]models.Language, error) {
+	if len(seeker.Languages) == 0 {
+		return []models.Language{}, nil
+	}
+	return seeker.Languages, nil
+}
+
+// NewLanguage builds a language entry with a fresh id.
+func NewLanguage(req dto.LanguageRequest) models.Language {
+	now := time.Now()
+	return models.Language{
+		ID:               primitive.NewObjectID(),
+		LanguageName:     req.LanguageName,
+		ProficiencyLevel: req.ProficiencyLevel,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
 }
 
-
 // =======================
 // CERTIFICATE
 // =======================
 
 // GetCertificates retrieves the certificate information of the seeker
-func GetCertificates(seeker *models.Seeker) ([]bson.M, error) {
+func GetCertificates(seeker *models.Seeker) ([]models.Certificate, error) {
 	if len(seeker.Certificates) == 0 {
-		return []bson.M{}, nil
+		return []models.Certificate{}, nil
 	}
 	return seeker.Certificates, nil
 }
 
-// SetCertificates sets the certificate information for a Seeker using an array of bson.M
-func SetCertificates(seeker *models.Seeker, certificates []bson.M) error {
-	seeker.Certificates = certificates
-	return nil
-}
-// AppendToCertificates adds a new certificate entry to the Seeker's certificates list
-func AppendToCertificates(seeker *models.Seeker, newCertificate dto.CertificateRequest) error {
-	// Initialize if nil
-	if seeker.Certificates == nil {
-		seeker.Certificates = []bson.M{}
+// NewCertificate builds a certificate entry with a fresh id.
+func NewCertificate(req dto.CertificateRequest) models.Certificate {
+	now := time.Now()
+	return models.Certificate{
+		ID:              primitive.NewObjectID(),
+		CertificateName: req.CertificateName,
+		CertificateType: req.CertificateType,
+		Provider:        req.Provider,
+		CompletionDate:  req.CompletionDate,
+		CreatedAt:       now,
+		UpdatedAt:       now,
 	}
-
-	// Create a new certificate entry
-certificateBson := bson.M{
-    "certificate_name":  newCertificate.CertificateName,
-    "certificate_type":  newCertificate.CertificateType,
-    "provider":          newCertificate.Provider,
-    "completion_date":   newCertificate.CompletionDate,
-    "created_at":        time.Now(),
-    "updated_at":        time.Now(),
 }
-
-
-	seeker.Certificates = append(seeker.Certificates, certificateBson)
-	return nil
-}
-
-
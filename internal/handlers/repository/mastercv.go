@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"RAAS/internal/mlclient"
+	"RAAS/internal/models"
+
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrMasterCVNotFound is returned when the seeker has no master CV in a language.
+	ErrMasterCVNotFound = errors.New("master CV not found")
+	// ErrSectionNotFound is returned when a master CV has no such section.
+	ErrSectionNotFound = errors.New("master CV section not found")
+)
+
+// wholeProfile is the source of sections not tied to one part of the profile.
+const wholeProfile = "*"
+
+// sectionSources names the profile data each CV section is generated from.
+// Keys are compared lowercased without '_', '-' and spaces.
+var sectionSources = map[string]string{
+	"experience": "work_experiences", "workexperience": "work_experiences", "workexperiences": "work_experiences",
+	"experiences": "work_experiences", "experiencesummary": "work_experiences", "employment": "work_experiences",
+	"professionalexperience": "work_experiences",
+	"education": "academics", "academics": "academics", "qualifications": "academics",
+	"projects": "past_projects", "pastprojects": "past_projects",
+	"certifications": "certificates", "certificates": "certificates",
+	"languages": "languages",
+	"skills": "key_skills", "keyskills": "key_skills", "technicalskills": "key_skills",
+	"name": "personal_info", "email": "personal_info", "contact": "personal_info", "address": "personal_info",
+	"linkedin": "personal_info", "portfolio": "personal_info", "designation": "personal_info",
+}
+
+func sectionSource(key string) string {
+	norm := strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(key))
+	if src, ok := sectionSources[norm]; ok {
+		return src
+	}
+	return wholeProfile
+}
+
+// MasterCVLanguage resolves a language name or code, or returns "" when a
+// master CV cannot be generated in it.
+func MasterCVLanguage(language string) string {
+	return models.MasterCVLanguages[strings.ToLower(strings.TrimSpace(language))]
+}
+
+func hashSource(v interface{}) string {
+	raw, _ := json.Marshal(v)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:12])
+}
+
+// MasterCVSources hashes the profile data behind each kind of CV section.
+func MasterCVSources(seeker *models.Seeker) map[string]string {
+	parts := map[string]interface{}{
+		"work_experiences": seeker.WorkExperiences,
+		"academics":        seeker.Academics,
+		"past_projects":    seeker.PastProjects,
+		"certificates":     seeker.Certificates,
+		"languages":        seeker.Languages,
+		"key_skills":       seeker.KeySkills,
+		"personal_info":    []interface{}{seeker.PersonalInfo, seeker.PrimaryTitle},
+	}
+	sources := map[string]string{wholeProfile: hashSource(parts)}
+	for name, v := range parts {
+		sources[name] = hashSource(v)
+	}
+	return sources
+}
+
+// MasterCVUpToDate reports whether every section of a master CV was
+// generated from the current profile data.
+func MasterCVUpToDate(cv models.MasterCV, sources map[string]string) bool {
+	if len(cv.Sections) == 0 || len(sources) == 0 {
+		return false
+	}
+	for key, s := range cv.Sections {
+		if s.SourceHash != sources[sectionSource(key)] {
+			return false
+		}
+	}
+	return true
+}
+
+func GetMasterCV(ctx context.Context, db *mongo.Database, userID, language string) (models.MasterCV, error) {
+	var cv models.MasterCV
+	err := db.Collection(models.CollectionMasterCVs).FindOne(ctx, bson.M{"auth_user_id": userID, "language": language}).Decode(&cv)
+	if err == mongo.ErrNoDocuments {
+		return cv, ErrMasterCVNotFound
+	}
+	return cv, err
+}
+
+func ListMasterCVs(ctx context.Context, db *mongo.Database, userID string) ([]models.MasterCV, error) {
+	cursor, err := db.Collection(models.CollectionMasterCVs).Find(ctx, bson.M{"auth_user_id": userID},
+		options.Find().SetSort(bson.D{{Key: "language", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	cvs := []models.MasterCV{}
+	err = cursor.All(ctx, &cvs)
+	return cvs, err
+}
+
+// MergeMasterCV stores a generated master CV against the translation memory
+// of the stored one. A section whose profile source is unchanged keeps the
+// seeker's edit, and without force also its earlier wording; other sections
+// take the generated content. A response wrapped as {"cv_data": {...}} is
+// unwrapped, and keys that cannot be a section path are left out. It returns
+// the stored CV and the sections whose edits were kept.
+func MergeMasterCV(ctx context.Context, db *mongo.Database, userID, language, format string, generated map[string]interface{}, sources map[string]string, force bool) (models.MasterCV, []string, error) {
+	existing, err := GetMasterCV(ctx, db, userID, language)
+	if err != nil && err != ErrMasterCVNotFound {
+		return existing, nil, err
+	}
+
+	now := time.Now()
+	generated = mlclient.Unwrap(generated)
+	sections := make(map[string]models.MasterCVSection, len(generated))
+	kept := []string{}
+	for key, content := range generated {
+		if !validSectionKey(key) {
+			continue
+		}
+		hash := sources[sectionSource(key)]
+		if old, ok := existing.Sections[key]; ok && old.SourceHash == hash && (old.Edited || !force) {
+			sections[key] = old
+			if old.Edited {
+				kept = append(kept, key)
+			}
+			continue
+		}
+		sections[key] = models.MasterCVSection{Content: content, SourceHash: hash, UpdatedAt: now}
+	}
+	// Edited sections the generator left out stay while their source is unchanged
+	for key, old := range existing.Sections {
+		if _, ok := sections[key]; !ok && old.Edited && old.SourceHash == sources[sectionSource(key)] {
+			sections[key] = old
+			kept = append(kept, key)
+		}
+	}
+	sort.Strings(kept)
+
+	if format == "" {
+		format = existing.Format
+	}
+	var cv models.MasterCV
+	err = db.Collection(models.CollectionMasterCVs).FindOneAndUpdate(ctx,
+		bson.M{"auth_user_id": userID, "language": language},
+		bson.M{
+			"$set":         bson.M{"sections": sections, "cv_format": format, "generated_at": now, "updated_at": now},
+			"$setOnInsert": bson.M{"auth_user_id": userID, "language": language},
+		},
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&cv)
+	return cv, kept, err
+}
+
+// validSectionKey reports whether key can be used as a section's field path.
+func validSectionKey(key string) bool {
+	return key != "" && !strings.ContainsAny(key, ".$")
+}
+
+// UpdateMasterCVSection saves the seeker's edit of a section. The edit is
+// kept by later regenerations until the profile data behind it changes.
+func UpdateMasterCVSection(ctx context.Context, db *mongo.Database, userID, language, key string, content interface{}) (models.MasterCV, error) {
+	var cv models.MasterCV
+	if !validSectionKey(key) {
+		return cv, ErrSectionNotFound
+	}
+	field := "sections." + key
+	err := db.Collection(models.CollectionMasterCVs).FindOneAndUpdate(ctx,
+		bson.M{"auth_user_id": userID, "language": language, field: bson.M{"$exists": true}},
+		bson.M{"$set": bson.M{
+			field + ".content":    content,
+			field + ".edited":     true,
+			field + ".updated_at": time.Now(),
+			"updated_at":          time.Now(),
+		}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&cv)
+	if err == mongo.ErrNoDocuments {
+		if _, getErr := GetMasterCV(ctx, db, userID, language); getErr != nil {
+			return cv, getErr
+		}
+		return cv, ErrSectionNotFound
+	}
+	return cv, err
+}
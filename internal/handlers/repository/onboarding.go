@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OnboardingStepStatus is a flow step together with the user's progress on it.
+type OnboardingStepStatus struct {
+	models.OnboardingStep
+	Completed bool `json:"completed"`
+}
+
+// OnboardingProgress is a user's position in their onboarding flow.
+type OnboardingProgress struct {
+	Flow      string                 `json:"flow"`
+	Completed bool                   `json:"completed"`
+	NextStep  string                 `json:"next_step"`
+	Steps     []OnboardingStepStatus `json:"steps"`
+}
+
+// LoadOnboardingFlow returns the active flow with the given key, falling back
+// to the default flow stored in the database and then to the built-in one.
+func LoadOnboardingFlow(ctx context.Context, db *mongo.Database, key string) models.OnboardingFlow {
+	coll := db.Collection(models.CollectionOnboardingFlows)
+	for _, k := range []string{key, models.DefaultOnboardingFlowKey} {
+		if k == "" {
+			continue
+		}
+		var flow models.OnboardingFlow
+		if err := coll.FindOne(ctx, bson.M{"key": k, "is_active": true}).Decode(&flow); err == nil && len(flow.Steps) > 0 {
+			return flow
+		}
+	}
+	return models.DefaultOnboardingFlow()
+}
+
+// AssignOnboardingFlow picks the flow for a new user: an experiment whose
+// rollout bucket includes the user, else the flow for their tier, else the
+// default flow.
+func AssignOnboardingFlow(ctx context.Context, db *mongo.Database, userID, tier string) models.OnboardingFlow {
+	cursor, err := db.Collection(models.CollectionOnboardingFlows).Find(ctx, bson.M{
+		"is_active": true,
+		"tier":      bson.M{"$in": bson.A{nil, "", tier}},
+	}, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		log.Printf("⚠️ Failed to load onboarding flows: %v", err)
+		return LoadOnboardingFlow(ctx, db, "")
+	}
+	var flows []models.OnboardingFlow
+	if err := cursor.All(ctx, &flows); err != nil {
+		log.Printf("⚠️ Failed to decode onboarding flows: %v", err)
+		return LoadOnboardingFlow(ctx, db, "")
+	}
+
+	var tierFlow *models.OnboardingFlow
+	for i, f := range flows {
+		if len(f.Steps) == 0 {
+			continue
+		}
+		if f.Experiment != "" {
+			if (f.Tier == "" || f.Tier == tier) && rolloutBucket(userID, f.Experiment) < f.RolloutPercent {
+				return f
+			}
+			continue
+		}
+		if tier != "" && f.Tier == tier && tierFlow == nil {
+			tierFlow = &flows[i]
+		}
+	}
+	if tierFlow != nil {
+		return *tierFlow
+	}
+	return LoadOnboardingFlow(ctx, db, "")
+}
+
+// rolloutBucket places a user in one of 100 stable buckets per experiment.
+func rolloutBucket(userID, experiment string) int {
+	h := fnv.New32a()
+	h.Write([]byte(experiment + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// UserOnboardingFlow returns the flow the user was assigned at sign-up.
+func UserOnboardingFlow(ctx context.Context, db *mongo.Database, userID string) (models.OnboardingFlow, error) {
+	var t models.UserEntryTimeline
+	if err := db.Collection(models.CollectionUserEntryTimelines).FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&t); err != nil {
+		return models.OnboardingFlow{}, err
+	}
+	return LoadOnboardingFlow(ctx, db, t.Flow), nil
+}
+
+// CreateUserEntryTimeline assigns a new user their onboarding flow.
+func CreateUserEntryTimeline(ctx context.Context, db *mongo.Database, userID, tier string) error {
+	flow := AssignOnboardingFlow(ctx, db, userID, tier)
+	now := time.Now()
+	_, err := db.Collection(models.CollectionUserEntryTimelines).InsertOne(ctx, models.UserEntryTimeline{
+		AuthUserID:     userID,
+		Flow:           flow.Key,
+		CompletedSteps: []string{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	})
+	return err
+}
+
+// CompleteOnboardingStep marks a step of the user's flow as completed (an
+// empty step only re-evaluates progress) and, once every required step is
+// done, marks the timeline completed. It returns whether onboarding is
+// complete and the next required step.
+func CompleteOnboardingStep(ctx context.Context, db *mongo.Database, userID, step string) (bool, string, error) {
+	progress, err := UpdateOnboardingProgress(ctx, db, userID, step)
+	if err != nil {
+		return false, "", err
+	}
+	return progress.Completed, progress.NextStep, nil
+}
+
+// UpdateOnboardingProgress is CompleteOnboardingStep returning the full progress.
+func UpdateOnboardingProgress(ctx context.Context, db *mongo.Database, userID, step string) (OnboardingProgress, error) {
+	timelines := db.Collection(models.CollectionUserEntryTimelines)
+
+	var t models.UserEntryTimeline
+	if err := timelines.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&t); err != nil {
+		return OnboardingProgress{}, fmt.Errorf("timeline lookup failed: %w", err)
+	}
+	flow := LoadOnboardingFlow(ctx, db, t.Flow)
+
+	// Data-entry handlers report their step regardless of flow; steps the
+	// flow does not contain are ignored
+	if _, ok := flow.Step(step); ok {
+		if err := timelines.FindOneAndUpdate(ctx,
+			bson.M{"auth_user_id": userID},
+			bson.M{
+				"$addToSet": bson.M{"completed_steps": step},
+				"$set":      bson.M{"updated_at": time.Now()},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&t); err != nil {
+			return OnboardingProgress{}, fmt.Errorf("timeline update failed: %w", err)
+		}
+	}
+
+	next := flow.NextStep(t.CompletedSteps)
+	if next == "" && !t.Completed {
+		now := time.Now()
+		if _, err := timelines.UpdateOne(ctx,
+			bson.M{"auth_user_id": userID},
+			bson.M{"$set": bson.M{"completed": true, "completed_at": now, "updated_at": now}},
+		); err != nil {
+			return OnboardingProgress{}, fmt.Errorf("timeline completion failed: %w", err)
+		}
+		t.Completed = true
+	}
+	if next != "" {
+		log.Printf("🟥 Step incomplete for user %s: %s", userID, next)
+	}
+
+	done := make(map[string]bool, len(t.CompletedSteps))
+	for _, k := range t.CompletedSteps {
+		done[k] = true
+	}
+	steps := make([]OnboardingStepStatus, 0, len(flow.Steps))
+	for _, s := range flow.Steps {
+		steps = append(steps, OnboardingStepStatus{OnboardingStep: s, Completed: done[s.Key]})
+	}
+
+	// Completion is sticky: steps added to a flow later do not lock users out
+	return OnboardingProgress{
+		Flow:      flow.Key,
+		Completed: t.Completed,
+		NextStep:  next,
+		Steps:     steps,
+	}, nil
+}
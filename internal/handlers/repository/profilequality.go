@@ -0,0 +1,369 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+	"RAAS/internal/skills"
+
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Profile suggestion types
+const (
+	SuggestionMissingField        = "missing_field"
+	SuggestionResponsibilities    = "add_responsibilities"
+	SuggestionEmploymentGap       = "explain_employment_gap"
+	SuggestionMissingSkill        = "add_skill"
+	SuggestionOutdatedCertificate = "refresh_certificate"
+)
+
+const (
+	// Matched jobs sampled for skill suggestions
+	qualitySkillSampleJobs = 100
+	// A skill is suggested when at least this share of matched jobs asks for it
+	qualitySkillMinShare       = 0.1
+	qualityMaxSkillSuggestions = 5
+	// Responsibilities shorter than this are treated as missing
+	qualityMinResponsibilityChars = 40
+	// Gaps between roles longer than this many months are reported
+	qualityGapMonths = 3
+	// Certificates completed longer ago than this many years are outdated
+	qualityCertificateMaxAgeYears = 5
+
+	// Match score range, see jobs.CalculateMatchScore: 60 + skillScore*40
+	matchScoreSkillSpan = 40.0
+)
+
+// Estimated match-score impact of suggestions that do not change the keyword
+// score directly; they improve the generated CV that employers see.
+var qualityHeuristicImpact = map[string]float64{
+	SuggestionResponsibilities:    3,
+	SuggestionEmploymentGap:       2,
+	SuggestionOutdatedCertificate: 1,
+}
+
+// Impact of fields scored by CalculateJobProfileCompletion that block matching.
+var qualityMissingFieldImpact = map[string]float64{
+	"key_skills":                        20,
+	"primary_title":                     15,
+	"work_experiences or past_projects": 10,
+	"first_name":                        5,
+	"academics":                         5,
+	"languages":                         5,
+}
+
+// ProfileSuggestion is one actionable improvement to a seeker profile.
+type ProfileSuggestion struct {
+	Type            string   `json:"type"`
+	Message         string   `json:"message"`
+	Field           string   `json:"field"`
+	Items           []string `json:"items,omitempty"`
+	EstimatedImpact float64  `json:"estimated_impact"` // match score points, 0-40
+}
+
+// ProfileQuality is the result of analysing a seeker profile.
+type ProfileQuality struct {
+	Completion    int                 `json:"completion"`
+	Missing       []string            `json:"missing"`
+	PotentialGain float64             `json:"potential_gain"`
+	MatchedJobs   int                 `json:"matched_jobs_analysed"`
+	Suggestions   []ProfileSuggestion `json:"suggestions"`
+}
+
+// AnalyzeProfileQuality checks the seeker's profile for weaknesses and returns
+// suggestions ranked by their estimated match-score impact.
+func AnalyzeProfileQuality(ctx context.Context, db *mongo.Database, seeker models.Seeker) (ProfileQuality, error) {
+	completion, missing := CalculateJobProfileCompletion(seeker)
+	quality := ProfileQuality{Completion: completion, Missing: missing, Suggestions: []ProfileSuggestion{}}
+	if quality.Missing == nil {
+		quality.Missing = []string{}
+	}
+
+	for _, field := range missing {
+		quality.Suggestions = append(quality.Suggestions, ProfileSuggestion{
+			Type:            SuggestionMissingField,
+			Message:         fmt.Sprintf("Add your %s to start receiving better matches.", strings.ReplaceAll(field, "_", " ")),
+			Field:           field,
+			EstimatedImpact: qualityMissingFieldImpact[field],
+		})
+	}
+
+	quality.Suggestions = append(quality.Suggestions, responsibilitySuggestions(seeker)...)
+	quality.Suggestions = append(quality.Suggestions, employmentGapSuggestions(seeker, time.Now())...)
+	quality.Suggestions = append(quality.Suggestions, certificateSuggestions(seeker, time.Now())...)
+
+	skills, analysed, err := missingSkillSuggestions(ctx, db, seeker)
+	if err != nil {
+		return quality, err
+	}
+	quality.MatchedJobs = analysed
+	quality.Suggestions = append(quality.Suggestions, skills...)
+
+	sort.SliceStable(quality.Suggestions, func(i, j int) bool {
+		return quality.Suggestions[i].EstimatedImpact > quality.Suggestions[j].EstimatedImpact
+	})
+	for _, s := range quality.Suggestions {
+		quality.PotentialGain += s.EstimatedImpact
+	}
+	quality.PotentialGain = math.Min(roundImpact(quality.PotentialGain), matchScoreSkillSpan)
+
+	return quality, nil
+}
+
+// responsibilitySuggestions lists roles without a meaningful description.
+func responsibilitySuggestions(seeker models.Seeker) []ProfileSuggestion {
+	var roles []string
+	for _, we := range seeker.WorkExperiences {
+		if len(strings.TrimSpace(DereferenceString(we.KeyResponsibilities))) < qualityMinResponsibilityChars {
+			roles = append(roles, roleLabel(we))
+		}
+	}
+	if len(roles) == 0 {
+		return nil
+	}
+	return []ProfileSuggestion{{
+		Type:            SuggestionResponsibilities,
+		Message:         "Describe your responsibilities and achievements for these roles.",
+		Field:           "work_experiences",
+		Items:           roles,
+		EstimatedImpact: math.Min(qualityHeuristicImpact[SuggestionResponsibilities]*float64(len(roles)), 3*qualityHeuristicImpact[SuggestionResponsibilities]),
+	}}
+}
+
+type employmentPeriod struct {
+	start, end time.Time
+}
+
+// employmentGapSuggestions reports gaps between roles, and since the last role
+// when none is current.
+func employmentGapSuggestions(seeker models.Seeker, now time.Time) []ProfileSuggestion {
+	var periods []employmentPeriod
+	for _, we := range seeker.WorkExperiences {
+		if we.StartDate.IsZero() {
+			continue
+		}
+		end := now
+		if we.EndDate != nil && !we.EndDate.IsZero() {
+			end = *we.EndDate
+		}
+		periods = append(periods, employmentPeriod{start: we.StartDate, end: end})
+	}
+	if len(periods) == 0 {
+		return nil
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].start.Before(periods[j].start) })
+
+	var gaps []string
+	coveredUntil := periods[0].end
+	for _, p := range periods[1:] {
+		if gapMonths(coveredUntil, p.start) > qualityGapMonths {
+			gaps = append(gaps, fmt.Sprintf("%s – %s", coveredUntil.Format("Jan 2006"), p.start.Format("Jan 2006")))
+		}
+		if p.end.After(coveredUntil) {
+			coveredUntil = p.end
+		}
+	}
+	if gapMonths(coveredUntil, now) > qualityGapMonths {
+		gaps = append(gaps, fmt.Sprintf("since %s", coveredUntil.Format("Jan 2006")))
+	}
+	if len(gaps) == 0 {
+		return nil
+	}
+	return []ProfileSuggestion{{
+		Type:            SuggestionEmploymentGap,
+		Message:         "Explain these gaps, e.g. with studies, projects or freelance work.",
+		Field:           "work_experiences",
+		Items:           gaps,
+		EstimatedImpact: qualityHeuristicImpact[SuggestionEmploymentGap],
+	}}
+}
+
+// gapMonths returns the whole months between from and to, using CalculateWorkExperience.
+func gapMonths(from, to time.Time) int {
+	years, months, _ := CalculateWorkExperience(from, to)
+	return years*12 + months
+}
+
+// certificateSuggestions lists certificates completed too long ago.
+func certificateSuggestions(seeker models.Seeker, now time.Time) []ProfileSuggestion {
+	cutoff := now.AddDate(-qualityCertificateMaxAgeYears, 0, 0)
+	var outdated []string
+	for _, cert := range seeker.Certificates {
+		if cert.CompletionDate.IsZero() || cert.CompletionDate.After(cutoff) {
+			continue
+		}
+		outdated = append(outdated, fmt.Sprintf("%s (%d)", cert.CertificateName, cert.CompletionDate.Year()))
+	}
+	if len(outdated) == 0 {
+		return nil
+	}
+	return []ProfileSuggestion{{
+		Type:            SuggestionOutdatedCertificate,
+		Message:         fmt.Sprintf("These certificates are over %d years old; renew them or add recent training.", qualityCertificateMaxAgeYears),
+		Field:           "certificates",
+		Items:           outdated,
+		EstimatedImpact: math.Min(qualityHeuristicImpact[SuggestionOutdatedCertificate]*float64(len(outdated)), 3),
+	}}
+}
+
+// missingSkillSuggestions finds skills that the seeker's best matched jobs ask
+// for but the seeker does not list. The impact is the average match-score
+// gain over those jobs, using the same keyword scoring as the matcher.
+func missingSkillSuggestions(ctx context.Context, db *mongo.Database, seeker models.Seeker) ([]ProfileSuggestion, int, error) {
+	cursor, err := db.Collection(models.CollectionMatchScores).Find(ctx,
+		bson.M{"auth_user_id": seeker.AuthUserID},
+		options.Find().
+			SetSort(bson.D{{Key: "match_score", Value: -1}}).
+			SetLimit(qualitySkillSampleJobs).
+			SetProjection(bson.M{"job_id": 1}),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	var scores []models.MatchScore
+	if err := cursor.All(ctx, &scores); err != nil {
+		return nil, 0, err
+	}
+	if len(scores) == 0 {
+		return nil, 0, nil
+	}
+
+	jobIDs := make([]string, 0, len(scores))
+	for _, s := range scores {
+		jobIDs = append(jobIDs, s.JobID)
+	}
+	jobCursor, err := db.Collection(models.CollectionJobs).Find(ctx,
+		bson.M{"job_id": bson.M{"$in": jobIDs}},
+		options.Find().SetProjection(bson.M{"job_id": 1, "title": 1, "job_description": 1, "skills": 1}),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+	var jobs []models.Job
+	if err := jobCursor.All(ctx, &jobs); err != nil {
+		return nil, 0, err
+	}
+	if len(jobs) == 0 {
+		return nil, 0, nil
+	}
+
+	taxonomy := skills.Default()
+	userSkills := taxonomy.Canonicalize(seeker.KeySkills)
+	have := make(map[string]bool, len(userSkills))
+	for _, id := range taxonomy.IDs(userSkills) {
+		have["id:"+id] = true
+	}
+	for _, s := range userSkills {
+		have[skills.Normalize(s)] = true
+	}
+
+	// Count how many jobs list each skill the seeker lacks. Listed skills are
+	// keyed by canonical ID when known, so "JS" and "JavaScript" count once
+	counts := map[string]int{}
+	display := map[string]string{}
+	docs := make([]*skills.Document, 0, len(jobs))
+	for _, job := range jobs {
+		docs = append(docs, taxonomy.NewDocument(job.Title+" "+job.JobDescription+" "+job.Skills))
+		seen := map[string]bool{}
+		for _, raw := range strings.Split(job.Skills, ",") {
+			skill := strings.TrimSpace(raw)
+			key := skills.Normalize(skill)
+			if known, ok := taxonomy.Lookup(skill); ok {
+				skill, key = known.Name, "id:"+known.ID
+			}
+			if key == "" || have[key] || seen[key] {
+				continue
+			}
+			seen[key] = true
+			counts[key]++
+			if _, ok := display[key]; !ok {
+				display[key] = skill
+			}
+		}
+	}
+
+	minJobs := int(math.Ceil(qualitySkillMinShare * float64(len(jobs))))
+	type candidate struct {
+		key    string
+		count  int
+		impact float64
+	}
+	var candidates []candidate
+	for key, n := range counts {
+		if n < minJobs {
+			continue
+		}
+		impact := skillImpact(userSkills, display[key], docs)
+		if impact <= 0 {
+			continue
+		}
+		candidates = append(candidates, candidate{key: key, count: n, impact: impact})
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].impact != candidates[j].impact {
+			return candidates[i].impact > candidates[j].impact
+		}
+		return candidates[i].key < candidates[j].key
+	})
+	if len(candidates) > qualityMaxSkillSuggestions {
+		candidates = candidates[:qualityMaxSkillSuggestions]
+	}
+
+	suggestions := make([]ProfileSuggestion, 0, len(candidates))
+	for _, cand := range candidates {
+		suggestions = append(suggestions, ProfileSuggestion{
+			Type:            SuggestionMissingSkill,
+			Message:         fmt.Sprintf("%d of your %d best matched jobs ask for %s. Add it if you have it.", cand.count, len(jobs), display[cand.key]),
+			Field:           "key_skills",
+			Items:           []string{display[cand.key]},
+			EstimatedImpact: cand.impact,
+		})
+	}
+	return suggestions, len(jobs), nil
+}
+
+// skillImpact estimates the average match-score change from adding skill,
+// mirroring the matcher's share-of-skills-found keyword score.
+func skillImpact(userSkills []string, skill string, docs []*skills.Document) float64 {
+	n := float64(len(userSkills))
+	total := 0.0
+	for _, doc := range docs {
+		found := 0.0
+		for _, s := range userSkills {
+			if doc.Has(s) {
+				found++
+			}
+		}
+		before := 0.0
+		if n > 0 {
+			before = found / n
+		}
+		if doc.Has(skill) {
+			found++
+		}
+		after := found / (n + 1)
+		total += (after - before) * matchScoreSkillSpan
+	}
+	return roundImpact(total / float64(len(docs)))
+}
+
+func roundImpact(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// roleLabel describes a work experience entry as "title at company".
+func roleLabel(we models.WorkExperience) string {
+	if we.CompanyName == "" {
+		return we.JobTitle
+	}
+	return fmt.Sprintf("%s at %s", we.JobTitle, we.CompanyName)
+}
+
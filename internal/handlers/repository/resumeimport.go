@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/models"
+	"RAAS/internal/skills"
+
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrResumeImportNotFound is returned when the user has no such import.
+	ErrResumeImportNotFound = errors.New("resume import not found")
+	// ErrResumeImportApplied is returned when the draft was already applied.
+	ErrResumeImportApplied = errors.New("resume import was already applied")
+	// ErrNothingSelected is returned when an apply call selects no entries.
+	ErrNothingSelected = errors.New("nothing selected to import")
+)
+
+// ResumeImport is an extracted resume kept for review until it is applied
+// or expires.
+type ResumeImport struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthUserID      string             `bson:"auth_user_id" json:"-"`
+	FileName        string             `bson:"file_name" json:"file_name"`
+	Format          string             `bson:"format" json:"format"` // pdf | docx
+	Status          string             `bson:"status" json:"status"`
+	Draft           dto.ResumeDraft    `bson:"draft" json:"draft"`
+	AppliedSections []string           `bson:"applied_sections,omitempty" json:"applied_sections,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	AppliedAt       *time.Time         `bson:"applied_at,omitempty" json:"applied_at,omitempty"`
+	ExpiresAt       time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// CreateResumeImport stores a new draft for review.
+func CreateResumeImport(ctx context.Context, db *mongo.Database, userID, fileName, format string, draft dto.ResumeDraft) (ResumeImport, error) {
+	now := time.Now()
+	imp := ResumeImport{
+		ID:         primitive.NewObjectID(),
+		AuthUserID: userID,
+		FileName:   fileName,
+		Format:     format,
+		Status:     models.ResumeImportStatusDraft,
+		Draft:      draft,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(models.ResumeImportTTL),
+	}
+	_, err := db.Collection(models.CollectionResumeImports).InsertOne(ctx, imp)
+	return imp, err
+}
+
+// GetResumeImport loads one of the user's imports.
+func GetResumeImport(ctx context.Context, db *mongo.Database, userID string, id primitive.ObjectID) (ResumeImport, error) {
+	var imp ResumeImport
+	err := db.Collection(models.CollectionResumeImports).FindOne(ctx, bson.M{"_id": id, "auth_user_id": userID}).Decode(&imp)
+	if err == mongo.ErrNoDocuments {
+		return imp, ErrResumeImportNotFound
+	}
+	return imp, err
+}
+
+// ResumeDraftSelection selects every draft entry that can be saved as is.
+// Entries failing validation are returned in skipped, keyed by field path.
+func ResumeDraftSelection(draft dto.ResumeDraft) (dto.ResumeImportApplyRequest, map[string]string) {
+	sel := dto.ResumeImportApplyRequest{KeySkills: draft.KeySkills.Skills}
+	skipped := map[string]string{}
+	valid := func(path string, v interface{}) bool {
+		if err := binding.Validator.ValidateStruct(v); err != nil {
+			skipped[path] = err.Error()
+			return false
+		}
+		return true
+	}
+
+	if draft.PersonalInfo != nil && valid("personal_info", draft.PersonalInfo) {
+		sel.PersonalInfo = draft.PersonalInfo
+	}
+	for i, we := range draft.WorkExperiences {
+		if valid(fmt.Sprintf("work_experiences[%d]", i), &we) {
+			sel.WorkExperiences = append(sel.WorkExperiences, we)
+		}
+	}
+	for i, a := range draft.Academics {
+		if valid(fmt.Sprintf("academics[%d]", i), &a) {
+			sel.Academics = append(sel.Academics, a)
+		}
+	}
+	for i, l := range draft.Languages {
+		if l.ProficiencyLevel == "" {
+			skipped[fmt.Sprintf("languages[%d]", i)] = "proficiency is missing"
+			continue
+		}
+		if valid(fmt.Sprintf("languages[%d]", i), &l) {
+			sel.Languages = append(sel.Languages, l)
+		}
+	}
+	return sel, skipped
+}
+
+// ApplyResumeImport writes the selected entries to the seeker in one update,
+// marks the import applied and completes the matching onboarding steps.
+// Section entries are added to the existing ones; personal info is replaced
+// and key skills are merged.
+func ApplyResumeImport(ctx context.Context, db *mongo.Database, userID string, id primitive.ObjectID, sel dto.ResumeImportApplyRequest) (dto.ResumeImportResult, error) {
+	result := dto.ResumeImportResult{Sections: []string{}}
+
+	seekers := db.Collection(models.CollectionSeekers)
+	var seeker models.Seeker
+	if err := seekers.FindOne(ctx, bson.M{"auth_user_id": userID}).Decode(&seeker); err != nil {
+		return result, err
+	}
+
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	push := bson.M{}
+	if sel.PersonalInfo != nil {
+		if err := SetPersonalInfo(&seeker, sel.PersonalInfo); err != nil {
+			return result, err
+		}
+		set["personal_info"] = seeker.PersonalInfo
+		result.Sections = append(result.Sections, models.OnboardingStepPersonalInfo)
+	}
+	if len(sel.WorkExperiences) > 0 {
+		items := make([]models.WorkExperience, 0, len(sel.WorkExperiences))
+		for _, we := range sel.WorkExperiences {
+			items = append(items, NewWorkExperience(we))
+		}
+		push[SectionWorkExperiences] = bson.M{"$each": items}
+		result.Sections = append(result.Sections, models.OnboardingStepWorkExperiences)
+	}
+	if len(sel.Academics) > 0 {
+		items := make([]models.Academics, 0, len(sel.Academics))
+		for _, a := range sel.Academics {
+			items = append(items, NewAcademics(a))
+		}
+		push[SectionAcademics] = bson.M{"$each": items}
+		result.Sections = append(result.Sections, models.OnboardingStepAcademics)
+	}
+	if len(sel.Languages) > 0 {
+		items := make([]models.Language, 0, len(sel.Languages))
+		for _, l := range sel.Languages {
+			items = append(items, NewLanguage(l))
+		}
+		push[SectionLanguages] = bson.M{"$each": items}
+		result.Sections = append(result.Sections, models.OnboardingStepLanguages)
+	}
+	if len(sel.KeySkills) > 0 {
+		merged := append(append([]string{}, seeker.KeySkills...), sel.KeySkills...)
+		set["key_skills"] = skills.Default().Canonicalize(merged)
+		result.Sections = append(result.Sections, models.OnboardingStepKeySkills)
+	}
+	if len(result.Sections) == 0 {
+		return result, ErrNothingSelected
+	}
+
+	// Claim the draft first so a repeated call cannot import it twice
+	imports := db.Collection(models.CollectionResumeImports)
+	importFilter := bson.M{"_id": id, "auth_user_id": userID}
+	res, err := imports.UpdateOne(ctx,
+		bson.M{"_id": id, "auth_user_id": userID, "status": models.ResumeImportStatusDraft},
+		bson.M{"$set": bson.M{"status": models.ResumeImportStatusApplied, "applied_at": now, "applied_sections": result.Sections}},
+	)
+	if err != nil {
+		return result, err
+	}
+	if res.MatchedCount == 0 {
+		if n, _ := imports.CountDocuments(ctx, importFilter); n == 0 {
+			return result, ErrResumeImportNotFound
+		}
+		return result, ErrResumeImportApplied
+	}
+
+	update := bson.M{"$set": set}
+	if len(push) > 0 {
+		update["$push"] = push
+	}
+	if _, err := seekers.UpdateOne(ctx, bson.M{"auth_user_id": userID}, update); err != nil {
+		// Release the draft so the user can retry
+		_, _ = imports.UpdateOne(ctx, importFilter, bson.M{
+			"$set":   bson.M{"status": models.ResumeImportStatusDraft},
+			"$unset": bson.M{"applied_at": "", "applied_sections": ""},
+		})
+		return result, err
+	}
+
+	for _, step := range result.Sections {
+		completed, next, err := CompleteOnboardingStep(ctx, db, userID, step)
+		if err != nil {
+			return result, err
+		}
+		result.Completed, result.NextStep = completed, next
+	}
+	return result, nil
+}
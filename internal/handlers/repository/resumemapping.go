@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"RAAS/internal/dto"
+	"RAAS/internal/skills"
+
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// extractedConfidence is used for fields the extraction service returns
+// without a score of its own.
+const extractedConfidence = 0.8
+
+// extracted is a value from the extraction response with its confidence.
+type extracted struct {
+	value      interface{}
+	confidence float64
+}
+
+// field returns the first of keys present in m. The extraction service may
+// wrap values as {"value": ..., "confidence": ...}.
+func field(m map[string]interface{}, keys ...string) (extracted, bool) {
+	for _, k := range keys {
+		v, ok := m[k]
+		if !ok || v == nil {
+			continue
+		}
+		e := extracted{value: v, confidence: extractedConfidence}
+		if w, ok := v.(map[string]interface{}); ok {
+			if inner, ok := w["value"]; ok {
+				e.value = inner
+				if c, ok := w["confidence"].(float64); ok {
+					e.confidence = math.Max(0, math.Min(c, 1))
+				}
+			}
+		}
+		if e.text() == "" && len(e.list()) == 0 && object(e.value) == nil {
+			continue
+		}
+		return e, true
+	}
+	return extracted{}, false
+}
+
+// text renders the value as a string; lists are joined line by line.
+func (e extracted) text() string {
+	switch v := e.value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return fmt.Sprint(v)
+	case []interface{}:
+		var lines []string
+		for _, item := range v {
+			if s := (extracted{value: item}).text(); s != "" {
+				lines = append(lines, s)
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	return ""
+}
+
+// list returns the value as a list; strings are split on commas.
+func (e extracted) list() []interface{} {
+	switch v := e.value.(type) {
+	case []interface{}:
+		return v
+	case string:
+		var out []interface{}
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// object returns v as a JSON object, unwrapping {"value": {...}}.
+func object(v interface{}) map[string]interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if inner, ok := m["value"].(map[string]interface{}); ok {
+		return inner
+	}
+	return m
+}
+
+// draftBuilder maps extraction output into a ResumeDraft, recording the
+// confidence of every field it fills and the issues it finds.
+type draftBuilder struct {
+	draft dto.ResumeDraft
+}
+
+func (b *draftBuilder) score(path string, confidence float64) {
+	b.draft.Confidence[path] = math.Round(confidence*100) / 100
+}
+
+func (b *draftBuilder) issue(path, msg string) {
+	b.draft.Issues[path] = msg
+}
+
+func (b *draftBuilder) text(m map[string]interface{}, path string, keys ...string) string {
+	e, ok := field(m, keys...)
+	if !ok {
+		return ""
+	}
+	s := e.text()
+	if s != "" {
+		b.score(path, e.confidence)
+	}
+	return s
+}
+
+func (b *draftBuilder) required(m map[string]interface{}, path string, keys ...string) string {
+	s := b.text(m, path, keys...)
+	if s == "" {
+		b.issue(path, "missing")
+	}
+	return s
+}
+
+func (b *draftBuilder) optional(m map[string]interface{}, path string, keys ...string) *string {
+	if s := b.text(m, path, keys...); s != "" {
+		return &s
+	}
+	return nil
+}
+
+// period maps a start and end date. A missing end date means the entry is
+// current, which is scored lower than an explicit "present".
+func (b *draftBuilder) period(m map[string]interface{}, path string) (time.Time, *time.Time) {
+	var start time.Time
+	if e, ok := field(m, "start_date", "start", "from", "begin"); ok {
+		if t, precision, _, ok := parseResumeDate(e.text()); ok {
+			start = t
+			b.score(path+".start_date", e.confidence*precision)
+		} else {
+			b.issue(path+".start_date", fmt.Sprintf("unrecognized date %q", e.text()))
+		}
+	} else {
+		b.issue(path+".start_date", "missing")
+	}
+
+	e, ok := field(m, "end_date", "end", "to", "until")
+	if !ok {
+		b.score(path+".end_date", 0.5)
+		return start, nil
+	}
+	t, precision, current, ok := parseResumeDate(e.text())
+	switch {
+	case current:
+		b.score(path+".end_date", e.confidence)
+		return start, nil
+	case !ok:
+		b.issue(path+".end_date", fmt.Sprintf("unrecognized date %q", e.text()))
+		return start, nil
+	}
+	b.score(path+".end_date", e.confidence*precision)
+	return start, &t
+}
+
+// resumeDateLayouts are tried in order; precision lowers the confidence of
+// dates missing a day or month.
+var resumeDateLayouts = []struct {
+	layout    string
+	precision float64
+}{
+	{"2006-01-02", 1},
+	{time.RFC3339, 1},
+	{"02.01.2006", 1},
+	{"01/02/2006", 0.9},
+	{"2006-01", 0.9},
+	{"01/2006", 0.9},
+	{"1/2006", 0.9},
+	{"01.2006", 0.9},
+	{"Jan 2006", 0.9},
+	{"January 2006", 0.9},
+	{"Jan. 2006", 0.9},
+	{"2006", 0.6},
+}
+
+var currentDateWords = []string{"present", "current", "now", "today", "ongoing", "heute", "aktuell"}
+
+// parseResumeDate parses the date formats found in resumes. current is set
+// for values such as "Present".
+func parseResumeDate(s string) (t time.Time, precision float64, current bool, ok bool) {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	for _, w := range currentDateWords {
+		if strings.Contains(lower, w) {
+			return time.Time{}, 1, true, false
+		}
+	}
+	for _, l := range resumeDateLayouts {
+		if t, err := time.Parse(l.layout, s); err == nil {
+			return t, l.precision, false, true
+		}
+	}
+	return time.Time{}, 0, false, false
+}
+
+// proficiencyWords maps CEFR levels and common descriptions to our levels.
+var proficiencyWords = map[string]string{
+	"native": "native", "mother": "native", "muttersprache": "native", "bilingual": "native",
+	"c2": "fluent", "c1": "fluent", "fluent": "fluent", "proficient": "fluent", "advanced": "fluent",
+	"business": "fluent", "verhandlungssicher": "fluent", "fließend": "fluent",
+	"b2": "intermediate", "b1": "intermediate", "intermediate": "intermediate", "conversational": "intermediate", "good": "intermediate",
+	"a2": "beginner", "a1": "beginner", "beginner": "beginner", "basic": "beginner", "elementary": "beginner", "grundkenntnisse": "beginner",
+}
+
+// mapProficiency finds a proficiency level in free text.
+func mapProficiency(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, w := range words {
+		if level, ok := proficiencyWords[w]; ok {
+			return level
+		}
+	}
+	return ""
+}
+
+// splitLanguage splits entries such as "German (C1)" or "English - fluent".
+func splitLanguage(s string) (string, string) {
+	if i := strings.IndexAny(s, "(:-–,"); i > 0 {
+		return strings.TrimSpace(s[:i]), strings.Trim(strings.TrimSpace(s[i:]), "(:-–,) ")
+	}
+	return strings.TrimSpace(s), ""
+}
+
+// MapResumeExtraction maps the extraction service response into a draft of
+// profile requests. Unknown keys are ignored; several common spellings are
+// accepted for each section and field.
+func MapResumeExtraction(raw map[string]interface{}) dto.ResumeDraft {
+	b := &draftBuilder{draft: dto.ResumeDraft{
+		WorkExperiences: []dto.WorkExperienceRequest{},
+		Academics:       []dto.AcademicsRequest{},
+		Languages:       []dto.LanguageRequest{},
+		KeySkills:       dto.KeySkillsRequest{Skills: []string{}},
+		Confidence:      map[string]float64{},
+		Issues:          map[string]string{},
+	}}
+	if data := object(raw["data"]); data != nil {
+		raw = data
+	}
+
+	b.personalInfo(raw)
+	b.workExperiences(raw)
+	b.academics(raw)
+	b.languages(raw)
+	b.keySkills(raw)
+	return b.draft
+}
+
+func (b *draftBuilder) personalInfo(raw map[string]interface{}) {
+	info := raw
+	if e, ok := field(raw, "personal_info", "personal_information", "contact", "basics"); ok && object(e.value) != nil {
+		info = object(e.value)
+	}
+
+	p := dto.PersonalInfoRequest{
+		FirstName:       b.text(info, "personal_info.first_name", "first_name", "firstname", "given_name"),
+		SecondName:      b.optional(info, "personal_info.second_name", "second_name", "last_name", "lastname", "surname", "family_name"),
+		Country:         b.optional(info, "personal_info.country", "country"),
+		State:           b.optional(info, "personal_info.state", "state", "region"),
+		City:            b.optional(info, "personal_info.city", "city"),
+		LinkedInProfile: b.optional(info, "personal_info.linkedin_profile", "linkedin_profile", "linkedin", "linkedin_url"),
+	}
+	// A full name is split into first names and surname
+	if p.FirstName == "" {
+		if e, ok := field(info, "name", "full_name"); ok {
+			parts := strings.Fields(e.text())
+			if len(parts) > 0 {
+				p.FirstName = strings.Join(parts[:max(len(parts)-1, 1)], " ")
+				b.score("personal_info.first_name", e.confidence*0.8)
+			}
+			if len(parts) > 1 && p.SecondName == nil {
+				last := parts[len(parts)-1]
+				p.SecondName = &last
+				b.score("personal_info.second_name", e.confidence*0.8)
+			}
+		}
+	}
+
+	if p.FirstName == "" && p.SecondName == nil && p.City == nil && p.Country == nil && p.LinkedInProfile == nil {
+		return
+	}
+	if p.FirstName == "" {
+		b.issue("personal_info.first_name", "missing")
+	}
+	b.draft.PersonalInfo = &p
+}
+
+func (b *draftBuilder) workExperiences(raw map[string]interface{}) {
+	e, ok := field(raw, "work_experiences", "work_experience", "experience", "experiences", "employment_history")
+	if !ok {
+		return
+	}
+	for _, item := range e.list() {
+		m := object(item)
+		if m == nil {
+			continue
+		}
+		path := fmt.Sprintf("work_experiences[%d]", len(b.draft.WorkExperiences))
+		we := dto.WorkExperienceRequest{
+			JobTitle:            b.required(m, path+".job_title", "job_title", "title", "position", "role", "designation"),
+			CompanyName:         b.required(m, path+".company_name", "company_name", "company", "employer", "organization"),
+			Location:            b.text(m, path+".location", "location", "city"),
+			KeyResponsibilities: b.optional(m, path+".key_responsibilities", "key_responsibilities", "responsibilities", "description", "achievements"),
+		}
+		we.StartDate, we.EndDate = b.period(m, path)
+		b.draft.WorkExperiences = append(b.draft.WorkExperiences, we)
+	}
+}
+
+func (b *draftBuilder) academics(raw map[string]interface{}) {
+	e, ok := field(raw, "academics", "education", "educations")
+	if !ok {
+		return
+	}
+	for _, item := range e.list() {
+		m := object(item)
+		if m == nil {
+			continue
+		}
+		path := fmt.Sprintf("academics[%d]", len(b.draft.Academics))
+		a := dto.AcademicsRequest{
+			Institution:  b.required(m, path+".institution", "institution", "school", "university", "college"),
+			City:         b.optional(m, path+".city", "city", "location"),
+			Degree:       b.required(m, path+".degree", "degree", "qualification"),
+			FieldOfStudy: b.required(m, path+".field_of_study", "field_of_study", "field", "major", "subject"),
+			Description:  b.optional(m, path+".description", "description", "achievements", "grade"),
+		}
+		a.StartDate, a.EndDate = b.period(m, path)
+		b.draft.Academics = append(b.draft.Academics, a)
+	}
+}
+
+func (b *draftBuilder) languages(raw map[string]interface{}) {
+	e, ok := field(raw, "languages", "language_skills")
+	if !ok {
+		return
+	}
+	for _, item := range e.list() {
+		path := fmt.Sprintf("languages[%d]", len(b.draft.Languages))
+		var name, level string
+		confidence := e.confidence
+		if m := object(item); m != nil {
+			name = b.text(m, path+".language", "language", "name")
+			if lv, ok := field(m, "proficiency", "level", "fluency"); ok {
+				level, confidence = mapProficiency(lv.text()), lv.confidence
+			}
+		} else {
+			var rest string
+			name, rest = splitLanguage((extracted{value: item}).text())
+			level = mapProficiency(rest)
+			if name != "" {
+				b.score(path+".language", e.confidence)
+			}
+		}
+		if name == "" {
+			continue
+		}
+		if level == "" {
+			b.issue(path+".proficiency", "missing")
+		} else {
+			b.score(path+".proficiency", confidence)
+		}
+		b.draft.Languages = append(b.draft.Languages, dto.LanguageRequest{LanguageName: name, ProficiencyLevel: level})
+	}
+}
+
+func (b *draftBuilder) keySkills(raw map[string]interface{}) {
+	e, ok := field(raw, "key_skills", "skills", "technical_skills")
+	if !ok {
+		return
+	}
+	// Skills may come grouped by category
+	items := e.list()
+	if groups := object(e.value); groups != nil {
+		for _, g := range groups {
+			items = append(items, (extracted{value: g}).list()...)
+		}
+	}
+
+	var names []string
+	for _, item := range items {
+		if m := object(item); m != nil {
+			if s, ok := field(m, "name", "skill"); ok {
+				names = append(names, s.text())
+			}
+			continue
+		}
+		names = append(names, (extracted{value: item}).text())
+	}
+
+	// Known skills are stored under their canonical name and trusted more
+	taxonomy := skills.Default()
+	for i, name := range taxonomy.Canonicalize(names) {
+		confidence := e.confidence * 0.75
+		if _, known := taxonomy.Lookup(name); known {
+			confidence = e.confidence
+		}
+		b.score(fmt.Sprintf("key_skills[%d]", i), confidence)
+		b.draft.KeySkills.Skills = append(b.draft.KeySkills.Skills, name)
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	// ErrSavedJobNotFound is returned when the user has not saved the job.
+	ErrSavedJobNotFound = errors.New("saved job not found")
+	// ErrAlreadyTracked is returned when the job already has an application.
+	ErrAlreadyTracked = errors.New("job is already in the application tracker")
+	// ErrSavedJobRemoved is returned when the saved posting no longer exists.
+	ErrSavedJobRemoved = errors.New("saved job posting was removed")
+)
+
+// SavedJobWarning explains why a saved job may no longer be worth applying to.
+// job is nil when the posting has been removed.
+func SavedJobWarning(job *models.Job) string {
+	switch {
+	case job == nil:
+		return models.SavedJobWarningRemoved
+	case !job.IsActive || (job.ExpiresAt != nil && job.ExpiresAt.Before(time.Now())):
+		return models.SavedJobWarningExpired
+	case !job.PostedDate.IsZero() && job.PostedDate.Before(PostedSince(DefaultPostedWithinDays)):
+		return models.SavedJobWarningStale
+	}
+	return ""
+}
+
+// MoveSavedJobToTracker creates a tracker entry in the "saved" status for a
+// saved job and removes it from the saved list. Generating documents for it
+// later moves it on to "generated".
+func MoveSavedJobToTracker(ctx context.Context, db *mongo.Database, userID, jobID string) (models.SelectedJobApplication, error) {
+	savedColl := db.Collection(models.CollectionSavedJobs)
+	filter := bson.M{"auth_user_id": userID, "job_id": jobID}
+
+	var saved models.SavedJob
+	if err := savedColl.FindOne(ctx, filter).Decode(&saved); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.SelectedJobApplication{}, ErrSavedJobNotFound
+		}
+		return models.SelectedJobApplication{}, err
+	}
+
+	var job models.Job
+	if err := db.Collection(models.CollectionJobs).FindOne(ctx, bson.M{"job_id": jobID}).Decode(&job); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.SelectedJobApplication{}, ErrSavedJobRemoved
+		}
+		return models.SelectedJobApplication{}, err
+	}
+
+	now := time.Now()
+	app := models.SelectedJobApplication{
+		ID:           primitive.NewObjectID(),
+		AuthUserID:   userID,
+		JobID:        jobID,
+		Status:       models.ApplicationStatusSaved,
+		Source:       models.ApplicationSourceInternal,
+		Company:      job.Company,
+		SelectedDate: now,
+		UpdatedAt:    &now,
+		StatusHistory: []models.StatusChange{{
+			To:        models.ApplicationStatusSaved,
+			ChangedAt: now,
+		}},
+	}
+	if saved.Notes != "" {
+		app.Notes = []models.ApplicationNote{{ID: primitive.NewObjectID(), Text: saved.Notes, CreatedAt: saved.SavedAt}}
+	}
+
+	if _, err := db.Collection(models.CollectionSelectedJobApps).InsertOne(ctx, app); err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return app, ErrAlreadyTracked
+		}
+		return app, err
+	}
+
+	if _, err := savedColl.DeleteOne(ctx, filter); err != nil {
+		return app, err
+	}
+	return app, nil
+}
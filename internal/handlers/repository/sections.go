@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Seeker profile sections, stored as arrays of entries with their own _id
+const (
+	SectionWorkExperiences = "work_experiences"
+	SectionAcademics       = "academics"
+	SectionPastProjects    = "past_projects"
+	SectionCertificates    = "certificates"
+	SectionLanguages       = "languages"
+)
+
+// ErrSectionItemNotFound is returned when the seeker has no entry with the given id.
+var ErrSectionItemNotFound = errors.New("profile entry not found")
+
+// PushSectionItem appends an entry to a profile section.
+func PushSectionItem(ctx context.Context, seekers *mongo.Collection, userID, section string, item interface{}) error {
+	res, err := seekers.UpdateOne(ctx,
+		bson.M{"auth_user_id": userID},
+		bson.M{
+			"$push": bson.M{section: item},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// ReplaceSectionItem overwrites the entry with the given id, keeping its id
+// and created_at.
+func ReplaceSectionItem(ctx context.Context, seekers *mongo.Collection, userID, section string, id primitive.ObjectID, item interface{}) error {
+	raw, err := bson.Marshal(item)
+	if err != nil {
+		return err
+	}
+	var fields bson.M
+	if err := bson.Unmarshal(raw, &fields); err != nil {
+		return err
+	}
+	delete(fields, "_id")
+	delete(fields, "created_at")
+
+	now := time.Now()
+	fields["updated_at"] = now
+	set := bson.M{"updated_at": now}
+	for k, v := range fields {
+		set[section+".$."+k] = v
+	}
+
+	res, err := seekers.UpdateOne(ctx,
+		bson.M{"auth_user_id": userID, section + "._id": id},
+		bson.M{"$set": set},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrSectionItemNotFound
+	}
+	return nil
+}
+
+// DeleteSectionItem removes the entry with the given id.
+func DeleteSectionItem(ctx context.Context, seekers *mongo.Collection, userID, section string, id primitive.ObjectID) error {
+	res, err := seekers.UpdateOne(ctx,
+		bson.M{"auth_user_id": userID, section + "._id": id},
+		bson.M{
+			"$pull": bson.M{section: bson.M{"_id": id}},
+			"$set":  bson.M{"updated_at": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return ErrSectionItemNotFound
+	}
+	return nil
+}
+
+// ResolveSectionItemID parses an entry id from a route parameter. Clients that
+// still address entries by their 1-based position get the id of that entry.
+func ResolveSectionItemID(ctx context.Context, seekers *mongo.Collection, userID, section, param string) (primitive.ObjectID, error) {
+	if id, err := primitive.ObjectIDFromHex(param); err == nil {
+		return id, nil
+	}
+	index, err := strconv.Atoi(param)
+	if err != nil || index <= 0 {
+		return primitive.NilObjectID, ErrSectionItemNotFound
+	}
+
+	var doc bson.Raw
+	if err := seekers.FindOne(ctx,
+		bson.M{"auth_user_id": userID},
+		options.FindOne().SetProjection(bson.M{section + "._id": 1}),
+	).Decode(&doc); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return primitive.NilObjectID, ErrSectionItemNotFound
+		}
+		return primitive.NilObjectID, err
+	}
+	arr, ok := doc.Lookup(section).ArrayOK()
+	if !ok {
+		return primitive.NilObjectID, ErrSectionItemNotFound
+	}
+	entry, err := arr.IndexErr(uint(index - 1))
+	if err != nil {
+		return primitive.NilObjectID, ErrSectionItemNotFound
+	}
+	id, ok := entry.Value().Document().Lookup("_id").ObjectIDOK()
+	if !ok {
+		return primitive.NilObjectID, ErrSectionItemNotFound
+	}
+	return id, nil
+}
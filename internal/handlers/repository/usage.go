@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"RAAS/core/config"
+	"RAAS/internal/models"
+
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrQuotaExceeded is returned when the seeker has no quota left for a generation.
+var ErrQuotaExceeded = errors.New("limit reached")
+
+// ErrRegenerationQueued is returned, along with ErrQuotaExceeded, when a
+// regeneration of the cover letter is already queued.
+var ErrRegenerationQueued = errors.New("regeneration already queued")
+
+// errReservationHeld aborts a reservation for an application whose quota
+// another queued generation already holds.
+var errReservationHeld = errors.New("reservation held")
+
+// RegenerationCost is the share of an application, in hundredths, one cover
+// letter regeneration costs.
+func RegenerationCost() int {
+	if config.Cfg == nil || config.Cfg.Project == nil {
+		return config.DefaultCoverLetterRegenerationCost
+	}
+	return config.Cfg.Project.CoverLetterRegenerationCost
+}
+
+// UsageResource is the single definition of what consumes quota: CV and
+// cover letter generation start an application, which costs one application
+// of the job's source, and a cover letter regeneration costs a share of one.
+// Job research is free. It returns "" for free kinds.
+func UsageResource(kind, source string) string {
+	switch kind {
+	case models.GenerationKindExternal:
+		return models.UsageExternalApplication
+	case models.GenerationKindCV, models.GenerationKindCoverLetter:
+		return models.ApplicationResource(source)
+	case models.GenerationKindCoverLetterRegeneration:
+		return models.UsageCoverLetterRegeneration
+	}
+	return ""
+}
+
+// applicationStarted reports whether the job already has an application
+// that paid for its quota. Saved jobs have not consumed any yet.
+func applicationStarted(ctx context.Context, db *mongo.Database, userID, jobID string) (bool, error) {
+	var app models.SelectedJobApplication
+	err := db.Collection(models.CollectionSelectedJobApps).FindOne(ctx,
+		bson.M{"auth_user_id": userID, "job_id": jobID},
+		options.FindOne().SetProjection(bson.M{"status": 1}),
+	).Decode(&app)
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return app.Status != models.ApplicationStatusSaved, nil
+}
+
+// usageCost returns the resource a generation would consume, or "" when it
+// is free: free kinds, regenerations while they cost nothing, generations
+// for a started application and generations for a job whose application
+// quota is already reserved.
+func usageCost(ctx context.Context, db *mongo.Database, userID, jobID, kind, source string) (string, error) {
+	resource := UsageResource(kind, source)
+	if resource == "" {
+		return "", nil
+	}
+	if resource == models.UsageCoverLetterRegeneration {
+		if RegenerationCost() <= 0 {
+			return "", nil
+		}
+		return resource, nil
+	}
+	started, err := applicationStarted(ctx, db, userID, jobID)
+	if err != nil || started {
+		return "", err
+	}
+	held, err := db.Collection(models.CollectionUsageLedger).CountDocuments(ctx,
+		bson.M{"auth_user_id": userID, "job_id": jobID, "resource": resource, "status": models.UsageReserved})
+	if err != nil || held > 0 {
+		return "", err
+	}
+	return resource, nil
+}
+
+// CheckUsage fails with ErrQuotaExceeded when the generation would need
+// quota the seeker does not have. Nothing is reserved.
+func CheckUsage(ctx context.Context, db *mongo.Database, userID, jobID, kind, source string) error {
+	resource, err := usageCost(ctx, db, userID, jobID, kind, source)
+	if err != nil || resource == "" {
+		return err
+	}
+	app := models.ApplicationResource(source)
+	if resource != models.UsageCoverLetterRegeneration {
+		app = resource
+	}
+	counter := models.UsageCounters[app]
+	filter := bson.M{"auth_user_id": userID, counter: bson.M{"$gt": 0}}
+	if resource == models.UsageCoverLetterRegeneration {
+		filter = bson.M{"auth_user_id": userID, "$or": bson.A{
+			bson.M{counter: bson.M{"$gt": 0}},
+			bson.M{models.UsageCredits[app]: bson.M{"$gte": RegenerationCost()}},
+		}}
+	}
+	n, err := db.Collection(models.CollectionSeekers).CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%s %w", counter, ErrQuotaExceeded)
+	}
+	return nil
+}
+
+// ReserveUsage takes the quota a generation needs and records it in the
+// ledger as reserved. It returns nil when the generation is free.
+//
+// A partial charge is taken from the credit left over from earlier ones; when
+// that is not enough, a whole application is taken from the counter and the
+// rest of it kept as credit.
+func ReserveUsage(ctx context.Context, db *mongo.Database, userID, jobID string, generationJobID primitive.ObjectID, kind, source string) (*models.UsageEntry, error) {
+	resource, err := usageCost(ctx, db, userID, jobID, kind, source)
+	if err != nil || resource == "" {
+		return nil, err
+	}
+	partial := resource == models.UsageCoverLetterRegeneration
+	app := resource
+	if partial {
+		app = models.ApplicationResource(source)
+	}
+	counter, credit := models.UsageCounters[app], models.UsageCredits[app]
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	entry := models.UsageEntry{
+		AuthUserID:      userID,
+		JobID:           jobID,
+		GenerationJobID: generationJobID,
+		Kind:            kind,
+		Resource:        resource,
+		Source:          source,
+		Status:          models.UsageReserved,
+		CreatedAt:       time.Now(),
+	}
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		seekers := db.Collection(models.CollectionSeekers)
+		projection := options.FindOneAndUpdate().SetProjection(bson.M{"subscription_interval_start": 1})
+		var seeker models.Seeker
+		err := mongo.ErrNoDocuments
+		entry.Amount, entry.Credit = 1, 0
+		if partial {
+			cost := RegenerationCost()
+			entry.Amount, entry.Credit = 0, cost
+			err = seekers.FindOneAndUpdate(sc,
+				bson.M{"auth_user_id": userID, credit: bson.M{"$gte": cost}},
+				bson.M{"$inc": bson.M{credit: -cost}}, projection,
+			).Decode(&seeker)
+			if err == mongo.ErrNoDocuments {
+				entry.Amount = 1
+				err = seekers.FindOneAndUpdate(sc,
+					bson.M{"auth_user_id": userID, counter: bson.M{"$gte": 1}},
+					bson.M{"$inc": bson.M{counter: -1, credit: 100 - cost}}, projection,
+				).Decode(&seeker)
+			}
+		} else {
+			err = seekers.FindOneAndUpdate(sc,
+				bson.M{"auth_user_id": userID, counter: bson.M{"$gte": entry.Amount}},
+				bson.M{"$inc": bson.M{counter: -entry.Amount}}, projection,
+			).Decode(&seeker)
+		}
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("%s %w", counter, ErrQuotaExceeded)
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry.PeriodStart = seeker.SubscriptionIntervalStart
+
+		res, err := db.Collection(models.CollectionUsageLedger).InsertOne(sc, entry)
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, errReservationHeld
+		}
+		if err != nil {
+			return nil, err
+		}
+		entry.ID, _ = res.InsertedID.(primitive.ObjectID)
+		return nil, nil
+	})
+	if errors.Is(err, errReservationHeld) {
+		if partial {
+			// One regeneration per cover letter is queued at a time
+			return nil, fmt.Errorf("%w: %w", ErrRegenerationQueued, ErrQuotaExceeded)
+		}
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CommitUsage marks a reservation as consumed.
+func CommitUsage(ctx context.Context, db *mongo.Database, entryID primitive.ObjectID) error {
+	now := time.Now()
+	_, err := db.Collection(models.CollectionUsageLedger).UpdateOne(ctx,
+		bson.M{"_id": entryID, "status": models.UsageReserved},
+		bson.M{"$set": bson.M{"status": models.UsageCommitted, "settled_at": now}},
+	)
+	return err
+}
+
+// ReleaseUsage gives the quota of a failed generation back. A reservation
+// for an application that was started meanwhile by another generation for
+// the same job paid for that application and is committed instead. Quota
+// reserved in an earlier billing period is not refunded into the current one.
+func ReleaseUsage(ctx context.Context, db *mongo.Database, entryID primitive.ObjectID, reason string) error {
+	ledger := db.Collection(models.CollectionUsageLedger)
+	var entry models.UsageEntry
+	err := ledger.FindOne(ctx, bson.M{"_id": entryID, "status": models.UsageReserved}).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	partial := entry.Resource == models.UsageCoverLetterRegeneration
+	if !partial {
+		started, err := applicationStarted(ctx, db, entry.AuthUserID, entry.JobID)
+		if err != nil {
+			return err
+		}
+		if started {
+			return CommitUsage(ctx, db, entryID)
+		}
+	}
+
+	session, err := db.Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		res, err := ledger.UpdateOne(sc,
+			bson.M{"_id": entryID, "status": models.UsageReserved},
+			bson.M{"$set": bson.M{"status": models.UsageReleased, "reason": reason, "settled_at": time.Now()}},
+		)
+		if err != nil || res.ModifiedCount == 0 {
+			return nil, err
+		}
+
+		app := entry.Resource
+		if partial {
+			app = models.ApplicationResource(entry.Source)
+		}
+		counter := models.UsageCounters[app]
+		refund := bson.M{counter: entry.Amount}
+		if entry.Credit > 0 {
+			// Undo exactly what the reservation took; the credit may dip
+			// below zero when later charges used the leftover, which the
+			// next partial charge settles
+			refund[models.UsageCredits[app]] = entry.Credit - 100*entry.Amount
+		}
+		period := interface{}(entry.PeriodStart)
+		if entry.PeriodStart.IsZero() {
+			period = bson.M{"$in": bson.A{nil, time.Time{}}}
+		}
+		_, err = db.Collection(models.CollectionSeekers).UpdateOne(sc,
+			bson.M{"auth_user_id": entry.AuthUserID, counter: bson.M{"$exists": true}, "subscription_interval_start": period},
+			bson.M{"$inc": refund},
+		)
+		return nil, err
+	})
+	return err
+}
+
+// ListUsage returns the ledger entries of a seeker created in [from, to),
+// newest first. A zero bound is open.
+func ListUsage(ctx context.Context, db *mongo.Database, userID string, from, to time.Time) ([]models.UsageEntry, error) {
+	filter := bson.M{"auth_user_id": userID}
+	created := bson.M{}
+	if !from.IsZero() {
+		created["$gte"] = from
+	}
+	if !to.IsZero() {
+		created["$lt"] = to
+	}
+	if len(created) > 0 {
+		filter["created_at"] = created
+	}
+	cursor, err := db.Collection(models.CollectionUsageLedger).Find(ctx, filter,
+		options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	entries := []models.UsageEntry{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
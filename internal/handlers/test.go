@@ -1,13 +1,13 @@
 package handlers
 
 import (
+	"RAAS/internal/models"
+
 	"net/http"
-	// "time"
 
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestAcademicDatesHandler(c *gin.Context) {
@@ -21,46 +21,27 @@ func TestAcademicDatesHandler(c *gin.Context) {
 
 	seekColl := db.Collection("seekers")
 
-	var seeker bson.M
+	var seeker models.Seeker
 	err := seekColl.FindOne(c, bson.M{"auth_user_id": authUserID}).Decode(&seeker)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "seeker not found"})
 		return
 	}
 
-	academicsRaw, ok := seeker["academics"].(primitive.A)
-	if !ok {
-		c.JSON(http.StatusOK, gin.H{"academics": []string{}})
-		return
-	}
-
 	type Response struct {
 		StartDate string `json:"start_date"`
 		EndDate   string `json:"end_date"`
 	}
 
-	var results []Response
-
-	for _, raw := range academicsRaw {
-		entry, ok := raw.(bson.M)
-		if !ok {
-			continue
-		}
-
-		var startStr, endStr string
-
-		if start, ok := entry["start_date"].(primitive.DateTime); ok {
-			startStr = start.Time().Format("2006-01-02")
-		}
-
-		if end, ok := entry["end_date"].(primitive.DateTime); ok && !end.Time().IsZero() {
-			endStr = end.Time().Format("2006-01-02")
-		} else {
-			endStr = "Present"
+	results := []Response{}
+	for _, entry := range seeker.Academics {
+		endStr := "Present"
+		if entry.EndDate != nil && !entry.EndDate.IsZero() {
+			endStr = entry.EndDate.Format("2006-01-02")
 		}
 
 		results = append(results, Response{
-			StartDate: startStr,
+			StartDate: entry.StartDate.Format("2006-01-02"),
 			EndDate:   endStr,
 		})
 	}
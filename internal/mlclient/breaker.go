@@ -0,0 +1,59 @@
+package mlclient
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	breakerThreshold = 5                // consecutive failures that open the circuit
+	breakerCooldown  = 30 * time.Second // how long an open circuit rejects calls
+)
+
+// breaker stops calling an endpoint after repeated failures. Once the
+// cooldown passed one probe call is let through; its outcome closes the
+// circuit or opens it for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func newBreaker() *breaker {
+	return &breaker{}
+}
+
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < breakerThreshold {
+		return true
+	}
+	if time.Now().Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if ok {
+		if b.failures >= breakerThreshold {
+			log.Println("✅ [ML] service recovered, circuit closed")
+		}
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= breakerThreshold {
+		if b.failures == breakerThreshold {
+			log.Printf("⚠️ [ML] %d consecutive failures, circuit open for %s", b.failures, breakerCooldown)
+		}
+		b.openUntil = time.Now().Add(breakerCooldown)
+	}
+}
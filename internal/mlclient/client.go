@@ -0,0 +1,288 @@
+// Package mlclient calls the ML service: CV, cover letter and job research
+// generation and resume extraction. Calls have per-endpoint timeouts, retry
+// with exponential backoff on 5xx, 429 and network errors, fail fast while a
+// circuit breaker is open, log with personal data redacted and return only
+// responses that pass the endpoint's schema check. With ML_STUB set, or a
+// client from NewStub, calls are answered by a built-in stub instead.
+package mlclient
+
+import (
+	"RAAS/core/config"
+
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Endpoint names an ML API.
+type Endpoint string
+
+const (
+	CV               Endpoint = "cv"
+	CoverLetter      Endpoint = "cover_letter"
+	JobResearch      Endpoint = "job_research"
+	ResumeExtraction Endpoint = "resume_extraction"
+)
+
+const (
+	maxAttempts      = 3
+	baseBackoff      = 500 * time.Millisecond
+	maxBackoff       = 15 * time.Second
+	maxResponseBytes = 8 << 20
+)
+
+var (
+	// ErrCircuitOpen is returned without calling the service while it is considered down.
+	ErrCircuitOpen = errors.New("ML service unavailable")
+	// ErrInvalidResponse is returned for responses that fail the schema check.
+	ErrInvalidResponse = errors.New("invalid response")
+)
+
+type endpoint struct {
+	url      func() string
+	timeout  time.Duration
+	validate func(map[string]interface{}) error
+}
+
+var endpoints = map[Endpoint]endpoint{
+	CV:               {func() string { return config.Cfg.Cloud.CV_Url }, 120 * time.Second, validateCV},
+	CoverLetter:      {func() string { return config.Cfg.Cloud.CL_Url }, 90 * time.Second, validateCoverLetter},
+	JobResearch:      {func() string { return config.Cfg.Cloud.JobResearchURL }, 120 * time.Second, validateJobResearch},
+	ResumeExtraction: {func() string { return config.Cfg.Cloud.DataExtractionAPI }, 60 * time.Second, validateResumeExtraction},
+}
+
+// Error is a failed ML call.
+type Error struct {
+	Endpoint  Endpoint
+	Status    int    // HTTP status, 0 when no response was received
+	Body      string // response body with personal data redacted
+	Err       error
+	Retryable bool
+}
+
+func (e *Error) Error() string {
+	if e.Status != 0 {
+		return fmt.Sprintf("%s API error %d: %s", e.Endpoint, e.Status, e.Body)
+	}
+	return fmt.Sprintf("%s API: %v", e.Endpoint, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Retryable reports whether a failed call may succeed later.
+func Retryable(err error) bool {
+	var mlErr *Error
+	return errors.As(err, &mlErr) && mlErr.Retryable
+}
+
+// Client calls the ML endpoints. The zero value is not usable; use New.
+type Client struct {
+	stub     bool
+	http     *http.Client
+	mu       sync.Mutex
+	breakers map[Endpoint]*breaker
+}
+
+// New returns a client with its own circuit breakers.
+func New() *Client {
+	return &Client{http: &http.Client{}, breakers: map[Endpoint]*breaker{}}
+}
+
+// NewStub returns a client that answers every call with the built-in stub
+// and never touches the network or the configuration.
+func NewStub() *Client {
+	c := New()
+	c.stub = true
+	return c
+}
+
+// Default is the client shared by the handlers and workers.
+var Default = New()
+
+// PostJSON sends payload as JSON to the endpoint with the default client.
+func PostJSON(ctx context.Context, ep Endpoint, payload map[string]interface{}) (map[string]interface{}, error) {
+	return Default.PostJSON(ctx, ep, payload)
+}
+
+// PostMultipart sends a multipart form to the endpoint with the default client.
+func PostMultipart(ctx context.Context, ep Endpoint, body []byte, contentType string) (map[string]interface{}, error) {
+	return Default.PostMultipart(ctx, ep, body, contentType)
+}
+
+func (c *Client) PostJSON(ctx context.Context, ep Endpoint, payload map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, &Error{Endpoint: ep, Err: fmt.Errorf("encode request: %w", err)}
+	}
+	if c.logBodies() {
+		log.Printf("[ML] %s request: %s", ep, redactedJSON(payload))
+	}
+	return c.do(ctx, ep, body, "application/json")
+}
+
+func (c *Client) PostMultipart(ctx context.Context, ep Endpoint, body []byte, contentType string) (map[string]interface{}, error) {
+	if c.logBodies() {
+		log.Printf("[ML] %s request: multipart upload of %d bytes", ep, len(body))
+	}
+	return c.do(ctx, ep, body, contentType)
+}
+
+func (c *Client) stubbed() bool {
+	return c.stub || (config.Cfg != nil && config.Cfg.Cloud.MLStub)
+}
+
+func (c *Client) logBodies() bool {
+	return config.Cfg != nil && config.Cfg.Cloud.MLLogBodies
+}
+
+// callStub answers from the stub, checked like a real response.
+func (c *Client) callStub(ep Endpoint, cfg endpoint, body []byte, contentType string) (map[string]interface{}, error) {
+	out, err := stubResponse(ep, body, contentType)
+	if err == nil {
+		err = cfg.validate(out)
+	}
+	if err != nil {
+		return nil, &Error{Endpoint: ep, Err: fmt.Errorf("stub: %w", err)}
+	}
+	if c.logBodies() {
+		log.Printf("[ML] %s stub response: %s", ep, redactedJSON(out))
+	}
+	return out, nil
+}
+
+func (c *Client) breaker(ep Endpoint) *breaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	b, ok := c.breakers[ep]
+	if !ok {
+		b = newBreaker()
+		c.breakers[ep] = b
+	}
+	return b
+}
+
+func (c *Client) do(ctx context.Context, ep Endpoint, body []byte, contentType string) (map[string]interface{}, error) {
+	cfg, ok := endpoints[ep]
+	if ok && c.stubbed() {
+		return c.callStub(ep, cfg, body, contentType)
+	}
+	if !ok || cfg.url() == "" {
+		return nil, &Error{Endpoint: ep, Err: errors.New("endpoint not configured")}
+	}
+	br := c.breaker(ep)
+
+	var lastErr *Error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if !br.allow() {
+			return nil, &Error{Endpoint: ep, Err: ErrCircuitOpen, Retryable: true}
+		}
+		out, wait, err := c.once(ctx, ep, cfg, body, contentType, attempt)
+		// Rejected or malformed requests say nothing about the service's health
+		br.record(err == nil || (err.Status >= 400 && err.Status < 500 && err.Status != http.StatusTooManyRequests) || errors.Is(err, ErrInvalidResponse))
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+		if !err.Retryable || errors.Is(err, ErrInvalidResponse) || attempt == maxAttempts {
+			break
+		}
+
+		delay := backoff(attempt)
+		if wait > delay {
+			delay = wait
+		}
+		select {
+		case <-ctx.Done():
+			return nil, &Error{Endpoint: ep, Err: ctx.Err()}
+		case <-time.After(delay):
+		}
+	}
+	return nil, lastErr
+}
+
+// once makes one attempt. It returns the delay asked for by Retry-After.
+func (c *Client) once(ctx context.Context, ep Endpoint, cfg endpoint, body []byte, contentType string, attempt int) (map[string]interface{}, time.Duration, *Error) {
+	reqCtx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, cfg.url(), bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, &Error{Endpoint: ep, Err: fmt.Errorf("create request: %w", err)}
+	}
+	req.Header.Set("Authorization", "Bearer "+config.Cfg.Cloud.GEN_API_KEY)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		log.Printf("⚠️ [ML] %s attempt %d failed after %s: %v", ep, attempt, time.Since(start).Round(time.Millisecond), err)
+		// Only our own timeout is worth retrying; a cancelled caller is gone
+		return nil, 0, &Error{Endpoint: ep, Err: err, Retryable: ctx.Err() == nil}
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
+	log.Printf("[ML] %s attempt %d → %d in %s", ep, attempt, resp.StatusCode, time.Since(start).Round(time.Millisecond))
+	if err != nil {
+		return nil, 0, &Error{Endpoint: ep, Err: fmt.Errorf("read response: %w", err), Retryable: true}
+	}
+	if len(raw) > maxResponseBytes {
+		return nil, 0, &Error{Endpoint: ep, Err: fmt.Errorf("%w: response exceeds %d bytes", ErrInvalidResponse, maxResponseBytes)}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return nil, retryAfter(resp), &Error{Endpoint: ep, Status: resp.StatusCode, Body: truncate(RedactText(string(raw)), 500), Retryable: retry}
+	}
+
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, 0, &Error{Endpoint: ep, Err: fmt.Errorf("%w: %v", ErrInvalidResponse, err), Retryable: true}
+	}
+	if c.logBodies() {
+		log.Printf("[ML] %s response: %s", ep, redactedJSON(out))
+	}
+	if err := cfg.validate(out); err != nil {
+		log.Printf("❌ [ML] %s response rejected: %v", ep, err)
+		return nil, 0, &Error{Endpoint: ep, Err: fmt.Errorf("%w: %v", ErrInvalidResponse, err), Retryable: true}
+	}
+	return out, 0, nil
+}
+
+// backoff doubles the delay per attempt with up to 50% jitter.
+func backoff(attempt int) time.Duration {
+	d := baseBackoff << uint(attempt-1)
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryAfter reads a Retry-After header given in seconds.
+func retryAfter(resp *http.Response) time.Duration {
+	secs, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || secs <= 0 {
+		return 0
+	}
+	if d := time.Duration(secs) * time.Second; d < maxBackoff {
+		return d
+	}
+	return maxBackoff
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "…"
+}
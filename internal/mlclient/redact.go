@@ -0,0 +1,90 @@
+package mlclient
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[redacted]"
+
+// personalKeys are object keys whose values identify the seeker. Keys are
+// compared lowercased without '_', '-' and spaces.
+var personalKeys = map[string]bool{
+	"name": true, "fullname": true, "firstname": true, "secondname": true, "lastname": true,
+	"candidatename": true, "sendername": true, "signature": true,
+	"email": true, "emailaddress": true,
+	"phone": true, "phonenumber": true, "mobile": true, "contact": true, "contactnumber": true, "telephone": true,
+	"address": true, "street": true, "postalcode": true, "zipcode": true,
+	"linkedin": true, "linkedinprofile": true, "portfolio": true, "github": true, "website": true,
+	"dateofbirth": true, "dob": true, "birthdate": true,
+	"photo": true, "image": true, "file": true,
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`\+?\d[\d\s().-]{7,}\d`)
+)
+
+func personalKey(k string) bool {
+	return personalKeys[strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(k))]
+}
+
+// Redact returns a copy of a decoded JSON value with personal fields
+// replaced and e-mail addresses and phone numbers masked in all text.
+func Redact(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			if personalKey(k) && val != nil && val != "" {
+				out[k] = redacted
+			} else {
+				out[k] = Redact(val)
+			}
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, val := range t {
+			out[i] = Redact(val)
+		}
+		return out
+	case string:
+		return RedactText(t)
+	}
+	return v
+}
+
+// RedactText masks e-mail addresses and phone numbers. Digit runs shorter
+// than a phone number, such as dates and year ranges, are kept.
+func RedactText(s string) string {
+	s = emailPattern.ReplaceAllString(s, "[email]")
+	return phonePattern.ReplaceAllStringFunc(s, func(m string) string {
+		digits := 0
+		for _, r := range m {
+			if r >= '0' && r <= '9' {
+				digits++
+			}
+		}
+		if digits < 9 {
+			return m
+		}
+		return "[phone]"
+	})
+}
+
+// redactedJSON encodes a payload for the log, redacted and shortened.
+func redactedJSON(v interface{}) string {
+	// Round-trip so typed payloads (structs, string maps) are redacted too
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "<unencodable>"
+	}
+	var plain interface{}
+	if err := json.Unmarshal(raw, &plain); err != nil {
+		return "<unencodable>"
+	}
+	out, _ := json.Marshal(Redact(plain))
+	return truncate(string(out), 2000)
+}
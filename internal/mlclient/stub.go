@@ -0,0 +1,384 @@
+package mlclient
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// The stub answers every endpoint in-process with a deterministic response
+// built from the request, so the backend runs without the ML service. It is
+// selected with ML_STUB or by using NewStub.
+
+// stubSkills are recognised in resume text by the extraction stub.
+var stubSkills = []string{
+	"Go", "Python", "Java", "JavaScript", "TypeScript", "SQL", "MongoDB", "PostgreSQL",
+	"Docker", "Kubernetes", "AWS", "Azure", "React", "Node.js", "Git", "Linux",
+	"Excel", "SAP", "Project Management", "Communication", "Leadership",
+}
+
+var stubLanguages = []string{"English", "German", "French", "Spanish", "Italian", "Dutch", "Polish", "Turkish"}
+
+var wordPattern = regexp.MustCompile(`[\p{L}]+`)
+
+func stubResponse(ep Endpoint, body []byte, contentType string) (map[string]interface{}, error) {
+	if ep == ResumeExtraction {
+		return stubExtraction(body, contentType)
+	}
+	var req map[string]interface{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, fmt.Errorf("decode request: %w", err)
+	}
+	switch ep {
+	case CV:
+		return stubCV(req), nil
+	case CoverLetter:
+		return stubCoverLetter(req), nil
+	case JobResearch:
+		return stubJobResearch(req), nil
+	}
+	return nil, errors.New("endpoint not stubbed")
+}
+
+func obj(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+func str(m map[string]interface{}, keys ...string) string {
+	for _, k := range keys {
+		if s, ok := m[k].(string); ok && strings.TrimSpace(s) != "" {
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+func or(s, fallback string) string {
+	if s == "" {
+		return fallback
+	}
+	return s
+}
+
+func list(v interface{}) []interface{} {
+	l, _ := v.([]interface{})
+	return l
+}
+
+func strs(v interface{}) []string {
+	var out []string
+	switch t := v.(type) {
+	case string:
+		for _, s := range strings.Split(t, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			if s, ok := e.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+	}
+	return out
+}
+
+// candidate and job read the request shapes used by the generation handlers.
+func candidate(req map[string]interface{}) map[string]interface{} {
+	if m := obj(req["user_details"]); len(m) > 0 {
+		return m
+	}
+	return obj(req["candidate_profile"])
+}
+
+func job(req map[string]interface{}) (title, company string, skills []string) {
+	jd := obj(req["job_description"])
+	title = or(str(jd, "job_title", "title"), or(str(req, "job_title", "title"), "the advertised position"))
+	company = or(str(jd, "company"), or(str(req, "company"), "your company"))
+	skills = strs(jd["skills"])
+	return title, company, skills
+}
+
+// matchedSkills lists the candidate's skills the job asks for first.
+func matchedSkills(have, want []string) (matched, rest []string) {
+	wanted := map[string]bool{}
+	for _, s := range want {
+		wanted[strings.ToLower(s)] = true
+	}
+	for _, s := range have {
+		if wanted[strings.ToLower(s)] {
+			matched = append(matched, s)
+		} else {
+			rest = append(rest, s)
+		}
+	}
+	return matched, rest
+}
+
+func stubCV(req map[string]interface{}) map[string]interface{} {
+	user := candidate(req)
+	title, company, jobSkills := job(req)
+	designation := or(str(user, "designation"), title)
+	matched, rest := matchedSkills(strs(user["skills"]), jobSkills)
+
+	var experience []interface{}
+	for _, e := range list(user["experience_summary"]) {
+		m := obj(e)
+		entry := map[string]interface{}{
+			"job_title":  or(str(m, "job_title", "title"), designation),
+			"company":    str(m, "company_name", "company"),
+			"location":   str(m, "location"),
+			"start_date": str(m, "start_date"),
+			"end_date":   or(str(m, "end_date"), "Present"),
+		}
+		var bullets []interface{}
+		for _, line := range strings.Split(str(m, "key_responsibilities", "description"), "\n") {
+			if line = strings.TrimSpace(strings.TrimLeft(line, "-•* ")); line != "" {
+				bullets = append(bullets, line)
+			}
+		}
+		if len(bullets) > 0 {
+			entry["responsibilities"] = bullets
+		}
+		experience = append(experience, entry)
+	}
+
+	var education []interface{}
+	for _, e := range list(user["education"]) {
+		m := obj(e)
+		education = append(education, map[string]interface{}{
+			"degree":         str(m, "degree"),
+			"field_of_study": str(m, "field_of_study"),
+			"institution":    str(m, "institution"),
+			"start_date":     str(m, "start_date"),
+			"end_date":       str(m, "end_date"),
+		})
+	}
+
+	skills := []interface{}{}
+	for _, s := range append(matched, rest...) {
+		skills = append(skills, s)
+	}
+
+	summary := fmt.Sprintf("%s applying for %s at %s.", designation, title, company)
+	if len(matched) > 0 {
+		summary += fmt.Sprintf(" Brings hands-on experience with %s.", strings.Join(matched, ", "))
+	}
+
+	cv := map[string]interface{}{
+		"name":        or(str(user, "name"), "Candidate"),
+		"designation": designation,
+		"email":       str(user, "email"),
+		"contact":     str(user, "contact"),
+		"address":     str(user, "address"),
+		"linkedin":    str(user, "linkedin"),
+		"summary":     summary,
+		"skills":      skills,
+	}
+	if len(experience) > 0 {
+		cv["experience"] = experience
+	}
+	if len(education) > 0 {
+		cv["education"] = education
+	}
+	if l := list(user["past_projects"]); len(l) > 0 {
+		cv["projects"] = l
+	}
+	if l := list(user["certifications"]); len(l) > 0 {
+		cv["certifications"] = l
+	}
+	if l := list(user["languages"]); len(l) > 0 {
+		cv["languages"] = l
+	}
+	return cv
+}
+
+func stubCoverLetter(req map[string]interface{}) map[string]interface{} {
+	user := candidate(req)
+	title, company, jobSkills := job(req)
+	name := or(str(user, "name"), "Candidate")
+	designation := or(str(user, "designation"), "professional")
+	matched, _ := matchedSkills(strs(user["skills"]), jobSkills)
+
+	skillLine := "I am used to picking up new tools quickly and working closely with colleagues across teams."
+	if len(matched) > 0 {
+		skillLine = fmt.Sprintf("My experience with %s matches what you are looking for in this role.", strings.Join(matched, ", "))
+	}
+	experience := list(user["experience_summary"])
+	experienceLine := "I am looking forward to bringing my motivation and structured way of working to your team."
+	if len(experience) > 0 {
+		if latest := obj(experience[0]); str(latest, "company_name", "company") != "" {
+			experienceLine = fmt.Sprintf("In my role as %s at %s I took on responsibility for results that matter to the business.",
+				or(str(latest, "job_title"), designation), str(latest, "company_name", "company"))
+		}
+	}
+
+	return map[string]interface{}{
+		"subject":      fmt.Sprintf("Application for %s", title),
+		"salutation":   "Dear Hiring Manager,",
+		"introduction": fmt.Sprintf("I am writing to apply for the %s position at %s. As a %s I was glad to see this opening.", title, company, designation),
+		"body":         experienceLine + " " + skillLine,
+		"conclusion":   fmt.Sprintf("I would welcome the opportunity to discuss how I can contribute to %s.", company),
+		"closing":      "Kind regards,",
+		"sender_name":  name,
+	}
+}
+
+func stubJobResearch(req map[string]interface{}) map[string]interface{} {
+	title, company, skills := job(req)
+	if d := or(str(obj(req["job_description"]), "description"), str(req, "description")); d != "" && len(skills) == 0 {
+		skills = findSkills(d)
+	}
+	requirements := make([]interface{}, 0, len(skills))
+	for _, s := range skills {
+		requirements = append(requirements, s)
+	}
+	return map[string]interface{}{
+		"company_overview": fmt.Sprintf("%s is hiring for %s.", company, title),
+		"role_summary":     fmt.Sprintf("The %s role at %s.", title, company),
+		"key_requirements": requirements,
+		"interview_questions": []interface{}{
+			fmt.Sprintf("Why do you want to work at %s?", company),
+			fmt.Sprintf("What makes you a good fit for the %s role?", title),
+			"Describe a project you are proud of and your part in it.",
+		},
+		"talking_points": []interface{}{
+			fmt.Sprintf("Your motivation for joining %s", company),
+			"Examples that show the key requirements",
+		},
+	}
+}
+
+// stubExtraction derives a resume from the uploaded text: the "text" field
+// of DOCX uploads, or the file itself when it is plain text. Other files
+// get a placeholder resume seeded by their content hash.
+func stubExtraction(body []byte, contentType string) (map[string]interface{}, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("parse content type: %w", err)
+	}
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	var text, filename string
+	var file []byte
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read form: %w", err)
+		}
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, fmt.Errorf("read form: %w", err)
+		}
+		switch part.FormName() {
+		case "text":
+			text = string(data)
+		case "file":
+			filename, file = part.FileName(), data
+		}
+	}
+	if text == "" && isText(file) {
+		text = string(file)
+	}
+
+	first, last := "Alex", "Example"
+	if text == "" {
+		sum := sha256.Sum256(file)
+		last = fmt.Sprintf("Example-%x", sum[:2])
+	} else if name := firstLineName(text); len(name) > 0 {
+		first, last = name[0], strings.Join(name[1:], " ")
+	}
+	skills := findSkills(text)
+	if len(skills) == 0 {
+		skills = []string{"Communication"}
+	}
+	languages := []interface{}{}
+	for _, l := range stubLanguages {
+		if containsWord(text, l) {
+			languages = append(languages, map[string]interface{}{"language": l, "proficiency": "fluent"})
+		}
+	}
+	if len(languages) == 0 {
+		languages = append(languages, map[string]interface{}{"language": "English", "proficiency": "fluent"})
+	}
+
+	skillList := make([]interface{}, len(skills))
+	for i, s := range skills {
+		skillList[i] = s
+	}
+	return map[string]interface{}{
+		"personal_info": map[string]interface{}{
+			"first_name": first,
+			"last_name":  last,
+			"city":       "Berlin",
+			"country":    "Germany",
+		},
+		"work_experiences": []interface{}{map[string]interface{}{
+			"job_title":        "Software Engineer",
+			"company_name":     "Example GmbH",
+			"location":         "Berlin",
+			"start_date":       "2020-01",
+			"end_date":         "present",
+			"responsibilities": "Extracted from " + or(filename, "the uploaded resume"),
+		}},
+		"academics": []interface{}{map[string]interface{}{
+			"institution":    "Example University",
+			"degree":         "Bachelor",
+			"field_of_study": "Computer Science",
+			"start_date":     "2015-10",
+			"end_date":       "2019-09",
+		}},
+		"languages":  languages,
+		"key_skills": skillList,
+	}, nil
+}
+
+func isText(b []byte) bool {
+	return len(b) > 0 && bytes.IndexByte(b, 0) < 0 && utf8.Valid(b)
+}
+
+// firstLineName reads a name from the first line made of two to four words.
+func firstLineName(text string) []string {
+	for _, line := range strings.Split(text, "\n") {
+		words := wordPattern.FindAllString(line, -1)
+		if len(words) == 0 {
+			continue
+		}
+		if len(words) >= 2 && len(words) <= 4 && len(words) == len(strings.Fields(line)) {
+			return words
+		}
+		return nil
+	}
+	return nil
+}
+
+func containsWord(text, word string) bool {
+	return regexp.MustCompile(`(?i)(^|[^\p{L}])` + regexp.QuoteMeta(word) + `($|[^\p{L}])`).MatchString(text)
+}
+
+func findSkills(text string) []string {
+	var found []string
+	for _, s := range stubSkills {
+		if containsWord(text, s) {
+			found = append(found, s)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
@@ -0,0 +1,184 @@
+package mlclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The ML service names fields loosely; keys are compared lowercased without
+// '_', '-' and spaces, and a response may be wrapped in one of wrapperKeys.
+var (
+	wrapperKeys = set("cv", "cvdata", "resume", "coverletter", "cldata", "letter", "data", "result", "output", "research")
+	cvSections  = set("summary", "profile", "profilesummary", "professionalsummary", "objective",
+		"experience", "workexperience", "experiences", "experiencesummary", "employment", "professionalexperience",
+		"education", "academics", "qualifications", "projects", "pastprojects", "skills", "keyskills", "technicalskills",
+		"certifications", "certificates", "languages")
+	cvListSections = set("experience", "workexperience", "experiences", "education", "academics", "projects", "pastprojects", "certifications", "certificates")
+	letterKeys     = set("body", "content", "text", "letter", "coverletter", "paragraphs", "bodyparagraphs",
+		"introduction", "intro", "openingparagraph", "middle", "middleparagraph", "conclusion", "closingparagraph", "calltoaction")
+	errorKeys = set("error", "errors", "detail", "message")
+)
+
+// minLetterText is the shortest letter body accepted, in characters.
+const minLetterText = 80
+
+func set(keys ...string) map[string]bool {
+	m := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		m[k] = true
+	}
+	return m
+}
+
+func norm(k string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(k))
+}
+
+// NormalizeKey returns the form in which field names of the service are
+// compared.
+func NormalizeKey(k string) string {
+	return norm(k)
+}
+
+// Unwrap returns the content of a response or stored document wrapped in
+// one of the service's wrapper keys.
+func Unwrap(m map[string]interface{}) map[string]interface{} {
+	return unwrap(m)
+}
+
+// unwrap descends into a lone wrapper object such as {"cv_data": {...}}.
+func unwrap(m map[string]interface{}) map[string]interface{} {
+	for len(m) == 1 {
+		var next map[string]interface{}
+		for k, v := range m {
+			if inner, ok := v.(map[string]interface{}); ok && wrapperKeys[norm(k)] {
+				next = inner
+			}
+		}
+		if next == nil {
+			break
+		}
+		m = next
+	}
+	return m
+}
+
+// serviceError rejects responses that only report a failure.
+func serviceError(m map[string]interface{}) error {
+	if len(m) == 0 {
+		return errors.New("empty response")
+	}
+	for k, v := range m {
+		if !errorKeys[norm(k)] {
+			return nil
+		}
+		if s, ok := v.(string); ok && s != "" {
+			return fmt.Errorf("service reported: %s", truncate(RedactText(s), 200))
+		}
+	}
+	return errors.New("service reported an error")
+}
+
+func empty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(t) == ""
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// text collects the strings in a value.
+func text(v interface{}, sb *strings.Builder) {
+	switch t := v.(type) {
+	case string:
+		sb.WriteString(t)
+	case []interface{}:
+		for _, e := range t {
+			text(e, sb)
+		}
+	case map[string]interface{}:
+		for _, e := range t {
+			text(e, sb)
+		}
+	}
+}
+
+// validateCV requires at least one filled CV section, with list sections
+// given as lists or objects.
+func validateCV(resp map[string]interface{}) error {
+	m := unwrap(resp)
+	if err := serviceError(m); err != nil {
+		return err
+	}
+	filled := 0
+	for k, v := range m {
+		key := norm(k)
+		if !cvSections[key] || empty(v) {
+			continue
+		}
+		if cvListSections[key] {
+			switch v.(type) {
+			case []interface{}, map[string]interface{}, string:
+			default:
+				return fmt.Errorf("section %q has type %T", k, v)
+			}
+		}
+		filled++
+	}
+	if filled == 0 {
+		return errors.New("no CV sections")
+	}
+	return nil
+}
+
+// validateCoverLetter requires a letter body of some length.
+func validateCoverLetter(resp map[string]interface{}) error {
+	m := unwrap(resp)
+	if err := serviceError(m); err != nil {
+		return err
+	}
+	var sb strings.Builder
+	for k, v := range m {
+		if letterKeys[norm(k)] {
+			text(v, &sb)
+		}
+	}
+	if n := len(strings.TrimSpace(sb.String())); n < minLetterText {
+		return fmt.Errorf("letter body has %d characters", n)
+	}
+	return nil
+}
+
+// validateJobResearch requires at least one filled field.
+func validateJobResearch(resp map[string]interface{}) error {
+	m := unwrap(resp)
+	if err := serviceError(m); err != nil {
+		return err
+	}
+	for _, v := range m {
+		if !empty(v) {
+			return nil
+		}
+	}
+	return errors.New("no research content")
+}
+
+// validateResumeExtraction requires at least one extracted field.
+func validateResumeExtraction(resp map[string]interface{}) error {
+	if err := serviceError(resp); err != nil {
+		return err
+	}
+	for _, v := range resp {
+		if !empty(v) {
+			return nil
+		}
+	}
+	return errors.New("nothing extracted")
+}
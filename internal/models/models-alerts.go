@@ -0,0 +1,76 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Job alert frequencies
+const (
+	AlertFrequencyDaily  = "daily"
+	AlertFrequencyWeekly = "weekly"
+)
+
+// SearchCriteria mirrors the filters accepted by /b1/api/jobs/search.
+type SearchCriteria struct {
+	Q             string  `bson:"q,omitempty" json:"q,omitempty"`
+	JobType       string  `bson:"job_type,omitempty" json:"job_type,omitempty"`
+	Location      string  `bson:"location,omitempty" json:"location,omitempty"`
+	JobLang       string  `bson:"job_language,omitempty" json:"job_language,omitempty"`
+	Company       string  `bson:"company,omitempty" json:"company,omitempty"`
+	MinMatchScore float64 `bson:"min_match_score,omitempty" json:"min_match_score,omitempty"`
+}
+
+type SavedSearch struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthUserID       string             `bson:"auth_user_id" json:"auth_user_id"`
+	Name             string             `bson:"name" json:"name"`
+	Criteria         SearchCriteria     `bson:"criteria" json:"criteria"`
+	Frequency        string             `bson:"frequency" json:"frequency"` // daily | weekly
+	AlertsEnabled    bool               `bson:"alerts_enabled" json:"alerts_enabled"`
+	UnsubscribeToken string             `bson:"unsubscribe_token" json:"-"`
+	LastSentAt       *time.Time         `bson:"last_sent_at,omitempty" json:"last_sent_at,omitempty"`
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+func CreateSavedSearchIndexes(collection *mongo.Collection) error {
+	indexModel1 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "created_at", Value: -1}},
+	}
+	indexModel2 := mongo.IndexModel{
+		Keys:    bson.D{{Key: "unsubscribe_token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	indexModel3 := mongo.IndexModel{
+		Keys: bson.D{{Key: "alerts_enabled", Value: 1}, {Key: "frequency", Value: 1}},
+	}
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2, indexModel3})
+	return err
+}
+
+// JobAlertDelivery records that a job was already sent for a saved search,
+// so digests never repeat a job.
+type JobAlertDelivery struct {
+	SavedSearchID primitive.ObjectID `bson:"saved_search_id" json:"saved_search_id"`
+	AuthUserID    string             `bson:"auth_user_id" json:"auth_user_id"`
+	JobID         string             `bson:"job_id" json:"job_id"`
+	SentAt        time.Time          `bson:"sent_at" json:"sent_at"`
+}
+
+func CreateJobAlertDeliveryIndexes(collection *mongo.Collection) error {
+	indexModel1 := mongo.IndexModel{
+		Keys:    bson.D{{Key: "saved_search_id", Value: 1}, {Key: "job_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	indexModel2 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}},
+	}
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2})
+	return err
+}
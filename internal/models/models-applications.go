@@ -0,0 +1,180 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Application pipeline statuses
+const (
+	ApplicationStatusSaved     = "saved"
+	ApplicationStatusGenerated = "generated" // CV / cover letter generated, not yet sent
+	ApplicationStatusApplied   = "applied"
+	ApplicationStatusScreening = "screening"
+	ApplicationStatusInterview = "interview" // repeatable, one entry per round
+	ApplicationStatusOffer     = "offer"
+	ApplicationStatusAccepted  = "accepted"
+	ApplicationStatusRejected  = "rejected"
+	ApplicationStatusWithdrawn = "withdrawn"
+	ApplicationStatusDeleted   = "deleted" // hidden from the tracker, allowed from any state
+)
+
+// Application sources
+const (
+	ApplicationSourceInternal = "internal"
+	ApplicationSourceExternal = "external"
+	ApplicationSourceManual   = "manual" // tracked by the user, not generated here
+)
+
+// ApplicationPipelineOrder is the column order of the tracker board.
+var ApplicationPipelineOrder = []string{
+	ApplicationStatusSaved,
+	ApplicationStatusGenerated,
+	ApplicationStatusApplied,
+	ApplicationStatusScreening,
+	ApplicationStatusInterview,
+	ApplicationStatusOffer,
+	ApplicationStatusAccepted,
+	ApplicationStatusRejected,
+	ApplicationStatusWithdrawn,
+}
+
+// EmployerResponseStatuses are statuses that mean the employer reacted to an application.
+var EmployerResponseStatuses = []string{
+	ApplicationStatusScreening,
+	ApplicationStatusInterview,
+	ApplicationStatusOffer,
+	ApplicationStatusAccepted,
+	ApplicationStatusRejected,
+}
+
+// Statuses written before the pipeline existed, mapped to their pipeline equivalent.
+var legacyApplicationStatuses = map[string]string{
+	"pending":  ApplicationStatusGenerated,
+	"selected": ApplicationStatusOffer,
+}
+
+// applicationTransitions lists the statuses reachable from each status.
+var applicationTransitions = map[string][]string{
+	ApplicationStatusSaved:     {ApplicationStatusGenerated, ApplicationStatusApplied, ApplicationStatusWithdrawn},
+	ApplicationStatusGenerated: {ApplicationStatusApplied, ApplicationStatusWithdrawn},
+	ApplicationStatusApplied:   {ApplicationStatusScreening, ApplicationStatusInterview, ApplicationStatusOffer, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+	ApplicationStatusScreening: {ApplicationStatusInterview, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+	ApplicationStatusInterview: {ApplicationStatusInterview, ApplicationStatusOffer, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+	ApplicationStatusOffer:     {ApplicationStatusAccepted, ApplicationStatusRejected, ApplicationStatusWithdrawn},
+	ApplicationStatusAccepted:  {},
+	ApplicationStatusRejected:  {},
+	ApplicationStatusWithdrawn: {},
+}
+
+// SubmittedApplicationStatuses are the statuses of applications that were
+// actually sent, used to hide those jobs from recommendations.
+var SubmittedApplicationStatuses = []string{
+	ApplicationStatusApplied,
+	ApplicationStatusScreening,
+	ApplicationStatusInterview,
+	ApplicationStatusOffer,
+	ApplicationStatusAccepted,
+	ApplicationStatusRejected,
+	ApplicationStatusWithdrawn,
+}
+
+// NormalizeApplicationStatus maps legacy status values onto the pipeline.
+func NormalizeApplicationStatus(status string) string {
+	if s, ok := legacyApplicationStatuses[status]; ok {
+		return s
+	}
+	return status
+}
+
+// ReachedInterviewStage reports whether an application is at its interview
+// stage or past it without being closed, i.e. interview, offer or accepted.
+func ReachedInterviewStage(status string) bool {
+	switch NormalizeApplicationStatus(strings.ToLower(status)) {
+	case ApplicationStatusInterview, ApplicationStatusOffer, ApplicationStatusAccepted:
+		return true
+	}
+	return false
+}
+
+// IsApplicationStatus reports whether status is a known pipeline status.
+func IsApplicationStatus(status string) bool {
+	_, ok := applicationTransitions[status]
+	return ok || status == ApplicationStatusDeleted
+}
+
+// AllowedApplicationTransitions returns the statuses an application may move to next.
+func AllowedApplicationTransitions(from string) []string {
+	from = NormalizeApplicationStatus(from)
+	if from == ApplicationStatusDeleted {
+		return []string{}
+	}
+	next := append([]string{}, applicationTransitions[from]...)
+	return append(next, ApplicationStatusDeleted)
+}
+
+// CanTransitionApplication reports whether moving from -> to is allowed.
+func CanTransitionApplication(from, to string) bool {
+	for _, s := range AllowedApplicationTransitions(from) {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange is one entry of an application's status history.
+type StatusChange struct {
+	From           string    `bson:"from,omitempty" json:"from,omitempty"`
+	To             string    `bson:"to" json:"to"`
+	InterviewRound int       `bson:"interview_round,omitempty" json:"interview_round,omitempty"`
+	Note           string    `bson:"note,omitempty" json:"note,omitempty"`
+	ChangedAt      time.Time `bson:"changed_at" json:"changed_at"`
+}
+
+// Attachment kinds for uploaded documents
+const (
+	AttachmentKindCV          = "cv"
+	AttachmentKindCoverLetter = "cl"
+)
+
+// ApplicationAttachmentMeta describes an uploaded document on the application itself.
+type ApplicationAttachmentMeta struct {
+	Kind       string    `bson:"kind" json:"kind"`
+	FileName   string    `bson:"file_name" json:"file_name"`
+	Size       int       `bson:"size" json:"size"`
+	UploadedAt time.Time `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+// ApplicationAttachment stores an uploaded CV or cover letter PDF.
+type ApplicationAttachment struct {
+	AuthUserID string    `bson:"auth_user_id" json:"auth_user_id"`
+	JobID      string    `bson:"job_id" json:"job_id"`
+	Kind       string    `bson:"kind" json:"kind"`
+	FileName   string    `bson:"file_name" json:"file_name"`
+	MimeType   string    `bson:"mime_type" json:"mime_type"`
+	Data       []byte    `bson:"data" json:"-"`
+	UploadedAt time.Time `bson:"uploaded_at" json:"uploaded_at"`
+}
+
+func CreateApplicationAttachmentIndexes(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_id", Value: 1}, {Key: "kind", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := collection.Indexes().CreateOne(context.Background(), indexModel)
+	return err
+}
+
+// ApplicationNote is a free-text note attached to an application.
+type ApplicationNote struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	Text      string             `bson:"text" json:"text"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
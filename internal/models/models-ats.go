@@ -0,0 +1,68 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ATSAnalysis rates how well the CV generated for a job would pass an
+// applicant-tracking system. Scores range from 0 to 100.
+type ATSAnalysis struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"-"`
+	AuthUserID  string             `bson:"auth_user_id" json:"-"`
+	JobID       string             `bson:"job_id" json:"job_id"`
+	Source      string             `bson:"source" json:"source"` // internal | external
+	CVVersion   int                `bson:"cv_version,omitempty" json:"cv_version,omitempty"`
+	CVHash      string             `bson:"cv_hash" json:"-"` // analysed cv_data, to detect edits
+	Score       int                `bson:"ats_score" json:"ats_score"`
+	Keywords    ATSKeywords        `bson:"keywords" json:"keywords"`
+	Sections    ATSSections        `bson:"sections" json:"sections"`
+	Length      ATSLength          `bson:"length" json:"length"`
+	Readability ATSReadability     `bson:"readability" json:"readability"`
+	AnalyzedAt  time.Time          `bson:"analyzed_at" json:"analyzed_at"`
+}
+
+// ATSKeywords are the job's skills and title found in the CV.
+type ATSKeywords struct {
+	Matched      []string `bson:"matched" json:"matched"`
+	Missing      []string `bson:"missing" json:"missing"`
+	TitleMatched bool     `bson:"title_matched" json:"title_matched"`
+	Score        int      `bson:"score" json:"score"`
+}
+
+// ATSSections reports the standard CV sections. Only the required ones
+// count towards the score.
+type ATSSections struct {
+	Present  []string `bson:"present" json:"present"`
+	Missing  []string `bson:"missing" json:"missing"`
+	Optional []string `bson:"optional" json:"optional"` // optional sections present
+	Score    int      `bson:"score" json:"score"`
+}
+
+type ATSLength struct {
+	Words int `bson:"words" json:"words"`
+	Score int `bson:"score" json:"score"`
+}
+
+// ATSReadability is scored on sentence length, which works for every CV
+// language; the Flesch reading ease is meaningful for English only.
+type ATSReadability struct {
+	Sentences         int     `bson:"sentences" json:"sentences"`
+	AvgSentenceWords  float64 `bson:"avg_sentence_words" json:"avg_sentence_words"`
+	LongSentences     int     `bson:"long_sentences" json:"long_sentences"`
+	FleschReadingEase float64 `bson:"flesch_reading_ease" json:"flesch_reading_ease"`
+	Score             int     `bson:"score" json:"score"`
+}
+
+func CreateATSAnalysisIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -62,14 +62,16 @@ type Seeker struct {
 
 	ExternalApplications         int                `json:"external_application_count" bson:"external_application_count"`
 	InternalApplications         int                `json:"internal_application_count" bson:"internal_application_count"`
+	ExternalApplicationCredit    int                `json:"external_application_credit" bson:"external_application_credit"` // hundredths left from partial charges
+	InternalApplicationCredit    int                `json:"internal_application_credit" bson:"internal_application_credit"`
 	ProficiencyTest            	int                	`json:"proficiency_test" bson:"proficiency_test"`
 
 	PersonalInfo                bson.M             `json:"personal_info" bson:"personal_info"`
-	WorkExperiences             []bson.M           `json:"work_experiences" bson:"work_experiences"`
-	Academics                   []bson.M           `json:"academics" bson:"academics"`
-	PastProjects                []bson.M           `json:"past_projects" bson:"past_projects"`
-	Certificates                []bson.M           `json:"certificates" bson:"certificates"`
-	Languages                   []bson.M           `json:"languages" bson:"languages"`
+	WorkExperiences             []WorkExperience   `json:"work_experiences" bson:"work_experiences"`
+	Academics                   []Academics        `json:"academics" bson:"academics"`
+	PastProjects                []PastProject      `json:"past_projects" bson:"past_projects"`
+	Certificates                []Certificate      `json:"certificates" bson:"certificates"`
+	Languages                   []Language         `json:"languages" bson:"languages"`
 	KeySkills                   []string           `json:"key_skills" bson:"key_skills"`
 
 	PrimaryTitle                string             `json:"primary_title" bson:"primary_title"`
@@ -189,4 +191,4 @@ func CreateProfilePicIndexes(collection *mongo.Collection) error {
 	}
 	_, err := collection.Indexes().CreateOne(context.Background(), indexModel)
 	return err
-}
\ No newline at end of file
+}
\ No newline at end of file
@@ -1,9 +1,14 @@
 package models
 
 import (
+	"context"
+	"errors"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 
@@ -32,22 +37,25 @@ type PersonalInfo struct {
 	UpdatedAt       time.Time          `bson:"updated_at"`
 }
 
+// Profile sections are embedded in the seeker document as arrays. Every entry
+// has its own _id so it can be updated or removed in place, see
+// repository.PushSectionItem. Optional values are stored as null rather than
+// omitted so that replacing an entry clears them.
+
 // =======================
 // WORK EXPERIENCE
 // =======================
 
 type WorkExperience struct {
-	ID                  	primitive.ObjectID `bson:"_id,omitempty"`
-	AuthUserID          	string             `bson:"auth_user_id"`
-
-	JobTitle           		string     			`json:"job_title"`
-	CompanyName        		string     			`json:"company_name"`
-	StartDate          		time.Time  			`json:"start_date"`
-	EndDate            		*time.Time 			`json:"end_date,omitempty"`
-	KeyResponsibilities 	*string    			`json:"key_responsibilities,omitempty"`
-	Location           		string     			`json:"location"` // not a pointer   // Description of work
-	CreatedAt       		time.Time          	`bson:"created_at"`
-	UpdatedAt       		time.Time          	`bson:"updated_at"`
+	ID                  primitive.ObjectID `bson:"_id" json:"id"`
+	JobTitle            string             `bson:"job_title" json:"job_title"`
+	CompanyName         string             `bson:"company_name" json:"company_name"`
+	Location            string             `bson:"location" json:"location"`
+	StartDate           time.Time          `bson:"start_date" json:"start_date"`
+	EndDate             *time.Time         `bson:"end_date" json:"end_date"`
+	KeyResponsibilities *string            `bson:"key_responsibilities" json:"key_responsibilities"`
+	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 // =======================
@@ -55,17 +63,16 @@ type WorkExperience struct {
 // =======================
 
 type Academics struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty"`
-	AuthUserID      string             `bson:"auth_user_id"`
-	Institution     string             `bson:"institution"`
-	City            string            `bson:"city,omitempty"`
-	Degree          string             `bson:"degree"`
-	FieldOfStudy    string             `bson:"field_of_study"`
-	StartDate       time.Time          `bson:"start_date"`
-	EndDate         *time.Time         `bson:"end_date,omitempty"`
-	Description     *string            `bson:"description,omitempty"` // e.g., Achievements, activities, awards
-	CreatedAt       time.Time          `bson:"created_at"`
-	UpdatedAt       time.Time          `bson:"updated_at"`
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	Institution  string             `bson:"institution" json:"institution"`
+	City         *string            `bson:"city" json:"city"`
+	Degree       string             `bson:"degree" json:"degree"`
+	FieldOfStudy string             `bson:"field_of_study" json:"field_of_study"`
+	StartDate    time.Time          `bson:"start_date" json:"start_date"`
+	EndDate      *time.Time         `bson:"end_date" json:"end_date"`
+	Achievements *string            `bson:"achievements" json:"achievements"` // e.g., achievements, activities, awards
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt    time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 // =======================
@@ -73,15 +80,14 @@ type Academics struct {
 // =======================
 
 type PastProject struct {
-	ID                	primitive.ObjectID `bson:"_id,omitempty"`
-	AuthUserID        	string             `bson:"auth_user_id"`                    // Link to the user
-	ProjectName       	string             `bson:"project_name"`                    // Name of the project
-	Institution       	string             `bson:"institution"`                     // University or Company
-	StartDate         	time.Time          `bson:"start_date"`                      // Required
-	EndDate           	*time.Time         `bson:"end_date,omitempty"`              // Optional
-	ProjectDescription 	*string            `bson:"project_description"`             // Summary or detail of the project
-	CreatedAt         	time.Time          `bson:"created_at"`
-	UpdatedAt         	time.Time          `bson:"updated_at"`
+	ID                 primitive.ObjectID `bson:"_id" json:"id"`
+	ProjectName        string             `bson:"project_name" json:"project_name"`
+	Institution        string             `bson:"institution" json:"institution"` // University or Company
+	StartDate          time.Time          `bson:"start_date" json:"start_date"`
+	EndDate            *time.Time         `bson:"end_date" json:"end_date"`
+	ProjectDescription *string            `bson:"project_description" json:"project_description"`
+	CreatedAt          time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt          time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 // =======================
@@ -89,12 +95,11 @@ type PastProject struct {
 // =======================
 
 type Language struct {
-	ID               primitive.ObjectID `bson:"_id,omitempty"`
-	AuthUserID       string             `bson:"auth_user_id"`
-	LanguageName     string             `bson:"language"`
-	ProficiencyLevel string             `bson:"proficiency"`
-	CreatedAt        time.Time          `bson:"created_at"`
-	UpdatedAt        time.Time          `bson:"updated_at"`
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	LanguageName     string             `bson:"language" json:"language"`
+	ProficiencyLevel string             `bson:"proficiency" json:"proficiency"` // beginner | intermediate | fluent | native
+	CreatedAt        time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt        time.Time          `bson:"updated_at" json:"updated_at"`
 }
 
 // =======================
@@ -102,12 +107,117 @@ type Language struct {
 // =======================
 
 type Certificate struct {
-    ID               primitive.ObjectID `bson:"_id,omitempty"`
-    AuthUserID       string             `bson:"auth_user_id"`
-    CertificateName  string             `bson:"certificate_name"`                      // Title of the certificate
-    CertificateType  string             `bson:"certificate_type"`                      // e.g. "certification", "completion", "participation", "appreciation", "internship", etc.
-    Provider         *string            `bson:"provider,omitempty"`                    // Issuing organization (e.g., Coursera, Company XYZ)
-    CompletionDate   time.Time          `bson:"completion_date"`                       // Date certificate was earned
-    CreatedAt        time.Time          `bson:"created_at"`
-    UpdatedAt        time.Time          `bson:"updated_at"`
+	ID              primitive.ObjectID `bson:"_id" json:"id"`
+	CertificateName string             `bson:"certificate_name" json:"certificate_name"`
+	CertificateType string             `bson:"certificate_type" json:"certificate_type"` // certification | completion | appreciation | participation | internship
+	Provider        *string            `bson:"provider" json:"provider"`                 // Issuing organization
+	CompletionDate  time.Time          `bson:"completion_date" json:"completion_date"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Proficiency levels accepted for a language
+var LanguageProficiencyLevels = []string{"beginner", "intermediate", "fluent", "native"}
+
+// SeekerSectionsSchema is the $jsonSchema validator for the profile sections
+// of seeker documents.
+func SeekerSectionsSchema() bson.M {
+	str := bson.M{"bsonType": "string"}
+	optStr := bson.M{"bsonType": bson.A{"string", "null"}}
+	date := bson.M{"bsonType": "date"}
+	optDate := bson.M{"bsonType": bson.A{"date", "null"}}
+
+	section := func(required []string, props bson.M) bson.M {
+		props["_id"] = bson.M{"bsonType": "objectId"}
+		props["created_at"] = date
+		props["updated_at"] = date
+		return bson.M{
+			"bsonType": "array",
+			"items": bson.M{
+				"bsonType":   "object",
+				"required":   append([]string{"_id"}, required...),
+				"properties": props,
+			},
+		}
+	}
+
+	return bson.M{
+		"bsonType": "object",
+		"properties": bson.M{
+			"work_experiences": section([]string{"job_title", "company_name", "start_date"}, bson.M{
+				"job_title":            str,
+				"company_name":         str,
+				"location":             optStr,
+				"start_date":           date,
+				"end_date":             optDate,
+				"key_responsibilities": optStr,
+			}),
+			"academics": section([]string{"institution", "degree", "field_of_study", "start_date"}, bson.M{
+				"institution":    str,
+				"city":           optStr,
+				"degree":         str,
+				"field_of_study": str,
+				"start_date":     date,
+				"end_date":       optDate,
+				"achievements":   optStr,
+			}),
+			"past_projects": section([]string{"project_name", "institution", "start_date"}, bson.M{
+				"project_name":        str,
+				"institution":         str,
+				"start_date":          date,
+				"end_date":            optDate,
+				"project_description": optStr,
+			}),
+			"certificates": section([]string{"certificate_name", "certificate_type", "completion_date"}, bson.M{
+				"certificate_name": str,
+				"certificate_type": str,
+				"provider":         optStr,
+				"completion_date":  date,
+			}),
+			"languages": section([]string{"language", "proficiency"}, bson.M{
+				"language":    str,
+				"proficiency": bson.M{"enum": LanguageProficiencyLevels},
+			}),
+		},
+	}
+}
+
+// ApplySeekerSchema installs SeekerSectionsSchema as the validator of the
+// seekers collection. Validation is moderate: documents that do not match yet
+// can still be updated.
+func ApplySeekerSchema(ctx context.Context, db *mongo.Database) error {
+	err := db.RunCommand(ctx, bson.D{
+		{Key: "collMod", Value: CollectionSeekers},
+		{Key: "validator", Value: bson.M{"$jsonSchema": SeekerSectionsSchema()}},
+		{Key: "validationLevel", Value: "moderate"},
+	}).Err()
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Name == "NamespaceNotFound" {
+		return db.CreateCollection(ctx, CollectionSeekers, options.CreateCollection().
+			SetValidator(bson.M{"$jsonSchema": SeekerSectionsSchema()}).
+			SetValidationLevel("moderate"))
+	}
+	return err
+}
+
+// =======================
+// RESUME IMPORT
+// =======================
+
+// Resume import drafts are kept for review this long before the TTL index
+// removes them.
+const ResumeImportTTL = 7 * 24 * time.Hour
+
+// Resume import statuses
+const (
+	ResumeImportStatusDraft   = "draft"
+	ResumeImportStatusApplied = "applied"
+)
+
+func CreateResumeImportIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
 }
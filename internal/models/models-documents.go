@@ -0,0 +1,80 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Kinds of generated documents
+const (
+	DocumentKindCV          = "cv"
+	DocumentKindCoverLetter = "cover_letter"
+)
+
+// Authors of a document version
+const (
+	VersionAuthorUser      = "user"      // edited or restored by the seeker
+	VersionAuthorGenerator = "generator" // written by the ML API
+)
+
+// DocumentVersionRetention is the number of versions kept per document for
+// each subscription tier. Older versions are pruned when a new one is saved.
+var DocumentVersionRetention = map[string]int{
+	"free":     5,
+	"basic":    20,
+	"advanced": 40,
+	"premium":  100,
+}
+
+// VersionRetention returns the version cap of a tier; unknown tiers get the free cap.
+func VersionRetention(tier string) int {
+	if n, ok := DocumentVersionRetention[strings.ToLower(tier)]; ok {
+		return n
+	}
+	return DocumentVersionRetention["free"]
+}
+
+// CoverLetterOptions steer cover letter generation. They are turned into
+// the spec sent to the ML API and kept with each generated version.
+type CoverLetterOptions struct {
+	Tone         string   `bson:"tone,omitempty" json:"tone,omitempty"`         // formal | professional | friendly | enthusiastic | confident
+	Length       string   `bson:"length,omitempty" json:"length,omitempty"`     // short | medium | long
+	Emphasis     []string `bson:"emphasis,omitempty" json:"emphasis,omitempty"` // experiences or skills to put forward
+	Language     string   `bson:"language,omitempty" json:"language,omitempty"`
+	Instructions string   `bson:"instructions,omitempty" json:"instructions,omitempty"`
+}
+
+// DocumentVersion is an immutable snapshot of a CV or cover letter.
+type DocumentVersion struct {
+	ID           primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	AuthUserID   string                 `bson:"auth_user_id" json:"-"`
+	JobID        string                 `bson:"job_id" json:"job_id"`
+	Kind         string                 `bson:"kind" json:"kind"`
+	Version      int                    `bson:"version" json:"version"`
+	Author       string                 `bson:"author" json:"author"`
+	Format       string                 `bson:"format" json:"format"`
+	Data         map[string]interface{} `bson:"data" json:"data,omitempty"`
+	RestoredFrom int                    `bson:"restored_from,omitempty" json:"restored_from,omitempty"`
+	Options      *CoverLetterOptions    `bson:"options,omitempty" json:"options,omitempty"` // generation options, cover letters only
+	CreatedAt    time.Time              `bson:"created_at" json:"created_at"`
+}
+
+func CreateDocumentVersionIndexes(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "auth_user_id", Value: 1},
+			{Key: "job_id", Value: 1},
+			{Key: "kind", Value: 1},
+			{Key: "version", Value: -1},
+		},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := collection.Indexes().CreateOne(context.Background(), indexModel)
+	return err
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Kinds of generation jobs
+const (
+	GenerationKindCV          = "cv"
+	GenerationKindCoverLetter = "cover_letter"
+	GenerationKindExternal    = "external_cv_cl" // CV and cover letter for an external job
+	GenerationKindJobResearch = "job_research"
+	// Another take on an existing cover letter, charged as partial credit
+	GenerationKindCoverLetterRegeneration = "cover_letter_regeneration"
+)
+
+// Generation job statuses
+const (
+	GenerationStatusQueued    = "queued"
+	GenerationStatusRunning   = "running"
+	GenerationStatusSucceeded = "succeeded"
+	GenerationStatusFailed    = "failed"
+)
+
+// GenerationJobRetention is how long finished jobs, and their results, are kept.
+const GenerationJobRetention = 7 * 24 * time.Hour
+
+// GenerationJob is a queued call to the ML service. The handler stores the
+// request payloads; a worker runs them, saves the documents and records the
+// outcome for clients polling or streaming the job.
+type GenerationJob struct {
+	ID          primitive.ObjectID     `bson:"_id,omitempty" json:"id"`
+	AuthUserID  string                 `bson:"auth_user_id" json:"-"`
+	JobID       string                 `bson:"job_id" json:"job_id"`
+	Kind        string                 `bson:"kind" json:"kind"`
+	Source      string                 `bson:"source,omitempty" json:"-"`   // internal or external job
+	UsageID     primitive.ObjectID     `bson:"usage_id,omitempty" json:"-"` // ledger entry of the quota it holds
+	Status      string                 `bson:"status" json:"status"`
+	Active      bool                   `bson:"active,omitempty" json:"-"` // queued or running; one active job per document
+	Attempts    int                    `bson:"attempts" json:"attempts"`
+	Payloads    map[string]interface{} `bson:"payloads,omitempty" json:"-"` // ML request per endpoint, dropped once finished
+	Params      map[string]string      `bson:"params,omitempty" json:"-"`   // formats, language and external job details
+	Options     *CoverLetterOptions    `bson:"options,omitempty" json:"options,omitempty"`
+	Result      map[string]interface{} `bson:"result,omitempty" json:"result,omitempty"`
+	Error       string                 `bson:"error,omitempty" json:"error,omitempty"`
+	Issue       string                 `bson:"issue,omitempty" json:"issue,omitempty"`
+	CallbackURL string                 `bson:"callback_url,omitempty" json:"callback_url,omitempty"`
+	CallbackKey string                 `bson:"callback_key,omitempty" json:"-"`
+	CallbackAt  *time.Time             `bson:"callback_at,omitempty" json:"callback_at,omitempty"`
+
+	RunAfter   time.Time  `bson:"run_after" json:"-"`
+	LeaseUntil time.Time  `bson:"lease_until,omitempty" json:"-"`
+	CreatedAt  time.Time  `bson:"created_at" json:"created_at"`
+	StartedAt  *time.Time `bson:"started_at,omitempty" json:"started_at,omitempty"`
+	FinishedAt *time.Time `bson:"finished_at,omitempty" json:"finished_at,omitempty"`
+	ExpiresAt  *time.Time `bson:"expires_at,omitempty" json:"-"`
+}
+
+// Finished reports whether the job reached a final status.
+func (j GenerationJob) Finished() bool {
+	return j.Status == GenerationStatusSucceeded || j.Status == GenerationStatusFailed
+}
+
+func CreateGenerationJobIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "run_after", Value: 1}}},
+		{Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{
+			Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_id", Value: 1}, {Key: "kind", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("unique_active_generation").
+				SetPartialFilterExpression(bson.M{"active": true}),
+		},
+		{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InterviewEvent is a scheduled interview for an application.
+// StartsAt is stored in UTC; Timezone is the zone it was entered in.
+type InterviewEvent struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthUserID      string             `bson:"auth_user_id" json:"auth_user_id"`
+	JobID           string             `bson:"job_id" json:"job_id"`
+	Round           int                `bson:"round" json:"round"`
+	Title           string             `bson:"title" json:"title"`
+	Company         string             `bson:"company,omitempty" json:"company,omitempty"`
+	StartsAt        time.Time          `bson:"starts_at" json:"starts_at"`
+	DurationMinutes int                `bson:"duration_minutes" json:"duration_minutes"`
+	Timezone        string             `bson:"timezone" json:"timezone"`
+	Interviewer     string             `bson:"interviewer,omitempty" json:"interviewer,omitempty"`
+	Location        string             `bson:"location,omitempty" json:"location,omitempty"`
+	VideoLink       string             `bson:"video_link,omitempty" json:"video_link,omitempty"`
+	Notes           string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	Cancelled       bool               `bson:"cancelled" json:"cancelled"`
+	ReminderSentAt  *time.Time         `bson:"reminder_sent_at,omitempty" json:"reminder_sent_at,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// EndsAt returns when the interview is expected to finish.
+func (e InterviewEvent) EndsAt() time.Time {
+	return e.StartsAt.Add(time.Duration(e.DurationMinutes) * time.Minute)
+}
+
+func CreateInterviewEventIndexes(collection *mongo.Collection) error {
+	indexModel1 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "starts_at", Value: 1}},
+	}
+	indexModel2 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_id", Value: 1}},
+	}
+	indexModel3 := mongo.IndexModel{
+		Keys: bson.D{{Key: "starts_at", Value: 1}, {Key: "reminder_sent_at", Value: 1}},
+	}
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2, indexModel3})
+	return err
+}
+
+// CalendarFeed holds the secret token of a user's subscribable calendar feed.
+type CalendarFeed struct {
+	AuthUserID string    `bson:"auth_user_id" json:"auth_user_id"`
+	Token      string    `bson:"token" json:"-"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+}
+
+func CreateCalendarFeedIndexes(collection *mongo.Collection) error {
+	indexModel1 := mongo.IndexModel{
+		Keys:    bson.D{{Key: "auth_user_id", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	indexModel2 := mongo.IndexModel{
+		Keys:    bson.D{{Key: "token", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2})
+	return err
+}
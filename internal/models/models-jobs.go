@@ -1,6 +1,7 @@
 package models
 
 import (
+	"RAAS/internal/geo"
 
 	"context"
 	"time"
@@ -17,7 +18,7 @@ type Job struct {
     Title          string `bson:"title" json:"title"`
     Company        string `bson:"company" json:"company"`
     Location       string `bson:"location" json:"location"`
-    PostedDate     string `bson:"posted_date" json:"posted_date"`
+    PostedDate     time.Time `bson:"posted_date" json:"posted_date"`
     Link           string `bson:"link" json:"link"`
     Processed      bool   `bson:"processed" json:"processed"`
     Source         string `bson:"source" json:"source"`
@@ -30,6 +31,26 @@ type Job struct {
 	// New Fields
 	JobLang		   string `bson:"job_language" json:"job_language"`
 	JobTitle	   string `bson:"job_title" json:"job_title"`
+
+	// Lifecycle
+	ExpiresAt	   *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	IsActive	   bool       `bson:"is_active" json:"is_active"`
+
+	// Geocoded from Location against the bundled gazetteer
+	GeoLocation	   *geo.Point `bson:"geo_location,omitempty" json:"geo_location,omitempty"`
+	WorkMode	   string     `bson:"work_mode,omitempty" json:"work_mode,omitempty"` // onsite | hybrid | remote
+
+	// Canonical skills found in the posting, see skills.TagPendingJobs
+	SkillIDs	   []string   `bson:"skill_ids,omitempty" json:"skill_ids,omitempty"`
+}
+
+// DefaultJobLifetimeDays is used to derive expires_at for jobs ingested without one.
+const DefaultJobLifetimeDays = 30
+
+// ActiveJobFilter matches jobs that have not been closed. Documents written
+// before is_active existed are treated as active.
+func ActiveJobFilter() bson.M {
+	return bson.M{"is_active": bson.M{"$ne": false}}
 }
 
 func CreateJobIndexes(collection *mongo.Collection) error {
@@ -68,13 +89,36 @@ func CreateJobIndexes(collection *mongo.Collection) error {
 		Options: options.Index().SetUnique(false),      // Not unique
 	}
 
+	// Index for the expiry worker (active jobs ordered by expires_at)
+	expiryIndex := mongo.IndexModel{
+		Keys:    bson.D{{Key: "is_active", Value: 1}, {Key: "expires_at", Value: 1}},
+		Options: options.Index().SetUnique(false),
+	}
+
 	// Create indexes
 	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
-		jobIdIndex, jobTypeIndex, selectedCountIndex, jobTitleIndex, jobLangIndex ,postedDateIndex,
+		jobIdIndex, jobTypeIndex, selectedCountIndex, jobTitleIndex, jobLangIndex ,postedDateIndex, expiryIndex,
+		JobTextIndex(), geo.Index2dSphere("geo_location"),
 	})
 	return err
 }
 
+// JobTextIndex is the full-text index used by job search. Title matches weigh
+// more than skills, which weigh more than the description.
+func JobTextIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "job_description", Value: "text"},
+			{Key: "skills", Value: "text"},
+		},
+		Options: options.Index().
+			SetName("job_search_text").
+			SetWeights(bson.M{"title": 10, "skills": 5, "job_description": 1}).
+			SetDefaultLanguage("none"),
+	}
+}
+
 type ExternalJob struct {
     JobID         string    `bson:"job_id" json:"job_id"`
     Title         string    `bson:"title" json:"title"`
@@ -84,6 +128,29 @@ type ExternalJob struct {
     PostedDate    time.Time `bson:"posted_date" json:"posted_date"`
 }
 
+// AsJob maps an external job onto the Job schema. External jobs have no
+// link, location or listed skills; their skills are found in the description.
+func (e ExternalJob) AsJob() Job {
+	return Job{
+		JobID:          e.JobID,
+		Title:          e.Title,
+		JobTitle:       e.Title,
+		Company:        e.Company,
+		JobDescription: e.Description,
+		JobLang:        e.JobLanguage,
+		PostedDate:     e.PostedDate,
+		IsActive:       true,
+	}
+}
+
+// ResolvedJob is a job from either jobs or external_jobs in the Job schema.
+// Origin is ApplicationSourceInternal or ApplicationSourceExternal; Job.Source
+// keeps naming the board an internal job was collected from.
+type ResolvedJob struct {
+	Job    `bson:",inline"`
+	Origin string `bson:"origin" json:"origin"`
+}
+
 
 type MatchScore struct {
     AuthUserID string             `json:"auth_user_id" bson:"auth_user_id"`
@@ -115,6 +182,7 @@ type CoverLetterData struct {
     JobID       string                 	`bson:"job_id" json:"job_id"`
     CLData      map[string]interface{} 	`bson:"cl_data" json:"cl_data"`
 	ClFormat	string					`bson:"cl_format" json:"cl_format"`
+	Version		int						`bson:"version,omitempty" json:"version,omitempty"` // current DocumentVersion
 }
 
 func CreateCoverLetterIndexes(collection *mongo.Collection) error {
@@ -138,6 +206,7 @@ type CVData struct {
     JobID      		string                 		`bson:"job_id" json:"job_id"`
     CVData     		map[string]interface{} 	  	`bson:"cv_data" json:"cv_data"`
 	CvFormat		string						`bson:"cv_format" json:"cv_format"`
+	Version			int							`bson:"version,omitempty" json:"version,omitempty"` // current DocumentVersion
 }
 
 func CreateCVIndexes(collection *mongo.Collection) error {
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GenerationKindMasterCV generates the master CV in one or more languages.
+const GenerationKindMasterCV = "master_cv"
+
+// MasterCVJobID stands in for the job of master CV generation jobs.
+const MasterCVJobID = "master"
+
+// MasterCVLanguages are the languages a master CV can be generated in, by
+// lowercase name and code.
+var MasterCVLanguages = map[string]string{
+	"english": "English", "en": "English",
+	"german": "German", "de": "German", "deutsch": "German",
+	"french": "French", "fr": "French",
+	"spanish": "Spanish", "es": "Spanish",
+	"italian": "Italian", "it": "Italian",
+	"dutch": "Dutch", "nl": "Dutch",
+}
+
+// DefaultMasterCVLanguages are generated when no language is requested.
+var DefaultMasterCVLanguages = []string{"English", "German"}
+
+// MasterCV is a CV of the seeker in one language that is not tied to a job.
+// Each section remembers the profile data it was generated from, so
+// regeneration only replaces sections whose source changed and keeps the
+// seeker's edits of the others.
+type MasterCV struct {
+	ID          primitive.ObjectID         `bson:"_id,omitempty" json:"id"`
+	AuthUserID  string                     `bson:"auth_user_id" json:"-"`
+	Language    string                     `bson:"language" json:"language"`
+	Format      string                     `bson:"cv_format,omitempty" json:"cv_format,omitempty"`
+	Sections    map[string]MasterCVSection `bson:"sections" json:"sections"`
+	GeneratedAt time.Time                  `bson:"generated_at" json:"generated_at"`
+	UpdatedAt   time.Time                  `bson:"updated_at" json:"updated_at"`
+}
+
+// MasterCVSection is one section of a master CV with its translation memory.
+type MasterCVSection struct {
+	Content    interface{} `bson:"content" json:"content"`
+	SourceHash string      `bson:"source_hash" json:"-"` // hash of the profile data it was generated from
+	Edited     bool        `bson:"edited" json:"edited"` // changed by the seeker; kept while the source is unchanged
+	UpdatedAt  time.Time   `bson:"updated_at" json:"updated_at"`
+}
+
+// Data returns the CV content keyed by section, as the CV API returns it.
+func (m MasterCV) Data() map[string]interface{} {
+	data := make(map[string]interface{}, len(m.Sections))
+	for key, s := range m.Sections {
+		data[key] = s.Content
+	}
+	return data
+}
+
+func CreateMasterCVIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "auth_user_id", Value: 1}, {Key: "language", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,511 @@
+package models
+
+import (
+	"RAAS/internal/geo"
+	"RAAS/internal/skills"
+
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Migration is a one-off data fix applied once per database.
+type Migration struct {
+	ID  string
+	Run func(ctx context.Context, db *mongo.Database) error
+}
+
+// AppliedMigration records a migration that has already run.
+type AppliedMigration struct {
+	ID        string    `bson:"_id" json:"id"`
+	AppliedAt time.Time `bson:"applied_at" json:"applied_at"`
+}
+
+// Ordered list of migrations. Append only; never reorder or rename IDs.
+var migrations = []Migration{
+	{ID: "2026_10_jobs_posted_date_to_date", Run: migrateJobPostedDates},
+	{ID: "2026_10_jobs_text_index", Run: migrateJobTextIndex},
+	{ID: "2026_10_jobs_geocode", Run: migrateJobGeocode},
+	{ID: "2026_10_job_alert_indexes", Run: migrateJobAlertIndexes},
+	{ID: "2026_10_cursor_pagination_indexes", Run: migrateCursorPaginationIndexes},
+	{ID: "2026_10_application_pipeline_statuses", Run: migrateApplicationStatuses},
+	{ID: "2026_10_interview_indexes", Run: migrateInterviewIndexes},
+	{ID: "2026_10_application_attachment_indexes", Run: migrateApplicationAttachmentIndexes},
+	{ID: "2026_10_saved_job_details", Run: migrateSavedJobDetails},
+	{ID: "2026_10_onboarding_flow_timelines", Run: migrateOnboardingTimelines},
+	{ID: "2026_10_skill_taxonomy", Run: migrateSkillTaxonomy},
+	{ID: "2026_10_typed_seeker_sections", Run: migrateSeekerSections},
+	{ID: "2026_10_resume_import_indexes", Run: migrateResumeImportIndexes},
+	{ID: "2026_10_document_versions", Run: migrateDocumentVersions},
+	{ID: "2026_10_generation_jobs", Run: migrateGenerationJobIndexes},
+	{ID: "2026_10_usage_ledger", Run: migrateUsageLedgerIndexes},
+	{ID: "2026_10_usage_ledger_resource_reservations", Run: migrateUsageReservationIndex},
+	{ID: "2026_10_master_cv_indexes", Run: migrateMasterCVIndexes},
+	{ID: "2026_10_ats_analyses", Run: migrateATSAnalysisIndexes},
+}
+
+// RunMigrations applies every migration not yet recorded in the migrations collection.
+func RunMigrations(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	applied := db.Collection(CollectionMigrations)
+	for _, m := range migrations {
+		count, err := applied.CountDocuments(ctx, bson.M{"_id": m.ID})
+		if err != nil {
+			return fmt.Errorf("checking migration %s: %w", m.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		log.Printf("🔧 Running migration %s", m.ID)
+		if err := m.Run(ctx, db); err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+		if _, err := applied.InsertOne(ctx, AppliedMigration{ID: m.ID, AppliedAt: time.Now()}); err != nil {
+			return fmt.Errorf("recording migration %s: %w", m.ID, err)
+		}
+		log.Printf("✅ Migration %s applied", m.ID)
+	}
+	return nil
+}
+
+func migrateJobPostedDates(ctx context.Context, db *mongo.Database) error {
+	_, err := NormalizeJobLifecycleFields(ctx, db.Collection(CollectionJobs))
+	return err
+}
+
+func migrateJobTextIndex(ctx context.Context, db *mongo.Database) error {
+	_, err := db.Collection(CollectionJobs).Indexes().CreateOne(ctx, JobTextIndex())
+	return err
+}
+
+func migrateJobGeocode(ctx context.Context, db *mongo.Database) error {
+	jobs := db.Collection(CollectionJobs)
+	if _, err := jobs.Indexes().CreateOne(ctx, geo.Index2dSphere("geo_location")); err != nil {
+		return err
+	}
+	if _, err := db.Collection(CollectionSeekers).Indexes().CreateOne(ctx, geo.Index2dSphere("personal_info.geo_location")); err != nil {
+		return err
+	}
+	_, err := geo.GeocodePendingJobs(ctx, jobs)
+	return err
+}
+
+func migrateJobAlertIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := CreateSavedSearchIndexes(db.Collection(CollectionSavedSearches)); err != nil {
+		return err
+	}
+	return CreateJobAlertDeliveryIndexes(db.Collection(CollectionJobAlertDeliveries))
+}
+
+// migrateCursorPaginationIndexes adds the compound (filter, sort field, _id)
+// indexes backing keyset pagination on list endpoints.
+func migrateCursorPaginationIndexes(ctx context.Context, db *mongo.Database) error {
+	indexes := map[string]bson.D{
+		"match_scores":              {{Key: "auth_user_id", Value: 1}, {Key: "match_score", Value: -1}, {Key: "_id", Value: -1}},
+		"selected_job_applications": {{Key: "auth_user_id", Value: 1}, {Key: "selected_date", Value: -1}, {Key: "_id", Value: -1}},
+		"announcements":             {{Key: "is_active", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+		"exam_results":              {{Key: "auth_user_id", Value: 1}, {Key: "submitted_at", Value: -1}, {Key: "_id", Value: -1}},
+	}
+	for coll, keys := range indexes {
+		if _, err := db.Collection(coll).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys}); err != nil {
+			return fmt.Errorf("%s: %w", coll, err)
+		}
+	}
+	return nil
+}
+
+// migrateApplicationStatuses maps legacy application statuses onto the
+// pipeline and seeds each application's history with its current status.
+func migrateApplicationStatuses(ctx context.Context, db *mongo.Database) error {
+	apps := db.Collection(CollectionSelectedJobApps)
+	for legacy, status := range legacyApplicationStatuses {
+		if _, err := apps.UpdateMany(ctx,
+			bson.M{"status": legacy},
+			bson.M{"$set": bson.M{"status": status}},
+		); err != nil {
+			return fmt.Errorf("mapping status %s: %w", legacy, err)
+		}
+	}
+
+	_, err := apps.UpdateMany(ctx,
+		bson.M{"status_history": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"status_history": bson.A{bson.M{"to": "$status", "changed_at": "$selected_date"}},
+			}}},
+		},
+	)
+	return err
+}
+
+func migrateInterviewIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := CreateInterviewEventIndexes(db.Collection(CollectionInterviewEvents)); err != nil {
+		return err
+	}
+	return CreateCalendarFeedIndexes(db.Collection(CollectionCalendarFeeds))
+}
+
+func migrateApplicationAttachmentIndexes(ctx context.Context, db *mongo.Database) error {
+	if err := CreateApplicationAttachmentIndexes(db.Collection(CollectionAppAttachments)); err != nil {
+		return err
+	}
+	// Manual entries are de-duplicated by link during CSV import
+	_, err := db.Collection(CollectionSelectedJobApps).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_link", Value: 1}},
+	})
+	return err
+}
+
+// NormalizeJobLifecycleFields converts legacy "YYYY-MM-DD" posted_date strings
+// into dates and backfills is_active / expires_at. It is idempotent, so the
+// expiry worker also runs it to catch jobs ingested in the old format.
+func NormalizeJobLifecycleFields(ctx context.Context, coll *mongo.Collection) (int64, error) {
+	var modified int64
+
+	// 1️⃣ posted_date string -> date (original kept in legacy_posted_date)
+	res, err := coll.UpdateMany(ctx,
+		bson.M{"posted_date": bson.M{"$type": "string"}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"legacy_posted_date": "$posted_date",
+				"posted_date": bson.M{"$dateFromString": bson.M{
+					"dateString": "$posted_date",
+					"onError":    nil,
+					"onNull":     nil,
+				}},
+			}}},
+		},
+	)
+	if err != nil {
+		return modified, fmt.Errorf("converting posted_date: %w", err)
+	}
+	modified += res.ModifiedCount
+
+	// 2️⃣ is_active defaults to true
+	res, err = coll.UpdateMany(ctx,
+		bson.M{"is_active": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"is_active": true}},
+	)
+	if err != nil {
+		return modified, fmt.Errorf("backfilling is_active: %w", err)
+	}
+	modified += res.ModifiedCount
+
+	// 3️⃣ expires_at defaults to posted_date + DefaultJobLifetimeDays
+	lifetime := int64(DefaultJobLifetimeDays) * 24 * int64(time.Hour/time.Millisecond)
+	res, err = coll.UpdateMany(ctx,
+		bson.M{
+			"expires_at":  bson.M{"$exists": false},
+			"posted_date": bson.M{"$type": "date"},
+		},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"expires_at": bson.M{"$add": bson.A{"$posted_date", lifetime}},
+			}}},
+		},
+	)
+	if err != nil {
+		return modified, fmt.Errorf("backfilling expires_at: %w", err)
+	}
+	modified += res.ModifiedCount
+
+	return modified, nil
+}
+
+// migrateSavedJobDetails backfills saved_at from the document's ObjectID and
+// indexes saved jobs for date ordering and tag filters.
+func migrateSavedJobDetails(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection(CollectionSavedJobs)
+	_, err := coll.UpdateMany(ctx,
+		bson.M{"saved_at": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"saved_at": bson.M{"$toDate": "$_id"},
+				"priority": SavedJobPriorityNormal,
+			}}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("backfilling saved_at: %w", err)
+	}
+	return CreateSavedJobApplicationIndexes(coll)
+}
+
+// legacyTimelineSteps maps the boolean timeline fields onto onboarding step keys.
+var legacyTimelineSteps = map[string]string{
+	"personal_info":    OnboardingStepPersonalInfo,
+	"work_experiences": OnboardingStepWorkExperiences,
+	"academics":        OnboardingStepAcademics,
+	"past_projects":    OnboardingStepPastProjects,
+	"certificates":     OnboardingStepCertificates,
+	"languages":        OnboardingStepLanguages,
+	"job_titles":       OnboardingStepJobTitles,
+	"key_skills":       OnboardingStepKeySkills,
+}
+
+// migrateOnboardingTimelines converts the per-step <step>_completed and
+// <step>_required booleans into completed_steps on the default flow. The
+// required flags were the same for every user, so the default flow carries them.
+func migrateOnboardingTimelines(ctx context.Context, db *mongo.Database) error {
+	if err := CreateOnboardingFlowIndexes(db.Collection(CollectionOnboardingFlows)); err != nil {
+		return err
+	}
+
+	completed := bson.A{}
+	unset := bson.A{}
+	for legacy, step := range legacyTimelineSteps {
+		completed = append(completed, bson.M{"$cond": bson.A{
+			bson.M{"$eq": bson.A{"$" + legacy + "_completed", true}},
+			bson.A{step},
+			bson.A{},
+		}})
+		unset = append(unset, legacy+"_completed", legacy+"_required")
+	}
+
+	res, err := db.Collection(CollectionUserEntryTimelines).UpdateMany(ctx,
+		bson.M{"completed_steps": bson.M{"$exists": false}},
+		mongo.Pipeline{
+			{{Key: "$set", Value: bson.M{
+				"flow":            DefaultOnboardingFlowKey,
+				"completed_steps": bson.M{"$concatArrays": completed},
+			}}},
+			{{Key: "$unset", Value: unset}},
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("converting timelines: %w", err)
+	}
+	log.Printf("🧭 Converted %d entry timelines to onboarding flows", res.ModifiedCount)
+	return nil
+}
+
+// migrateSkillTaxonomy indexes job skill tags and rewrites seekers' key skills
+// to their canonical names. Jobs are tagged by the job expiry worker.
+func migrateSkillTaxonomy(ctx context.Context, db *mongo.Database) error {
+	if _, err := db.Collection(CollectionJobs).Indexes().CreateOne(ctx, skills.IndexSkillIDs()); err != nil {
+		return fmt.Errorf("jobs: %w", err)
+	}
+
+	seekers := db.Collection(CollectionSeekers)
+	cursor, err := seekers.Find(ctx,
+		bson.M{"key_skills.0": bson.M{"$exists": true}},
+		options.Find().SetProjection(bson.M{"auth_user_id": 1, "key_skills": 1}),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	taxonomy := skills.Default()
+	var updates []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var s struct {
+			AuthUserID string   `bson:"auth_user_id"`
+			KeySkills  []string `bson:"key_skills"`
+		}
+		if err := cursor.Decode(&s); err != nil {
+			continue
+		}
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"auth_user_id": s.AuthUserID}).
+			SetUpdate(bson.M{"$set": bson.M{"key_skills": taxonomy.Canonicalize(s.KeySkills)}}))
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+	_, err = seekers.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+// seekerSections maps each profile section to a constructor for its entry type.
+var seekerSections = map[string]func() interface{}{
+	"work_experiences": func() interface{} { return &WorkExperience{} },
+	"academics":        func() interface{} { return &Academics{} },
+	"past_projects":    func() interface{} { return &PastProject{} },
+	"certificates":     func() interface{} { return &Certificate{} },
+	"languages":        func() interface{} { return &Language{} },
+}
+
+// migrateSeekerSections gives every profile section entry an _id, rewrites it
+// in the shape of its typed struct and installs the seekers schema validator.
+// Entries that cannot be typed are moved to legacy_profile_entries.
+func migrateSeekerSections(ctx context.Context, db *mongo.Database) error {
+	seekers := db.Collection(CollectionSeekers)
+	projection := bson.M{"auth_user_id": 1}
+	for section := range seekerSections {
+		projection[section] = 1
+	}
+	cursor, err := seekers.Find(ctx, bson.M{}, options.Find().SetProjection(projection))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	now := time.Now()
+	var updates []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		set := bson.M{}
+		for section, newEntry := range seekerSections {
+			raw, _ := doc[section].(bson.A)
+			entries := make([]interface{}, 0, len(raw))
+			var invalid []interface{}
+			for _, e := range raw {
+				entry, ok := e.(bson.M)
+				if !ok {
+					invalid = append(invalid, e)
+					continue
+				}
+				typed := newEntry()
+				if err := normalizeSectionEntry(entry, typed, now); err != nil {
+					log.Printf("⚠️ Setting aside %s entry of seeker %v: %v", section, doc["auth_user_id"], err)
+					invalid = append(invalid, entry)
+					continue
+				}
+				entries = append(entries, typed)
+			}
+			set[section] = entries
+			// Kept for manual review; they would stop the seeker from decoding
+			if len(invalid) > 0 {
+				set["legacy_profile_entries."+section] = invalid
+			}
+		}
+		updates = append(updates, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": doc["_id"]}).
+			SetUpdate(bson.M{"$set": set}))
+	}
+	if err := cursor.Err(); err != nil {
+		return err
+	}
+	if len(updates) > 0 {
+		if _, err := seekers.BulkWrite(ctx, updates, options.BulkWrite().SetOrdered(false)); err != nil {
+			return err
+		}
+	}
+
+	return ApplySeekerSchema(ctx, db)
+}
+
+// normalizeSectionEntry decodes a legacy section entry into typed, giving it
+// an _id and timestamps and parsing dates stored as strings.
+func normalizeSectionEntry(entry bson.M, typed interface{}, now time.Time) error {
+	if _, ok := entry["_id"].(primitive.ObjectID); !ok {
+		entry["_id"] = primitive.NewObjectID()
+	}
+	delete(entry, "auth_user_id")
+	for k, v := range entry {
+		if s, ok := v.(string); ok && (strings.HasSuffix(k, "_date") || strings.HasSuffix(k, "_at")) {
+			if t, err := time.Parse(time.RFC3339, s); err == nil {
+				entry[k] = t
+			}
+		}
+	}
+	for _, k := range []string{"created_at", "updated_at"} {
+		if _, ok := entry[k]; !ok {
+			entry[k] = now
+		}
+	}
+
+	data, err := bson.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return bson.Unmarshal(data, typed)
+}
+
+func migrateResumeImportIndexes(ctx context.Context, db *mongo.Database) error {
+	return CreateResumeImportIndexes(db.Collection(CollectionResumeImports))
+}
+
+// migrateDocumentVersions records every existing CV and cover letter as
+// version 1 of its history, authored by the generator.
+func migrateDocumentVersions(ctx context.Context, db *mongo.Database) error {
+	if err := CreateDocumentVersionIndexes(db.Collection(CollectionDocumentVersions)); err != nil {
+		return err
+	}
+
+	sources := []struct{ collection, kind, data, format string }{
+		{CollectionCV, DocumentKindCV, "$cv_data", "$cv_format"},
+		{CollectionCoverLetters, DocumentKindCoverLetter, "$cl_data", "$cl_format"},
+	}
+	for _, src := range sources {
+		coll := db.Collection(src.collection)
+		unversioned := bson.M{"version": bson.M{"$exists": false}}
+		cursor, err := coll.Aggregate(ctx, mongo.Pipeline{
+			{{Key: "$match", Value: unversioned}},
+			{{Key: "$project", Value: bson.M{
+				"_id":          0,
+				"auth_user_id": 1,
+				"job_id":       1,
+				"kind":         src.kind,
+				"version":      bson.M{"$literal": 1},
+				"author":       VersionAuthorGenerator,
+				"format":       src.format,
+				"data":         src.data,
+				"created_at":   bson.M{"$toDate": "$_id"},
+			}}},
+			{{Key: "$merge", Value: bson.M{
+				"into":           CollectionDocumentVersions,
+				"on":             bson.A{"auth_user_id", "job_id", "kind", "version"},
+				"whenMatched":    "keepExisting",
+				"whenNotMatched": "insert",
+			}}},
+		})
+		if err != nil {
+			return fmt.Errorf("recording %s versions: %w", src.kind, err)
+		}
+		cursor.Close(ctx)
+
+		if _, err := coll.UpdateMany(ctx, unversioned, bson.M{"$set": bson.M{"version": 1}}); err != nil {
+			return fmt.Errorf("numbering %s documents: %w", src.kind, err)
+		}
+	}
+	return nil
+}
+
+func migrateGenerationJobIndexes(ctx context.Context, db *mongo.Database) error {
+	return CreateGenerationJobIndexes(db.Collection(CollectionGenerationJobs))
+}
+
+func migrateUsageLedgerIndexes(ctx context.Context, db *mongo.Database) error {
+	return CreateUsageLedgerIndexes(db.Collection(CollectionUsageLedger))
+}
+
+// migrateUsageReservationIndex replaces the open reservation index with one
+// per resource, so a cover letter regeneration can be reserved while the
+// application's quota is still held.
+func migrateUsageReservationIndex(ctx context.Context, db *mongo.Database) error {
+	coll := db.Collection(CollectionUsageLedger)
+	if _, err := coll.Indexes().DropOne(ctx, "unique_open_reservation"); err != nil {
+		var cmdErr mongo.CommandError
+		if !errors.As(err, &cmdErr) || cmdErr.Name != "IndexNotFound" {
+			return err
+		}
+	}
+	return CreateUsageLedgerIndexes(coll)
+}
+
+func migrateMasterCVIndexes(ctx context.Context, db *mongo.Database) error {
+	return CreateMasterCVIndexes(db.Collection(CollectionMasterCVs))
+}
+
+func migrateATSAnalysisIndexes(ctx context.Context, db *mongo.Database) error {
+	return CreateATSAnalysisIndexes(db.Collection(CollectionATSAnalyses))
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Onboarding step keys completed by the profile data-entry handlers
+const (
+	OnboardingStepPersonalInfo    = "personal_info"
+	OnboardingStepWorkExperiences = "work_experiences"
+	OnboardingStepAcademics       = "academics"
+	OnboardingStepPastProjects    = "past_projects"
+	OnboardingStepCertificates    = "certificates"
+	OnboardingStepLanguages       = "languages"
+	OnboardingStepJobTitles       = "preferred_job_titles"
+	OnboardingStepKeySkills       = "key_skills"
+)
+
+// legacyStepNames are the next_step values of the entry timeline for steps
+// whose key changed when onboarding flows were introduced.
+var legacyStepNames = map[string]string{
+	OnboardingStepPersonalInfo: "personal_infos",
+}
+
+// LegacyStepName returns the name the entry timeline reports for a step key,
+// so existing clients keep recognizing it.
+func LegacyStepName(key string) string {
+	if name, ok := legacyStepNames[key]; ok {
+		return name
+	}
+	return key
+}
+
+// DefaultOnboardingFlowKey is the flow used when no other flow applies.
+const DefaultOnboardingFlowKey = "default"
+
+// OnboardingStep is one step of an onboarding flow.
+type OnboardingStep struct {
+	Key      string `bson:"key" json:"key"`
+	Required bool   `bson:"required" json:"required"`
+	// Manual steps have no profile data behind them and are completed
+	// through the step completion endpoint, e.g. a product tour.
+	Manual bool `bson:"manual,omitempty" json:"manual,omitempty"`
+}
+
+// OnboardingFlow is an ordered list of onboarding steps. Flows may target a
+// subscription tier and/or an experiment, which is rolled out to a share of
+// new users.
+type OnboardingFlow struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Key            string             `bson:"key" json:"key"`
+	Tier           string             `bson:"tier,omitempty" json:"tier,omitempty"`
+	Experiment     string             `bson:"experiment,omitempty" json:"experiment,omitempty"`
+	RolloutPercent int                `bson:"rollout_percent,omitempty" json:"rollout_percent,omitempty"` // experiments only, 0-100
+	Steps          []OnboardingStep   `bson:"steps" json:"steps"`
+	IsActive       bool               `bson:"is_active" json:"is_active"`
+	CreatedAt      time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt      time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// DefaultOnboardingFlow is the built-in flow, used when the database has no
+// active "default" flow.
+func DefaultOnboardingFlow() OnboardingFlow {
+	return OnboardingFlow{
+		Key: DefaultOnboardingFlowKey,
+		Steps: []OnboardingStep{
+			{Key: OnboardingStepPersonalInfo, Required: true},
+			{Key: OnboardingStepWorkExperiences, Required: false},
+			{Key: OnboardingStepAcademics, Required: true},
+			{Key: OnboardingStepPastProjects, Required: false},
+			{Key: OnboardingStepCertificates, Required: false},
+			{Key: OnboardingStepLanguages, Required: true},
+			{Key: OnboardingStepJobTitles, Required: true},
+			{Key: OnboardingStepKeySkills, Required: true},
+		},
+		IsActive: true,
+	}
+}
+
+// Step returns the flow's step with the given key.
+func (f OnboardingFlow) Step(key string) (OnboardingStep, bool) {
+	for _, s := range f.Steps {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return OnboardingStep{}, false
+}
+
+// NextStep returns the first required step not in completed, or "" when
+// every required step is done.
+func (f OnboardingFlow) NextStep(completed []string) string {
+	done := make(map[string]bool, len(completed))
+	for _, k := range completed {
+		done[k] = true
+	}
+	for _, s := range f.Steps {
+		if s.Required && !done[s.Key] {
+			return s.Key
+		}
+	}
+	return ""
+}
+
+func CreateOnboardingFlowIndexes(collection *mongo.Collection) error {
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	}
+	_, err := collection.Indexes().CreateOne(context.Background(), indexModel)
+	return err
+}
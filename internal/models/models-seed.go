@@ -4,35 +4,41 @@ import (
     "context"
     "log"
     "strings"
+    "time"
 
     "go.mongodb.org/mongo-driver/bson"
     "go.mongodb.org/mongo-driver/mongo"
 )
 
 
+func seedDate(s string) time.Time {
+    t, _ := time.Parse("2006-01-02", s)
+    return t
+}
+
 func SeedJobs(collection *mongo.Collection) {
     jobs := []Job{
-        {JobID: "L001", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: "2024-04-01", Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
-        {JobID: "L002", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: "2024-04-02", Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
-        {JobID: "L003", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L004", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L005", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L006", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L007", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L008", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L009", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L010", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L011", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: "2024-04-02", Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
-        {JobID: "L012", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: "2024-04-01", Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
-        {JobID: "L013", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L014", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: "2024-04-02", Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
-        {JobID: "L015", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: "2024-04-01", Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
-        {JobID: "L016", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L017", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: "2024-04-02", Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
-        {JobID: "L018", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: "2024-04-01", Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
-        {JobID: "L019", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: "2024-04-03", Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
-        {JobID: "L020", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: "2024-04-02", Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
-        {JobID: "L021", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: "2024-04-01", Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
+        {JobID: "L001", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: seedDate("2024-04-01"), Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
+        {JobID: "L002", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: seedDate("2024-04-02"), Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
+        {JobID: "L003", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L004", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L005", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L006", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L007", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L008", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L009", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L010", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L011", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: seedDate("2024-04-02"), Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
+        {JobID: "L012", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: seedDate("2024-04-01"), Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
+        {JobID: "L013", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L014", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: seedDate("2024-04-02"), Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
+        {JobID: "L015", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: seedDate("2024-04-01"), Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
+        {JobID: "L016", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L017", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: seedDate("2024-04-02"), Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
+        {JobID: "L018", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: seedDate("2024-04-01"), Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
+        {JobID: "L019", Title: "Software Engineer", Company: "Meta", Location: "Hamburg", PostedDate: seedDate("2024-04-03"), Link: "https://linkedin.com/jobs/3", Processed: true, Source: "LinkedIn", JobDescription: "Develop backend services with Go and microservices architecture.", JobType: "Remote", Skills: "Go, gRPC, PostgreSQL, Docker", JobLink: "https://apply.linkedin.com/job/3"},
+        {JobID: "L020", Title: "DevOps Engineer", Company: "Google", Location: "Munich", PostedDate: seedDate("2024-04-02"), Link: "https://linkedin.com/jobs/2", Processed: true, Source: "LinkedIn", JobDescription: "Join our DevOps team to manage CI/CD pipelines and cloud infrastructure.", JobType: "Full-time", Skills: "CI/CD, Jenkins, AWS, Docker, Kubernetes", JobLink: "https://apply.linkedin.com/job/2"},
+        {JobID: "L021", Title: "Software Engineer", Company: "LinkedIn", Location: "Berlin", PostedDate: seedDate("2024-04-01"), Link: "https://linkedin.com/jobs/1", Processed: true, Source: "LinkedIn", JobDescription: "We are looking for a skilled Software Engineer to build scalable systems.", JobType: "Full-time", Skills: "Go, REST, Microservices, Docker", JobLink: "https://apply.linkedin.com/job/1"},
     }
 
 
@@ -58,6 +64,8 @@ func SeedJobs(collection *mongo.Collection) {
             "job_type":       job.JobType,
             "skills":         job.Skills,
             "job_link":       job.JobLink,
+            "is_active":      true,
+            "expires_at":     job.PostedDate.AddDate(0, 0, DefaultJobLifetimeDays),
         }
 
         // Debug: Print the BSON document for update
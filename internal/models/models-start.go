@@ -34,6 +34,20 @@ const (
 	CollectionQuestions				= "questions"
 	CollectionResults				= "exam_results"
 	CollectionAnnouncements		   	= "announcements"
+	CollectionMigrations			= "migrations"
+	CollectionSavedSearches			= "saved_searches"
+	CollectionJobAlertDeliveries	= "job_alert_deliveries"
+	CollectionInterviewEvents		= "interview_events"
+	CollectionCalendarFeeds			= "calendar_feeds"
+	CollectionAppAttachments		= "application_attachments"
+	CollectionOnboardingFlows		= "onboarding_flows"
+	CollectionSkillTaxonomy			= "skill_taxonomy"
+	CollectionResumeImports			= "resume_imports"
+	CollectionDocumentVersions		= "document_versions"
+	CollectionGenerationJobs		= "generation_jobs"
+	CollectionUsageLedger			= "usage_ledger"
+	CollectionMasterCVs				= "master_cvs"
+	CollectionATSAnalyses			= "ats_analyses"
 	
 )
 
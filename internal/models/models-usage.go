@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Quota resources tracked in the usage ledger
+const (
+	UsageInternalApplication = "internal_application"
+	UsageExternalApplication = "external_application"
+	// Charged as a share of an application of the job's source
+	UsageCoverLetterRegeneration = "cover_letter_regeneration"
+)
+
+// UsageCounters maps each application resource to the seeker field holding
+// what is left of it.
+var UsageCounters = map[string]string{
+	UsageInternalApplication: "internal_application_count",
+	UsageExternalApplication: "external_application_count",
+}
+
+// UsageCredits maps each application resource to the seeker field holding
+// the share of an application, in hundredths, left over from partial charges.
+var UsageCredits = map[string]string{
+	UsageInternalApplication: "internal_application_credit",
+	UsageExternalApplication: "external_application_credit",
+}
+
+// ApplicationResource returns the application resource of a job source.
+func ApplicationResource(source string) string {
+	if source == "external" {
+		return UsageExternalApplication
+	}
+	return UsageInternalApplication
+}
+
+// Usage entry statuses. A reservation takes quota when a generation is
+// queued; it is committed when the generation succeeds and released, giving
+// the quota back, when it fails.
+const (
+	UsageReserved  = "reserved"
+	UsageCommitted = "committed"
+	UsageReleased  = "released"
+)
+
+// UsageEntry records quota taken for one generation.
+type UsageEntry struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AuthUserID      string             `bson:"auth_user_id" json:"-"`
+	JobID           string             `bson:"job_id" json:"job_id"`
+	GenerationJobID primitive.ObjectID `bson:"generation_job_id,omitempty" json:"generation_job_id,omitempty"`
+	Kind            string             `bson:"kind" json:"kind"` // generation kind that consumed it
+	Resource        string             `bson:"resource" json:"resource"`
+	Source          string             `bson:"source,omitempty" json:"source,omitempty"` // internal or external job
+	Amount          int                `bson:"amount" json:"amount"`                     // applications taken from the counter
+	Credit          int                `bson:"credit,omitempty" json:"credit,omitempty"` // hundredths of an application charged as partial credit
+	Status          string             `bson:"status" json:"status"`
+	Reason          string             `bson:"reason,omitempty" json:"reason,omitempty"` // why a reservation was released
+	PeriodStart     time.Time          `bson:"period_start" json:"period_start"`         // billing period the quota came from
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	SettledAt       *time.Time         `bson:"settled_at,omitempty" json:"settled_at,omitempty"`
+}
+
+func CreateUsageLedgerIndexes(collection *mongo.Collection) error {
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		{Keys: bson.D{{Key: "generation_job_id", Value: 1}}},
+		{
+			// One open reservation per application and resource: generations
+			// queued for a job while another holds its quota do not take it again
+			Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "job_id", Value: 1}, {Key: "resource", Value: 1}},
+			Options: options.Index().SetUnique(true).SetName("unique_open_reservation_resource").
+				SetPartialFilterExpression(bson.M{"status": UsageReserved}),
+		},
+	})
+	return err
+}
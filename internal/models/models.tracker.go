@@ -11,38 +11,18 @@ import (
 )
 
 
+// UserEntryTimeline tracks a user's progress through their onboarding flow.
+// Order and required steps come from the flow definition.
 type UserEntryTimeline struct {
 	ID                     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	AuthUserID             string             `bson:"auth_user_id" json:"auth_user_id"`
 
-	// Compulsory steps
-	PersonalInfoCompleted   bool              `bson:"personal_info_completed" json:"personal_info_completed"`
-	PersonalInfoRequired    bool              `bson:"personal_info_required" json:"personal_info_required"`
-
-	AcademicsCompleted      bool              `bson:"academics_completed" json:"academics_completed"`
-	AcademicsRequired       bool              `bson:"academics_required" json:"academics_required"`
-
-	LanguagesCompleted      bool              `bson:"languages_completed" json:"languages_completed"`
-	LanguagesRequired       bool              `bson:"languages_required" json:"languages_required"`
-
-	JobTitlesCompleted      bool              `bson:"job_titles_completed" json:"job_titles_completed"`
-	JobTitlesRequired       bool              `bson:"job_titles_required" json:"job_titles_required"`
-
-	KeySkillsCompleted      bool              `bson:"key_skills_completed" json:"key_skills_completed"`
-	KeySkillsRequired       bool              `bson:"key_skills_required" json:"key_skills_required"`
-
-	// Optional steps
-	WorkExperiencesCompleted bool             `bson:"work_experiences_completed" json:"work_experiences_completed"`
-	WorkExperiencesRequired  bool             `bson:"work_experiences_required" json:"work_experiences_required"`
-
-	PastProjectsCompleted    bool             `bson:"past_projects_completed" json:"past_projects_completed"`
-	PastProjectsRequired     bool             `bson:"past_projects_required" json:"past_projects_required"`
-
-	CertificatesCompleted    bool             `bson:"certificates_completed" json:"certificates_completed"`
-	CertificatesRequired     bool             `bson:"certificates_required" json:"certificates_required"`
+	Flow                   string             `bson:"flow" json:"flow"` // OnboardingFlow key, fixed at sign-up
+	CompletedSteps         []string           `bson:"completed_steps" json:"completed_steps"`
 
 	// Overall
 	Completed                bool             `bson:"completed" json:"completed"`
+	CompletedAt              *time.Time       `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
 
 	CreatedAt                time.Time        `bson:"created_at" json:"created_at"`
 	UpdatedAt                time.Time        `bson:"updated_at" json:"updated_at"`
@@ -71,6 +51,21 @@ type SelectedJobApplication struct {
 	Status					string				`bson:"status" json:"status"`
 	Source 					string				`bson:"source" json:"source"`
 	Company					string				`bson:"company" json:"company"`
+
+	// Manually tracked applications (source "manual") carry their own job details
+	Title					string				`bson:"title,omitempty" json:"title,omitempty"`
+	JobLink					string				`bson:"job_link,omitempty" json:"job_link,omitempty"`
+	Channel					string				`bson:"channel,omitempty" json:"channel,omitempty"` // where the user applied, e.g. "LinkedIn"
+	Attachments				[]ApplicationAttachmentMeta	`bson:"attachments,omitempty" json:"attachments,omitempty"`
+
+	// Pipeline tracking
+	StatusHistory			[]StatusChange		`bson:"status_history,omitempty" json:"status_history"`
+	InterviewRound			int					`bson:"interview_round,omitempty" json:"interview_round,omitempty"`
+	Notes					[]ApplicationNote	`bson:"notes,omitempty" json:"notes"`
+	NextAction				string				`bson:"next_action,omitempty" json:"next_action,omitempty"`
+	NextActionAt			*time.Time			`bson:"next_action_at,omitempty" json:"next_action_at,omitempty"`
+	AppliedAt				*time.Time			`bson:"applied_at,omitempty" json:"applied_at,omitempty"`
+	UpdatedAt				*time.Time			`bson:"updated_at,omitempty" json:"updated_at,omitempty"`
 }
 
 func CreateSelectedJobApplicationIndexes(collection *mongo.Collection) error {
@@ -107,11 +102,39 @@ func CreateSelectedJobApplicationIndexes(collection *mongo.Collection) error {
 }
 
 
+// Saved job priorities
+const (
+	SavedJobPriorityLow    = "low"
+	SavedJobPriorityNormal = "normal"
+	SavedJobPriorityHigh   = "high"
+)
+
+// Saved job warnings, computed when listing
+const (
+	SavedJobWarningRemoved = "removed" // the job no longer exists
+	SavedJobWarningExpired = "expired" // the job was closed
+	SavedJobWarningStale   = "stale"   // posted before the retrieval window
+)
+
 type SavedJob struct {
 	ID                    primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	AuthUserID            string             `bson:"auth_user_id" json:"auth_user_id"`
 	JobID                 string             `bson:"job_id" json:"job_id"`
-	// SavedDate          time.Time          `bson:"savedDate" json:"selected_date"`
+	SavedAt               time.Time          `bson:"saved_at" json:"saved_at"`
+	Notes                 string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	Tags                  []string           `bson:"tags,omitempty" json:"tags"`
+	Folder                string             `bson:"folder,omitempty" json:"folder,omitempty"`
+	Priority              string             `bson:"priority,omitempty" json:"priority"`
+	UpdatedAt             *time.Time         `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+// IsSavedJobPriority reports whether p is a known saved job priority.
+func IsSavedJobPriority(p string) bool {
+	switch p {
+	case SavedJobPriorityLow, SavedJobPriorityNormal, SavedJobPriorityHigh:
+		return true
+	}
+	return false
 }
 
 func CreateSavedJobApplicationIndexes(collection *mongo.Collection) error {
@@ -129,9 +152,17 @@ func CreateSavedJobApplicationIndexes(collection *mongo.Collection) error {
 	indexModel3 := mongo.IndexModel{
 		Keys: bson.D{{Key: "auth_user_id", Value: 1}},
 	}
+
+	indexModel4 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "saved_at", Value: -1}, {Key: "_id", Value: -1}},
+	}
+
+	indexModel5 := mongo.IndexModel{
+		Keys: bson.D{{Key: "auth_user_id", Value: 1}, {Key: "tags", Value: 1}},
+	}
 	
 
-	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2,indexModel3})
+	_, err := collection.Indexes().CreateMany(context.Background(), []mongo.IndexModel{indexModel1, indexModel2,indexModel3,indexModel4,indexModel5})
 	return err
 }
 
@@ -143,6 +174,12 @@ type UserPreferences struct {
 	Language            string             `bson:"language" json:"language"` // "english", "german"
 	Timezone            string             `bson:"timezone" json:"timezone"` // e.g. "Europe/Berlin"
 	CookiePolicy        bool               `bson:"cookie_policy" json:"cookie_policy"`
+
+	// Location preferences used by job retrieval and match scoring
+	PreferredRadiusKm   int                `bson:"preferred_radius_km,omitempty" json:"preferred_radius_km,omitempty"` // 0 = no limit
+	WillingToRelocate   bool               `bson:"willing_to_relocate" json:"willing_to_relocate"`
+	WorkModePreference  string             `bson:"work_mode_preference,omitempty" json:"work_mode_preference,omitempty"` // any | onsite | hybrid | remote
+
 	CreatedAt           time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt           time.Time          `bson:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,626 @@
+package render
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Profile is the seeker data used where the generated JSON lacks it: the
+// header of cover letters and of CVs without contact details.
+type Profile struct {
+	Name     string
+	Title    string
+	Email    string
+	Phone    string
+	City     string
+	LinkedIn string
+	Photo    []byte // JPEG, PNG or GIF
+}
+
+// Document is a CV or cover letter ready for layout.
+type Document struct {
+	Kind     string
+	Header   Header
+	Sections []Section // CV
+	Letter   *Letter   // cover letter
+}
+
+// Header is the name block at the top of the first page.
+type Header struct {
+	Name     string
+	Title    string
+	Contacts []string
+	Photo    []byte
+}
+
+// Section is a titled part of a CV holding text, a list of short items or
+// a list of entries.
+type Section struct {
+	Title   string
+	Text    string
+	Items   []string
+	Entries []Entry
+}
+
+// Entry is one position, degree, project or similar item of a section.
+type Entry struct {
+	Title    string
+	Subtitle string
+	Period   string
+	Lines    []string
+	Bullets  []string
+}
+
+// Letter is the body of a cover letter.
+type Letter struct {
+	Date       string
+	Recipient  []string
+	Subject    string
+	Salutation string
+	Paragraphs []string
+	Closing    string
+	Signature  string
+}
+
+// compact reports whether the section holds short items only, which the
+// sidebar templates move out of the main column.
+func (s Section) compact() bool {
+	return s.Text == "" && len(s.Entries) == 0 && len(s.Items) > 0
+}
+
+// Keys are matched case-insensitively after removing '_', '-' and spaces.
+var (
+	wrapperKeys   = []string{"cv", "cvdata", "resume", "coverletter", "cldata", "letter", "data", "result", "output"}
+	headerKeys    = []string{"personalinfo", "personaldetails", "userdetails", "contactinfo", "contact", "header", "basics"}
+	nameKeys      = []string{"name", "fullname", "candidatename", "sendername"}
+	titleKeys     = []string{"designation", "headline", "jobtitle", "title", "position", "role"}
+	emailKeys     = []string{"email", "emailaddress"}
+	phoneKeys     = []string{"phone", "phonenumber", "mobile", "contact", "contactnumber", "telephone"}
+	addressKeys   = []string{"address", "location", "city"}
+	linkKeys      = []string{"linkedin", "portfolio", "website", "github", "url"}
+	summaryKeys   = []string{"summary", "profile", "profilesummary", "professionalsummary", "about", "objective", "careerobjective"}
+	entryTitles   = []string{"title", "jobtitle", "position", "role", "designation", "degree", "name", "projectname", "projecttitle", "certificatename", "certification", "language", "skill", "course"}
+	entrySubs     = []string{"company", "companyname", "employer", "organization", "organisation", "institution", "university", "school", "college", "issuer", "issuingorganization", "provider", "proficiency", "level", "proficiencylevel", "fieldofstudy", "technologies"}
+	entryPlaces   = []string{"location", "city"}
+	entryPeriods  = []string{"period", "duration", "dates", "daterange", "timeline", "year", "graduationyear", "date"}
+	entryStarts   = []string{"startdate", "start", "from", "issuedate"}
+	entryEnds     = []string{"enddate", "end", "to", "expirydate"}
+	entryBullets  = []string{"responsibilities", "keyresponsibilities", "achievements", "highlights", "bullets", "tasks", "accomplishments", "keyachievements"}
+	entryLines    = []string{"description", "summary", "details", "link", "projectlink", "url"}
+	internalKeys  = []string{"id", "_id", "authuserid", "createdat", "updatedat", "language", "spec", "format", "cvformat", "clformat"}
+	letterBody    = []string{"body", "content", "text", "letter", "coverletter", "paragraphs", "bodyparagraphs"}
+	letterParts   = []string{"introduction", "intro", "openingparagraph", "middle", "middleparagraph", "conclusion", "closingparagraph", "calltoaction"}
+	letterGreet   = []string{"salutation", "greeting", "opening"}
+	letterClose   = []string{"closing", "signoff", "complimentaryclose", "closingline", "valediction"}
+	letterSign    = []string{"signature", "sendername", "name", "yourname"}
+	letterSubject = []string{"subject", "subjectline", "re", "title"}
+	letterTo      = []string{"recipient", "recipientaddress", "to", "hiringmanager", "company", "companyaddress", "address"}
+	letterDate    = []string{"date"}
+	letterFrom    = []string{"sender", "from", "senderdetails", "userdetails", "personalinfo"}
+)
+
+// sectionOrder lists the usual CV sections with their titles. Keys not
+// listed follow in alphabetical order under a title derived from the key.
+var sectionOrder = []struct {
+	title string
+	keys  []string
+}{
+	{"Experience", []string{"experience", "workexperience", "workexperiences", "experiences", "experiencesummary", "employment", "employmenthistory", "professionalexperience"}},
+	{"Education", []string{"education", "academics", "academicbackground", "qualifications"}},
+	{"Projects", []string{"projects", "pastprojects", "keyprojects"}},
+	{"Skills", []string{"skills", "keyskills", "technicalskills", "coreskills", "competencies"}},
+	{"Tools", []string{"tools", "technologies", "toolsandtechnologies"}},
+	{"Certifications", []string{"certifications", "certificates", "licenses"}},
+	{"Languages", []string{"languages", "languageskills"}},
+	{"Awards", []string{"awards", "honors", "achievements"}},
+	{"Interests", []string{"interests", "hobbies"}},
+}
+
+func normKey(k string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "", " ", "").Replace(k))
+}
+
+// fields is a JSON object indexed by normalized key. Keys are marked used
+// once read so the remaining ones can be rendered generically.
+type fields struct {
+	values map[string]interface{}
+	names  map[string]string // normalized -> original key
+	used   map[string]bool
+}
+
+func newFields(m map[string]interface{}) *fields {
+	f := &fields{values: map[string]interface{}{}, names: map[string]string{}, used: map[string]bool{}}
+	for k, v := range m {
+		n := normKey(k)
+		f.values[n] = v
+		f.names[n] = k
+	}
+	return f
+}
+
+// take returns the first present value of keys and marks it used.
+func (f *fields) take(keys ...string) interface{} {
+	for _, k := range keys {
+		if v, ok := f.values[k]; ok && !f.used[k] && !empty(v) {
+			f.used[k] = true
+			return v
+		}
+	}
+	return nil
+}
+
+func (f *fields) str(keys ...string) string {
+	return text(f.take(keys...))
+}
+
+// object returns the first present object value of keys and marks it used.
+func (f *fields) object(keys ...string) map[string]interface{} {
+	for _, k := range keys {
+		if m, ok := f.values[k].(map[string]interface{}); ok && !f.used[k] && len(m) > 0 {
+			f.used[k] = true
+			return m
+		}
+	}
+	return nil
+}
+
+// plain returns the first present string value of keys and marks it used.
+func (f *fields) plain(keys ...string) string {
+	for _, k := range keys {
+		if s, ok := f.values[k].(string); ok && !f.used[k] && strings.TrimSpace(s) != "" {
+			f.used[k] = true
+			return strings.TrimSpace(s)
+		}
+	}
+	return ""
+}
+
+// rest returns the unused keys in alphabetical order.
+func (f *fields) rest() []string {
+	var keys []string
+	for k := range f.values {
+		if !f.used[k] && !contains(internalKeys, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func empty(v interface{}) bool {
+	switch t := v.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(t) == ""
+	case []interface{}:
+		return len(t) == 0
+	case map[string]interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// text renders a scalar, or a list of scalars, as a single string.
+func text(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		if d, ok := parseDate(t); ok {
+			return d
+		}
+		return strings.TrimSpace(t)
+	case float64:
+		if t == float64(int64(t)) {
+			return fmt.Sprintf("%d", int64(t))
+		}
+		return fmt.Sprintf("%g", t)
+	case time.Time:
+		return t.Format("Jan 2006")
+	case []interface{}:
+		return strings.Join(list(t), ", ")
+	case map[string]interface{}:
+		f := newFields(t)
+		if s := f.str(entryTitles...); s != "" {
+			if sub := f.str(entrySubs...); sub != "" {
+				return s + " – " + sub
+			}
+			return s
+		}
+		var parts []string
+		for _, k := range f.rest() {
+			if s := text(f.values[k]); s != "" {
+				parts = append(parts, s)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return strings.TrimSpace(fmt.Sprint(v))
+}
+
+// list renders a string, or a list of values, as lines. Strings are split
+// on newlines and leading bullet characters are removed.
+func list(v interface{}) []string {
+	var out []string
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if s := text(item); s != "" {
+				out = append(out, s)
+			}
+		}
+	case string:
+		for _, line := range strings.Split(t, "\n") {
+			line = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(line), "-•*·"))
+			if line != "" {
+				out = append(out, line)
+			}
+		}
+	default:
+		if s := text(t); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// parseDate formats ISO timestamps as "Jan 2006".
+func parseDate(s string) (string, bool) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t.Format("Jan 2006"), true
+		}
+	}
+	return "", false
+}
+
+// normalize converts decoded BSON (primitive.A, primitive.M, DateTime and
+// integer types) to the plain JSON types the mapping expects.
+func normalize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	if t, ok := v.(interface{ Time() time.Time }); ok {
+		return t.Time()
+	}
+	switch v.(type) {
+	case string, float64, bool, time.Time:
+		return v
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return nil // binary data is not rendered
+		}
+		out := make([]interface{}, rv.Len())
+		for i := range out {
+			out[i] = normalize(rv.Index(i).Interface())
+		}
+		return out
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		out := make(map[string]interface{}, rv.Len())
+		for _, k := range rv.MapKeys() {
+			out[k.String()] = normalize(rv.MapIndex(k).Interface())
+		}
+		return out
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint())
+	case reflect.Float32:
+		return rv.Float()
+	case reflect.String:
+		return rv.String()
+	}
+	return fmt.Sprint(v)
+}
+
+// unwrap descends into single wrapper objects such as {"cv_data": {...}}.
+func unwrap(data map[string]interface{}) map[string]interface{} {
+	for i := 0; i < 3; i++ {
+		f := newFields(data)
+		var inner map[string]interface{}
+		for _, k := range wrapperKeys {
+			if m, ok := f.values[k].(map[string]interface{}); ok {
+				inner = m
+				break
+			}
+		}
+		if inner == nil || len(f.rest()) > 1 {
+			return data
+		}
+		data = inner
+	}
+	return data
+}
+
+func humanize(key string) string {
+	words := strings.Fields(strings.NewReplacer("_", " ", "-", " ").Replace(key))
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// header reads the name block from data, filling gaps from the profile.
+func header(f *fields, p Profile) Header {
+	h := Header{Photo: p.Photo}
+	src := f
+	if m := f.object(headerKeys...); m != nil {
+		src = newFields(m)
+	}
+	h.Name = firstOf(src.str(nameKeys...), f.str(nameKeys...), p.Name)
+	h.Title = firstOf(src.str(titleKeys...), f.str(titleKeys...), p.Title)
+
+	add := func(v string) {
+		if v != "" && !contains(h.Contacts, v) {
+			h.Contacts = append(h.Contacts, v)
+		}
+	}
+	add(firstOf(src.str(emailKeys...), f.str(emailKeys...), p.Email))
+	add(firstOf(src.str(phoneKeys...), f.str(phoneKeys...), p.Phone))
+	add(firstOf(src.str(addressKeys...), f.str(addressKeys...), p.City))
+	linked := false
+	for _, k := range linkKeys {
+		for _, s := range []*fields{src, f} {
+			if v := s.str(k); v != "" {
+				add(v)
+				linked = true
+			}
+		}
+	}
+	if !linked {
+		add(p.LinkedIn)
+	}
+	return h
+}
+
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// CVDocument maps the generated CV JSON to a document. The ML output is
+// loosely structured, so common key variants are recognised and unknown
+// keys are rendered as generic sections.
+func CVDocument(data map[string]interface{}, p Profile) Document {
+	plain, _ := normalize(data).(map[string]interface{})
+	f := newFields(unwrap(plain))
+	doc := Document{Kind: KindCV, Header: header(f, p)}
+
+	if s := f.plain(summaryKeys...); s != "" {
+		doc.Sections = append(doc.Sections, Section{Title: "Profile", Text: s})
+	}
+	for _, known := range sectionOrder {
+		for _, k := range known.keys {
+			if v := f.take(k); v != nil {
+				doc.Sections = append(doc.Sections, section(known.title, v))
+			}
+		}
+	}
+	for _, k := range f.rest() {
+		if s := section(humanize(f.names[k]), f.values[k]); len(s.Entries)+len(s.Items) > 0 || s.Text != "" {
+			doc.Sections = append(doc.Sections, s)
+		}
+	}
+	return doc
+}
+
+// section converts a JSON value to a section: strings become text, lists
+// of scalars become items and lists of objects become entries.
+func section(title string, v interface{}) Section {
+	s := Section{Title: title}
+	switch t := v.(type) {
+	case string:
+		s.Text = strings.TrimSpace(t)
+	case []interface{}:
+		for _, item := range t {
+			if m, ok := item.(map[string]interface{}); ok {
+				s.Entries = append(s.Entries, entry(m))
+			} else if str := text(item); str != "" {
+				s.Items = append(s.Items, str)
+			}
+		}
+	case map[string]interface{}:
+		// Grouped lists such as {"backend": ["Go"], "frontend": ["React"]}
+		f := newFields(t)
+		for _, k := range f.rest() {
+			if str := text(f.values[k]); str != "" {
+				s.Items = append(s.Items, humanize(f.names[k])+": "+str)
+			}
+		}
+	default:
+		s.Text = text(t)
+	}
+
+	// Entries with a title and subtitle only, like languages, read better
+	// as items
+	short := len(s.Entries) > 0
+	for _, e := range s.Entries {
+		short = short && e.Period == "" && len(e.Lines) == 0 && len(e.Bullets) == 0
+	}
+	if short {
+		for _, e := range s.Entries {
+			item := e.Title
+			if e.Subtitle != "" {
+				item = strings.TrimSpace(item + " – " + e.Subtitle)
+			}
+			s.Items = append(s.Items, item)
+		}
+		s.Entries = nil
+	}
+	return s
+}
+
+func entry(m map[string]interface{}) Entry {
+	f := newFields(m)
+	e := Entry{Title: f.str(entryTitles...)}
+
+	var sub []string
+	for _, k := range entrySubs {
+		if v := f.str(k); v != "" {
+			sub = append(sub, v)
+		}
+	}
+	if place := f.str(entryPlaces...); place != "" {
+		sub = append(sub, place)
+	}
+	e.Subtitle = strings.Join(sub, ", ")
+
+	if period := f.str(entryPeriods...); period != "" {
+		e.Period = period
+	} else {
+		start, end := f.str(entryStarts...), f.str(entryEnds...)
+		switch {
+		case start != "" && end != "":
+			e.Period = start + " – " + end
+		case start != "":
+			e.Period = start + " – Present"
+		case end != "":
+			e.Period = end
+		}
+	}
+	if e.Title == "" {
+		e.Title, e.Subtitle = e.Subtitle, ""
+	}
+
+	for _, k := range entryBullets {
+		e.Bullets = append(e.Bullets, list(f.take(k))...)
+	}
+	for _, k := range entryLines {
+		e.Lines = append(e.Lines, list(f.take(k))...)
+	}
+	for _, k := range f.rest() {
+		if _, ok := f.values[k].(bool); ok {
+			continue
+		}
+		if s := text(f.values[k]); s != "" {
+			e.Lines = append(e.Lines, humanize(f.names[k])+": "+s)
+		}
+	}
+	return e
+}
+
+// CoverLetterDocument maps the generated cover letter JSON to a document.
+// The letter may be a single text or split into parts.
+func CoverLetterDocument(data map[string]interface{}, p Profile) Document {
+	plain, _ := normalize(data).(map[string]interface{})
+	f := newFields(unwrap(plain))
+	l := &Letter{}
+
+	from := newFields(f.object(letterFrom...))
+	h := header(from, p)
+
+	l.Date = f.str(letterDate...)
+	if l.Date == "" {
+		l.Date = time.Now().Format("January 2, 2006")
+	}
+	l.Subject = f.str(letterSubject...)
+	l.Salutation = f.str(letterGreet...)
+	l.Closing = f.str(letterClose...)
+	l.Signature = f.str(letterSign...)
+	switch to := f.take(letterTo...).(type) {
+	case map[string]interface{}:
+		tf := newFields(to)
+		for _, k := range tf.rest() {
+			l.Recipient = append(l.Recipient, list(tf.values[k])...)
+		}
+	case nil:
+	default:
+		l.Recipient = list(to)
+	}
+
+	for _, k := range letterBody {
+		switch body := f.take(k).(type) {
+		case string:
+			l.Paragraphs = append(l.Paragraphs, paragraphs(body)...)
+		case []interface{}:
+			for _, item := range body {
+				l.Paragraphs = append(l.Paragraphs, paragraphs(text(item))...)
+			}
+		}
+	}
+	// Some responses split the letter into named parts
+	for _, k := range letterParts {
+		l.Paragraphs = append(l.Paragraphs, paragraphs(text(f.take(k)))...)
+	}
+
+	// Salutation and closing are often part of the text
+	if l.Salutation == "" && len(l.Paragraphs) > 0 && isSalutation(l.Paragraphs[0]) {
+		l.Salutation, l.Paragraphs = l.Paragraphs[0], l.Paragraphs[1:]
+	}
+	if l.Closing == "" && len(l.Paragraphs) > 0 {
+		last := l.Paragraphs[len(l.Paragraphs)-1]
+		if lines := strings.Split(last, "\n"); isClosing(lines[0]) {
+			l.Closing = strings.TrimSpace(lines[0])
+			if len(lines) > 1 && l.Signature == "" {
+				l.Signature = strings.TrimSpace(strings.Join(lines[1:], " "))
+			}
+			l.Paragraphs = l.Paragraphs[:len(l.Paragraphs)-1]
+		}
+	}
+	if l.Salutation == "" {
+		l.Salutation = "Dear Hiring Manager,"
+	}
+	if l.Closing == "" {
+		l.Closing = "Sincerely,"
+	}
+	if l.Signature == "" {
+		l.Signature = h.Name
+	}
+	return Document{Kind: KindCoverLetter, Header: h, Letter: l}
+}
+
+// paragraphs splits text on blank lines, joining wrapped lines.
+func paragraphs(s string) []string {
+	var out []string
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	for _, p := range strings.Split(s, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	// A single block with line breaks is one paragraph per line
+	if len(out) == 1 && strings.Count(out[0], "\n") > 1 {
+		out = list(out[0])
+	}
+	return out
+}
+
+func isSalutation(s string) bool {
+	low := strings.ToLower(s)
+	return len(s) < 80 && (strings.HasPrefix(low, "dear ") || strings.HasPrefix(low, "hello") ||
+		strings.HasPrefix(low, "hi ") || strings.HasPrefix(low, "to whom") ||
+		strings.HasPrefix(low, "sehr geehrte") || strings.HasPrefix(low, "liebe"))
+}
+
+func isClosing(s string) bool {
+	low := strings.ToLower(strings.TrimSpace(s))
+	for _, c := range []string{"sincerely", "best regards", "kind regards", "regards", "yours", "best,", "thank you,", "warm regards", "mit freundlichen grüßen", "viele grüße"} {
+		if strings.HasPrefix(low, c) {
+			return len(low) < 40
+		}
+	}
+	return false
+}
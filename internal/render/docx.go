@@ -0,0 +1,331 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// DOCX output keeps the template's fonts, colors and header style in a
+// single column, so the document stays easy to edit and to parse.
+
+const (
+	docxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Default Extension="jpeg" ContentType="image/jpeg"/>
+<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>
+<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>
+<Override PartName="/docProps/core.xml" ContentType="application/vnd.openxmlformats-package.core-properties+xml"/>
+</Types>`
+	docxRootRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/package/2006/relationships/metadata/core-properties" Target="docProps/core.xml"/>
+</Relationships>`
+	docxStylesRel = `<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`
+	docxPhotoRel  = `<Relationship Id="rIdPhoto" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/image" Target="media/photo.jpeg"/>`
+)
+
+// emuPerPoint converts points to the English Metric Units of DrawingML.
+const emuPerPoint = 12700
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func hexColor(c color) string {
+	return fmt.Sprintf("%02X%02X%02X", int(c.r*255+0.5), int(c.g*255+0.5), int(c.b*255+0.5))
+}
+
+// docx builds word/document.xml.
+type docx struct {
+	tpl   Template
+	body  strings.Builder
+	photo *pdfImage
+}
+
+// run is a span of text with direct formatting; sizes are in points.
+type run struct {
+	text   string
+	bold   bool
+	italic bool
+	size   float64
+	color  color
+	caps   bool
+}
+
+func (r run) xml() string {
+	var props strings.Builder
+	if r.bold {
+		props.WriteString("<w:b/>")
+	}
+	if r.italic {
+		props.WriteString("<w:i/>")
+	}
+	if r.caps {
+		props.WriteString("<w:caps/>")
+	}
+	fmt.Fprintf(&props, `<w:color w:val="%s"/><w:sz w:val="%d"/>`, hexColor(r.color), int(r.size*2+0.5))
+	var out strings.Builder
+	for i, part := range strings.Split(r.text, "\t") {
+		if i > 0 {
+			fmt.Fprintf(&out, "<w:r><w:rPr>%s</w:rPr><w:tab/></w:r>", props.String())
+		}
+		if part != "" {
+			fmt.Fprintf(&out, `<w:r><w:rPr>%s</w:rPr><w:t xml:space="preserve">%s</w:t></w:r>`, props.String(), xmlEscape(part))
+		}
+	}
+	return out.String()
+}
+
+// para holds paragraph properties; spacing is in points.
+type para struct {
+	align      string
+	before     float64
+	after      float64
+	indent     float64 // left indent
+	hanging    float64
+	firstLine  float64
+	ruleBelow  bool
+	shade      *color
+	rightTabAt float64 // right-aligned tab stop, 0 for none
+}
+
+func (d *docx) paragraph(p para, runs ...string) {
+	var props strings.Builder
+	if p.rightTabAt > 0 {
+		fmt.Fprintf(&props, `<w:tabs><w:tab w:val="right" w:pos="%d"/></w:tabs>`, int(p.rightTabAt*20))
+	}
+	if p.ruleBelow {
+		fmt.Fprintf(&props, `<w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="%s"/></w:pBdr>`, hexColor(d.tpl.Accent))
+	}
+	if p.shade != nil {
+		fmt.Fprintf(&props, `<w:shd w:val="clear" w:color="auto" w:fill="%s"/>`, hexColor(*p.shade))
+	}
+	fmt.Fprintf(&props, `<w:spacing w:before="%d" w:after="%d"/>`, int(p.before*20), int(p.after*20))
+	if p.indent > 0 || p.hanging > 0 || p.firstLine > 0 {
+		fmt.Fprintf(&props, `<w:ind w:left="%d"`, int(p.indent*20))
+		if p.hanging > 0 {
+			fmt.Fprintf(&props, ` w:hanging="%d"`, int(p.hanging*20))
+		} else if p.firstLine > 0 {
+			fmt.Fprintf(&props, ` w:firstLine="%d"`, int(p.firstLine*20))
+		}
+		props.WriteString("/>")
+	}
+	if p.align != "" && p.align != "left" {
+		align := p.align
+		if align == "justify" {
+			align = "both"
+		}
+		fmt.Fprintf(&props, `<w:jc w:val="%s"/>`, align)
+	}
+	fmt.Fprintf(&d.body, "<w:p><w:pPr>%s</w:pPr>%s</w:p>", props.String(), strings.Join(runs, ""))
+}
+
+// photoRun embeds the photo inline, w points wide.
+func (d *docx) photoRun(w float64) string {
+	h := w * float64(d.photo.height) / float64(d.photo.width)
+	cx, cy := int(w*emuPerPoint), int(h*emuPerPoint)
+	geom := "rect"
+	if d.tpl.PhotoRound {
+		geom = "ellipse"
+	}
+	return fmt.Sprintf(`<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0"><wp:extent cx="%d" cy="%d"/><wp:docPr id="1" name="Photo"/>`+
+		`<a:graphic xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main"><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">`+
+		`<pic:pic xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"><pic:nvPicPr><pic:cNvPr id="0" name="photo.jpeg"/><pic:cNvPicPr/></pic:nvPicPr>`+
+		`<pic:blipFill><a:blip r:embed="rIdPhoto"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>`+
+		`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%d" cy="%d"/></a:xfrm><a:prstGeom prst="%s"><a:avLst/></a:prstGeom></pic:spPr></pic:pic>`+
+		`</a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`, cx, cy, cx, cy, geom)
+}
+
+func (d *docx) contentWidth() float64 {
+	return pageWidth - 2*d.tpl.Margin
+}
+
+func (d *docx) header(h Header) {
+	t := d.tpl
+	textCol, titleCol, mutedCol := t.Text, t.Accent, t.Muted
+	var shade *color
+	if t.HeaderBand {
+		shade = &t.Accent
+		textCol, titleCol, mutedCol = white, white, white
+	}
+	base := para{align: t.HeaderAlign, shade: shade}
+
+	if d.photo != nil {
+		p := base
+		p.after = 6
+		d.paragraph(p, d.photoRun(72))
+	}
+	p := base
+	d.paragraph(p, run{text: h.Name, bold: true, size: t.BaseSize * 2.3, color: textCol}.xml())
+	if h.Title != "" {
+		d.paragraph(p, run{text: h.Title, size: t.BaseSize * 1.25, color: titleCol}.xml())
+	}
+	if len(h.Contacts) > 0 {
+		p.before = 4
+		d.paragraph(p, run{text: strings.Join(h.Contacts, "   |   "), size: t.BaseSize * 0.95, color: mutedCol}.xml())
+	}
+	d.paragraph(para{after: t.BaseSize})
+}
+
+func (d *docx) section(s Section) {
+	t := d.tpl
+	d.paragraph(para{before: t.BaseSize, after: 4, ruleBelow: t.SectionRule},
+		run{text: s.Title, bold: true, caps: t.UpperTitles, size: t.BaseSize * 1.2, color: t.Accent}.xml())
+
+	body := func(s string) string { return run{text: s, size: t.BaseSize, color: t.Text}.xml() }
+	if s.Text != "" {
+		for _, p := range paragraphs(s.Text) {
+			d.paragraph(para{after: 4}, body(p))
+		}
+	}
+	for _, item := range s.Items {
+		d.paragraph(para{indent: 14, hanging: 14}, run{text: "•\t", size: t.BaseSize, color: t.Accent}.xml()+body(item))
+	}
+	for _, e := range s.Entries {
+		title := run{text: e.Title, bold: true, size: t.BaseSize * 1.05, color: t.Text}.xml()
+		if e.Period != "" {
+			title += run{text: "\t" + e.Period, size: t.BaseSize * 0.95, color: t.Muted}.xml()
+		}
+		d.paragraph(para{before: 6, rightTabAt: d.contentWidth()}, title)
+		if e.Subtitle != "" {
+			d.paragraph(para{}, run{text: e.Subtitle, italic: true, size: t.BaseSize * 0.95, color: t.Muted}.xml())
+		}
+		for _, line := range e.Lines {
+			d.paragraph(para{}, body(line))
+		}
+		for _, b := range e.Bullets {
+			d.paragraph(para{indent: 14, hanging: 14}, run{text: "•\t", size: t.BaseSize, color: t.Accent}.xml()+body(b))
+		}
+	}
+}
+
+func (d *docx) letter(l *Letter) {
+	t := d.tpl
+	body := func(s string) string { return run{text: s, size: t.BaseSize, color: t.Text}.xml() }
+	gap := t.BaseSize * t.Leading * 0.6
+
+	dateAlign := "left"
+	if t.HeaderAlign == "right" {
+		dateAlign = "right"
+	}
+	if t.SectionRule {
+		d.paragraph(para{ruleBelow: true, after: t.BaseSize})
+	}
+	d.paragraph(para{align: dateAlign, after: gap}, body(l.Date))
+	for i, line := range l.Recipient {
+		p := para{}
+		if i == len(l.Recipient)-1 {
+			p.after = gap
+		}
+		d.paragraph(p, body(line))
+	}
+	if l.Subject != "" {
+		d.paragraph(para{after: gap}, run{text: l.Subject, bold: true, size: t.BaseSize, color: t.Accent}.xml())
+	}
+	d.paragraph(para{after: gap}, body(l.Salutation))
+	for _, p := range l.Paragraphs {
+		pp := para{after: gap, align: "justify"}
+		if t.Indent {
+			pp.firstLine = t.BaseSize * 2
+		}
+		d.paragraph(pp, body(p))
+	}
+	d.paragraph(para{before: gap, after: t.BaseSize * 2.5}, body(l.Closing))
+	d.paragraph(para{}, run{text: l.Signature, bold: true, size: t.BaseSize, color: t.Text}.xml())
+}
+
+func (d *docx) document() string {
+	m := int(d.tpl.Margin * 20)
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing"><w:body>` +
+		d.body.String() +
+		fmt.Sprintf(`<w:sectPr><w:pgSz w:w="%d" w:h="%d"/><w:pgMar w:top="%d" w:right="%d" w:bottom="%d" w:left="%d" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>`,
+			twips(pageWidth), twips(pageHeight), m, m, m, m) +
+		`</w:body></w:document>`
+}
+
+func (d *docx) styles() string {
+	fontName := d.tpl.family().docxName
+	return fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="%[1]s" w:hAnsi="%[1]s" w:cs="%[1]s" w:eastAsia="%[1]s"/><w:sz w:val="%[2]d"/><w:lang w:val="en-US"/></w:rPr></w:rPrDefault><w:pPrDefault><w:pPr><w:spacing w:after="0" w:line="%[3]d" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults><w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style></w:styles>`,
+		xmlEscape(fontName), int(d.tpl.BaseSize*2+0.5), int(d.tpl.Leading*240))
+}
+
+func coreProps(title string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>` +
+		xmlEscape(title) + `</dc:title><dc:creator>RAAS</dc:creator></cp:coreProperties>`
+}
+
+// renderDOCX writes doc as a Word document.
+func renderDOCX(doc Document, tpl Template) ([]byte, error) {
+	d := &docx{tpl: tpl}
+	if tpl.Photo && len(doc.Header.Photo) > 0 {
+		if img, err := preparePhoto(doc.Header.Photo); err == nil {
+			d.photo = &img
+		}
+	}
+	d.header(doc.Header)
+	if doc.Kind == KindCoverLetter && doc.Letter != nil {
+		d.letter(doc.Letter)
+	} else {
+		for _, s := range doc.Sections {
+			d.section(s)
+		}
+	}
+
+	rels := docxStylesRel
+	if d.photo != nil {
+		rels += docxPhotoRel
+	}
+	parts := []zipPart{
+		{"[Content_Types].xml", []byte(docxContentTypes)},
+		{"_rels/.rels", []byte(docxRootRels)},
+		{"docProps/core.xml", []byte(coreProps(documentTitle(doc)))},
+		{"word/_rels/document.xml.rels", []byte(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` + rels + `</Relationships>`)},
+		{"word/document.xml", []byte(d.document())},
+		{"word/styles.xml", []byte(d.styles())},
+	}
+	if d.photo != nil {
+		parts = append(parts, zipPart{"word/media/photo.jpeg", d.photo.data})
+	}
+	return zipParts(parts)
+}
+
+// zipPart is one file of a ZIP archive.
+type zipPart struct {
+	name string
+	data []byte
+}
+
+func zipParts(parts []zipPart) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, p := range parts {
+		w, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(p.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// twips converts points to twentieths of a point.
+func twips(pt float64) int {
+	return int(pt * 20)
+}
@@ -0,0 +1,390 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+)
+
+const photoResource = "Photo"
+
+// layout renders a document onto PDF pages with one template.
+type layout struct {
+	pdf   *pdfFile
+	tpl   Template
+	photo *pdfImage
+}
+
+// flow places blocks top to bottom in one column and continues on the
+// next page when the column is full.
+type flow struct {
+	l    *layout
+	x, w float64
+	page int
+	y    float64 // top of the next line
+}
+
+func (l *layout) canvas(page int) *canvas {
+	for len(l.pdf.pages) <= page {
+		c := l.pdf.addPage()
+		if l.tpl.Sidebar {
+			c.rect(0, 0, sidebarWidth, pageHeight, l.tpl.Tint)
+		}
+	}
+	return l.pdf.pages[page]
+}
+
+func (l *layout) lineHeight(size float64) float64 {
+	return size * l.tpl.Leading
+}
+
+func (f *flow) canvas() *canvas {
+	return f.l.canvas(f.page)
+}
+
+// need moves to the next page unless h points fit in the column.
+func (f *flow) need(h float64) {
+	margin := f.l.tpl.Margin
+	if f.y+h > pageHeight-margin && f.y > margin+1 {
+		f.page++
+		f.y = margin
+		f.l.canvas(f.page)
+	}
+}
+
+func (f *flow) space(h float64) {
+	f.y += h
+}
+
+// textStyle is the font, size and color of a text block.
+type textStyle struct {
+	face  face
+	size  float64
+	color color
+	align string
+}
+
+func (l *layout) style(fc face, scale float64, col color) textStyle {
+	return textStyle{face: fc, size: l.tpl.BaseSize * scale, color: col, align: "left"}
+}
+
+// lines draws wrapped text. The first line is indented by first, the
+// others by rest.
+func (f *flow) lines(s string, st textStyle, first, rest float64) {
+	fnt := f.l.tpl.font(st.face)
+	lh := f.l.lineHeight(st.size)
+	wrapped := wrapText(s, fnt, st.size, f.w-first)
+	if len(wrapped) > 1 && rest != first {
+		remaining := strings.TrimPrefix(strings.Join(strings.Fields(s), " "), wrapped[0])
+		wrapped = append(wrapped[:1], wrapText(strings.TrimSpace(remaining), fnt, st.size, f.w-rest)...)
+	}
+	for i, line := range wrapped {
+		indent := rest
+		if i == 0 {
+			indent = first
+		}
+		f.need(lh)
+		x := f.x + indent
+		switch st.align {
+		case "center":
+			x = f.x + (f.w-fnt.width(line, st.size))/2
+		case "right":
+			x = f.x + f.w - fnt.width(line, st.size)
+		}
+		f.canvas().text(x, f.y+st.size*0.9, fnt, st.size, st.color, line)
+		f.y += lh
+	}
+}
+
+func (f *flow) text(s string, st textStyle) {
+	f.lines(s, st, 0, 0)
+}
+
+// pair draws left and right aligned text on one line, or on two lines when
+// they do not fit together.
+func (f *flow) pair(left string, ls textStyle, right string, rs textStyle) {
+	lf, rf := f.l.tpl.font(ls.face), f.l.tpl.font(rs.face)
+	rw := rf.width(right, rs.size)
+	if right == "" || lf.width(left, ls.size)+rw+12 > f.w {
+		f.text(left, ls)
+		if right != "" {
+			f.text(right, rs)
+		}
+		return
+	}
+	lh := f.l.lineHeight(ls.size)
+	f.need(lh)
+	f.canvas().text(f.x, f.y+ls.size*0.9, lf, ls.size, ls.color, left)
+	f.canvas().text(f.x+f.w-rw, f.y+ls.size*0.9, rf, rs.size, rs.color, right)
+	f.y += lh
+}
+
+func (f *flow) rule(col color, width float64) {
+	f.need(width + 2)
+	f.canvas().line(f.x, f.y, f.x+f.w, f.y, width, col)
+	f.y += width
+}
+
+// bullets draws a list with hanging indents.
+func (f *flow) bullets(items []string, st textStyle) {
+	fnt := f.l.tpl.font(st.face)
+	indent := fnt.width("•  ", st.size)
+	for _, item := range items {
+		f.need(f.l.lineHeight(st.size))
+		f.canvas().text(f.x, f.y+st.size*0.9, fnt, st.size, f.l.tpl.Accent, "•")
+		f.lines(item, st, indent, indent)
+	}
+}
+
+// photoBox draws the photo with width w at (x, y) and returns its height.
+func (l *layout) photoBox(page int, x, y, w float64) float64 {
+	if l.photo == nil {
+		return 0
+	}
+	h := w * float64(l.photo.height) / float64(l.photo.width)
+	l.canvas(page).image(photoResource, x, y, w, h, l.tpl.PhotoRound)
+	return h
+}
+
+// header draws the name block. With contacts set the contact lines are
+// included; the sidebar template shows them in the sidebar instead.
+func (l *layout) header(f *flow, h Header, contacts bool) {
+	t := l.tpl
+	nameSt := l.style(faceBold, 2.3, t.Text)
+	titleSt := l.style(faceRegular, 1.25, t.Accent)
+	contactSt := l.style(faceRegular, 0.95, t.Muted)
+	if t.HeaderBand {
+		nameSt.color, titleSt.color, contactSt.color = white, white, white
+	}
+	nameSt.align, titleSt.align, contactSt.align = t.HeaderAlign, t.HeaderAlign, t.HeaderAlign
+
+	// Measure first so the band can be drawn behind the text
+	full := *f
+	photoW := 0.0
+	if l.photo != nil && !t.Sidebar {
+		photoW = 78
+		f.w -= photoW + 16
+		if t.HeaderAlign == "right" {
+			f.x += photoW + 16
+		}
+	}
+	contactLine := strings.Join(h.Contacts, "   |   ")
+	height := l.lineHeight(nameSt.size) * float64(len(wrapText(h.Name, t.font(faceBold), nameSt.size, f.w)))
+	if h.Title != "" {
+		height += l.lineHeight(titleSt.size) * float64(len(wrapText(h.Title, t.font(faceRegular), titleSt.size, f.w)))
+	}
+	if contacts && contactLine != "" {
+		height += 4 + l.lineHeight(contactSt.size)*float64(len(wrapText(contactLine, t.font(faceRegular), contactSt.size, f.w)))
+	}
+	if photoW > 0 {
+		height = max(height, photoW*float64(l.photo.height)/float64(l.photo.width))
+	}
+
+	top := f.y
+	if t.HeaderBand {
+		pad := t.Margin * 0.6
+		l.canvas(f.page).rect(0, 0, pageWidth, top+height+pad, t.Accent)
+	}
+	if photoW > 0 {
+		px := full.x + full.w - photoW
+		if t.HeaderAlign == "right" {
+			px = full.x
+		}
+		l.photoBox(f.page, px, top, photoW)
+	}
+
+	f.text(h.Name, nameSt)
+	if h.Title != "" {
+		f.text(h.Title, titleSt)
+	}
+	if contacts && contactLine != "" {
+		f.space(4)
+		f.text(contactLine, contactSt)
+	}
+	f.x, f.w = full.x, full.w
+	f.y = top + height
+	if t.HeaderBand {
+		f.y += t.Margin * 0.6
+	}
+	f.space(t.BaseSize * 1.4)
+}
+
+// section draws a CV section. Items are listed one per line in the
+// sidebar and run together in the main column.
+func (l *layout) section(f *flow, s Section, sidebar bool) {
+	t := l.tpl
+	titleSt := l.style(faceBold, 1.2, t.Accent)
+	bodySt := l.style(faceRegular, 1, t.Text)
+	mutedSt := l.style(faceItalic, 0.95, t.Muted)
+	lh := l.lineHeight(bodySt.size)
+
+	title := s.Title
+	if t.UpperTitles {
+		title = strings.ToUpper(title)
+	}
+	f.need(l.lineHeight(titleSt.size) + 2*lh)
+	f.text(title, titleSt)
+	if t.SectionRule {
+		f.space(1)
+		f.rule(t.Accent, 0.6)
+	}
+	f.space(lh * 0.35)
+
+	if s.Text != "" {
+		for _, p := range paragraphs(s.Text) {
+			f.text(p, bodySt)
+		}
+	}
+	if len(s.Items) > 0 {
+		grouped := false
+		for _, item := range s.Items {
+			grouped = grouped || strings.Contains(item, ": ")
+		}
+		if sidebar || grouped {
+			for _, item := range s.Items {
+				f.text(item, bodySt)
+			}
+		} else {
+			f.text(strings.Join(s.Items, "  ·  "), bodySt)
+		}
+	}
+	for i, e := range s.Entries {
+		if i > 0 {
+			f.space(lh * 0.5)
+		}
+		f.need(3 * lh)
+		f.pair(e.Title, l.style(faceBold, 1.05, t.Text), e.Period, l.style(faceRegular, 0.95, t.Muted))
+		if e.Subtitle != "" {
+			f.text(e.Subtitle, mutedSt)
+		}
+		for _, line := range e.Lines {
+			f.text(line, bodySt)
+		}
+		f.bullets(e.Bullets, bodySt)
+	}
+	f.space(lh * 0.9)
+}
+
+const sidebarWidth = 180.0
+
+func (l *layout) cv(doc Document) {
+	t := l.tpl
+	if !t.Sidebar {
+		f := &flow{l: l, x: t.Margin, w: pageWidth - 2*t.Margin, y: t.Margin}
+		l.canvas(0)
+		l.header(f, doc.Header, true)
+		for _, s := range doc.Sections {
+			l.section(f, s, false)
+		}
+		return
+	}
+
+	pad := t.Margin * 0.6
+	side := &flow{l: l, x: pad, w: sidebarWidth - 2*pad, y: t.Margin}
+	main := &flow{l: l, x: sidebarWidth + t.Margin*0.75, w: pageWidth - sidebarWidth - 1.75*t.Margin, y: t.Margin}
+	l.canvas(0)
+
+	if l.photo != nil {
+		side.y += l.photoBox(0, side.x+side.w*0.1, side.y, side.w*0.8) + t.BaseSize*1.5
+	}
+	if len(doc.Header.Contacts) > 0 {
+		l.section(side, Section{Title: "Contact", Items: doc.Header.Contacts}, true)
+	}
+	l.header(main, doc.Header, false)
+	for _, s := range doc.Sections {
+		if s.compact() {
+			l.section(side, s, true)
+		} else {
+			l.section(main, s, false)
+		}
+	}
+}
+
+func (l *layout) letter(doc Document) {
+	t := l.tpl
+	lt := doc.Letter
+	f := &flow{l: l, x: t.Margin, w: pageWidth - 2*t.Margin, y: t.Margin * 0.8}
+	l.canvas(0)
+	l.header(f, doc.Header, true)
+	if t.SectionRule {
+		f.rule(t.Accent, 0.8)
+		f.space(t.BaseSize * 1.4)
+	}
+
+	bodySt := l.style(faceRegular, 1, t.Text)
+	lh := l.lineHeight(bodySt.size)
+	dateSt := bodySt
+	if t.HeaderAlign == "right" {
+		dateSt.align = "right"
+	}
+	f.text(lt.Date, dateSt)
+	f.space(lh * 0.8)
+	for _, line := range lt.Recipient {
+		f.text(line, bodySt)
+	}
+	if len(lt.Recipient) > 0 {
+		f.space(lh * 0.8)
+	}
+	if lt.Subject != "" {
+		f.text(lt.Subject, l.style(faceBold, 1, t.Accent))
+		f.space(lh * 0.8)
+	}
+	f.text(lt.Salutation, bodySt)
+	f.space(lh * 0.5)
+
+	indent := 0.0
+	gap := lh * 0.6
+	if t.Indent {
+		indent, gap = t.BaseSize*2, lh*0.25
+	}
+	for _, p := range lt.Paragraphs {
+		f.lines(p, bodySt, indent, 0)
+		f.space(gap)
+	}
+	f.space(lh * 0.4)
+	f.need(lh * 4)
+	f.text(lt.Closing, bodySt)
+	f.space(lh * 1.8)
+	f.text(lt.Signature, l.style(faceBold, 1, t.Text))
+}
+
+// pageNumbers adds "Page n of m" footers to multi-page documents.
+func (l *layout) pageNumbers() {
+	if len(l.pdf.pages) < 2 {
+		return
+	}
+	fnt := l.tpl.font(faceRegular)
+	size := l.tpl.BaseSize * 0.8
+	for i, c := range l.pdf.pages {
+		label := fmt.Sprintf("Page %d of %d", i+1, len(l.pdf.pages))
+		c.text(pageWidth-l.tpl.Margin-fnt.width(label, size), pageHeight-l.tpl.Margin*0.5, fnt, size, l.tpl.Muted, label)
+	}
+}
+
+// renderPDF lays out doc with the template.
+func renderPDF(doc Document, tpl Template) ([]byte, error) {
+	l := &layout{pdf: newPDF(documentTitle(doc)), tpl: tpl}
+	if tpl.Photo && len(doc.Header.Photo) > 0 {
+		if img, err := preparePhoto(doc.Header.Photo); err == nil {
+			l.photo = &img
+			l.pdf.addImage(photoResource, img)
+		}
+	}
+	if doc.Kind == KindCoverLetter && doc.Letter != nil {
+		l.letter(doc)
+	} else {
+		l.cv(doc)
+	}
+	l.pageNumbers()
+	return l.pdf.bytes()
+}
+
+func documentTitle(doc Document) string {
+	kind := "CV"
+	if doc.Kind == KindCoverLetter {
+		kind = "Cover Letter"
+	}
+	if doc.Header.Name == "" {
+		return kind
+	}
+	return doc.Header.Name + " – " + kind
+}
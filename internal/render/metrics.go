@@ -0,0 +1,212 @@
+package render
+
+import "strings"
+
+// Documents use the standard PDF base fonts, so nothing has to be embedded.
+// Widths are the AFM advance widths (1/1000 em) of the printable ASCII range.
+
+var helveticaWidths = [95]uint16{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBoldWidths = [95]uint16{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
+
+var timesWidths = [95]uint16{
+	250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444,
+	921, 722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722,
+	556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611, 333, 278, 333, 469, 500,
+	333, 444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500,
+	500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444, 480, 200, 480, 541,
+}
+
+var timesBoldWidths = [95]uint16{
+	250, 333, 555, 500, 500, 1000, 833, 278, 333, 333, 500, 570, 250, 333, 250, 278,
+	500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 333, 333, 570, 570, 570, 500,
+	930, 722, 667, 722, 722, 667, 611, 778, 778, 389, 500, 778, 667, 944, 722, 778,
+	611, 778, 722, 556, 667, 722, 722, 1000, 722, 722, 667, 333, 278, 333, 581, 500,
+	333, 500, 556, 444, 556, 444, 333, 500, 556, 278, 333, 556, 278, 833, 556, 500,
+	556, 556, 444, 389, 333, 556, 500, 722, 500, 500, 444, 394, 220, 394, 520,
+}
+
+// face selects the weight or style within a font family.
+type face int
+
+const (
+	faceRegular face = iota
+	faceBold
+	faceItalic
+)
+
+// font is one PDF base font.
+type font struct {
+	name   string // PostScript name
+	widths *[95]uint16
+	fixed  bool // Courier: every glyph is 600 units wide
+}
+
+// family holds the faces of a font family together with the font names
+// used for DOCX output.
+type family struct {
+	faces    [3]font
+	docxName string
+}
+
+// Italic faces reuse the upright widths; the difference is small enough
+// for line wrapping.
+var families = map[string]family{
+	FamilyHelvetica: {
+		faces: [3]font{
+			{name: "Helvetica", widths: &helveticaWidths},
+			{name: "Helvetica-Bold", widths: &helveticaBoldWidths},
+			{name: "Helvetica-Oblique", widths: &helveticaWidths},
+		},
+		docxName: "Arial",
+	},
+	FamilyTimes: {
+		faces: [3]font{
+			{name: "Times-Roman", widths: &timesWidths},
+			{name: "Times-Bold", widths: &timesBoldWidths},
+			{name: "Times-Italic", widths: &timesWidths},
+		},
+		docxName: "Times New Roman",
+	},
+	FamilyCourier: {
+		faces: [3]font{
+			{name: "Courier", fixed: true},
+			{name: "Courier-Bold", fixed: true},
+			{name: "Courier-Oblique", fixed: true},
+		},
+		docxName: "Courier New",
+	},
+}
+
+// latinBase maps Latin-1 letters (0xC0-0xFF) to the ASCII letter whose width
+// they share.
+const latinBase = "AAAAAAACEEEEIIIIDNOOOOOxOUUUUYPsaaaaaaaceeeeiiiidnooooo-ouuuuypy"
+
+// glyphWidth returns the width of a WinAnsi byte in 1/1000 em.
+func (f font) glyphWidth(b byte) float64 {
+	if f.fixed {
+		return 600
+	}
+	switch {
+	case b >= 32 && b <= 126:
+		return float64(f.widths[b-32])
+	case b >= 0xC0:
+		return float64(f.widths[latinBase[b-0xC0]-32])
+	case b == 0x85 || b == 0x97 || b == 0x89:
+		return 1000 // ellipsis, em dash, per mille
+	case b == 0x95:
+		return 350 // bullet
+	case b == 0x96:
+		return float64(f.widths['_'-32]) // en dash
+	default:
+		return float64(f.widths['n'-32])
+	}
+}
+
+// width returns the width of s in points at the given size.
+func (f font) width(s string, size float64) float64 {
+	w := 0.0
+	for _, b := range encodeWinAnsi(s) {
+		w += f.glyphWidth(b)
+	}
+	return w * size / 1000
+}
+
+// winAnsiSpecials are the characters of the 0x80-0x9F range of WinAnsiEncoding.
+var winAnsiSpecials = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8A, '‹': 0x8B, 'Œ': 0x8C, 'Ž': 0x8E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9A, '›': 0x9B, 'œ': 0x9C, 'ž': 0x9E, 'Ÿ': 0x9F,
+}
+
+// encodeWinAnsi converts s to WinAnsiEncoding, the encoding of the base
+// fonts. Characters outside it become '?'.
+func encodeWinAnsi(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r < 32:
+			// control characters are dropped
+		case r < 0x7F || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			if b, ok := winAnsiSpecials[r]; ok {
+				out = append(out, b)
+			} else {
+				out = append(out, '?')
+			}
+		}
+	}
+	return out
+}
+
+// wrapText breaks s into lines no wider than width. Explicit newlines are
+// kept; words longer than a line are split.
+func wrapText(s string, f font, size, width float64) []string {
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+		line := ""
+		for _, w := range words {
+			for f.width(w, size) > width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				head := splitToWidth(w, f, size, width)
+				lines = append(lines, head)
+				w = w[len(head):]
+			}
+			switch {
+			case w == "":
+			case line == "":
+				line = w
+			case f.width(line+" "+w, size) <= width:
+				line += " " + w
+			default:
+				lines = append(lines, line)
+				line = w
+			}
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitToWidth returns the longest prefix of w that fits width, at least
+// one character.
+func splitToWidth(w string, f font, size, width float64) string {
+	end := 0
+	for i, r := range w {
+		next := i + len(string(r))
+		if end > 0 && f.width(w[:next], size) > width {
+			break
+		}
+		end = next
+	}
+	return w[:end]
+}
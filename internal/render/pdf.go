@@ -0,0 +1,222 @@
+package render
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A4 in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// pdfImage is a JPEG embedded with DCTDecode.
+type pdfImage struct {
+	data          []byte
+	width, height int
+	gray          bool
+}
+
+// canvas is the content stream of one page. Coordinates passed to its
+// methods are measured from the top left corner.
+type canvas struct {
+	buf bytes.Buffer
+	pdf *pdfFile
+}
+
+// pdfFile collects pages, fonts and images and writes a PDF 1.4 file.
+type pdfFile struct {
+	pages  []*canvas
+	fonts  map[string]string // PostScript name -> resource name
+	images map[string]pdfImage
+	title  string
+}
+
+func newPDF(title string) *pdfFile {
+	return &pdfFile{fonts: map[string]string{}, images: map[string]pdfImage{}, title: title}
+}
+
+func (p *pdfFile) addPage() *canvas {
+	c := &canvas{pdf: p}
+	p.pages = append(p.pages, c)
+	return c
+}
+
+// addImage registers a JPEG under a resource name.
+func (p *pdfFile) addImage(name string, img pdfImage) {
+	p.images[name] = img
+}
+
+func (p *pdfFile) fontResource(f font) string {
+	if r, ok := p.fonts[f.name]; ok {
+		return r
+	}
+	r := fmt.Sprintf("F%d", len(p.fonts)+1)
+	p.fonts[f.name] = r
+	return r
+}
+
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func (c *canvas) setFill(col color) {
+	fmt.Fprintf(&c.buf, "%s %s %s rg\n", num(col.r), num(col.g), num(col.b))
+}
+
+func (c *canvas) setStroke(col color) {
+	fmt.Fprintf(&c.buf, "%s %s %s RG\n", num(col.r), num(col.g), num(col.b))
+}
+
+// text draws s with its baseline at y.
+func (c *canvas) text(x, y float64, f font, size float64, col color, s string) {
+	if s == "" {
+		return
+	}
+	c.setFill(col)
+	fmt.Fprintf(&c.buf, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		c.pdf.fontResource(f), num(size), num(x), num(pageHeight-y), escapePDF(encodeWinAnsi(s)))
+}
+
+// rect fills a rectangle whose top left corner is (x, y).
+func (c *canvas) rect(x, y, w, h float64, col color) {
+	c.setFill(col)
+	fmt.Fprintf(&c.buf, "%s %s %s %s re f\n", num(x), num(pageHeight-y-h), num(w), num(h))
+}
+
+func (c *canvas) line(x1, y1, x2, y2, width float64, col color) {
+	c.setStroke(col)
+	fmt.Fprintf(&c.buf, "%s w %s %s m %s %s l S\n",
+		num(width), num(x1), num(pageHeight-y1), num(x2), num(pageHeight-y2))
+}
+
+// image draws a registered image into the box at (x, y), clipped to a
+// circle when round is set.
+func (c *canvas) image(name string, x, y, w, h float64, round bool) {
+	bottom := pageHeight - y - h
+	c.buf.WriteString("q\n")
+	if round {
+		// Four Bézier arcs approximate the ellipse inscribed in the box
+		const k = 0.5523
+		cx, cy, rx, ry := x+w/2, bottom+h/2, w/2, h/2
+		fmt.Fprintf(&c.buf, "%s %s m\n", num(cx+rx), num(cy))
+		fmt.Fprintf(&c.buf, "%s %s %s %s %s %s c\n", num(cx+rx), num(cy+k*ry), num(cx+k*rx), num(cy+ry), num(cx), num(cy+ry))
+		fmt.Fprintf(&c.buf, "%s %s %s %s %s %s c\n", num(cx-k*rx), num(cy+ry), num(cx-rx), num(cy+k*ry), num(cx-rx), num(cy))
+		fmt.Fprintf(&c.buf, "%s %s %s %s %s %s c\n", num(cx-rx), num(cy-k*ry), num(cx-k*rx), num(cy-ry), num(cx), num(cy-ry))
+		fmt.Fprintf(&c.buf, "%s %s %s %s %s %s c\n", num(cx+k*rx), num(cy-ry), num(cx+rx), num(cy-k*ry), num(cx+rx), num(cy))
+		c.buf.WriteString("W n\n")
+	}
+	fmt.Fprintf(&c.buf, "%s 0 0 %s %s %s cm /%s Do\nQ\n", num(w), num(h), num(x), num(bottom), name)
+}
+
+// escapePDF escapes a byte string for a PDF literal string. Bytes outside
+// printable ASCII are written as octal escapes.
+func escapePDF(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c < 32 || c > 126:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// bytes serializes the file.
+func (p *pdfFile) bytes() ([]byte, error) {
+	var objects [][]byte
+	add := func(body []byte) int {
+		objects = append(objects, body)
+		return len(objects)
+	}
+	// Catalog and page tree come first so their numbers are known
+	catalog := add(nil)
+	pagesObj := add(nil)
+
+	var fontNames []string
+	for name := range p.fonts {
+		fontNames = append(fontNames, name)
+	}
+	sort.Strings(fontNames)
+	var fontRes strings.Builder
+	for _, name := range fontNames {
+		id := add([]byte(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name)))
+		fmt.Fprintf(&fontRes, "/%s %d 0 R ", p.fonts[name], id)
+	}
+
+	var imageNames []string
+	for name := range p.images {
+		imageNames = append(imageNames, name)
+	}
+	sort.Strings(imageNames)
+	var imageRes strings.Builder
+	for _, name := range imageNames {
+		img := p.images[name]
+		space := "DeviceRGB"
+		if img.gray {
+			space = "DeviceGray"
+		}
+		var obj bytes.Buffer
+		fmt.Fprintf(&obj, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n",
+			img.width, img.height, space, len(img.data))
+		obj.Write(img.data)
+		obj.WriteString("\nendstream")
+		fmt.Fprintf(&imageRes, "/%s %d 0 R ", name, add(obj.Bytes()))
+	}
+
+	resources := fmt.Sprintf("<< /Font << %s>> /XObject << %s>> >>", fontRes.String(), imageRes.String())
+	var kids []string
+	for _, page := range p.pages {
+		var z bytes.Buffer
+		zw := zlib.NewWriter(&z)
+		if _, err := zw.Write(page.buf.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		var stream bytes.Buffer
+		fmt.Fprintf(&stream, "<< /Length %d /Filter /FlateDecode >>\nstream\n", z.Len())
+		stream.Write(z.Bytes())
+		stream.WriteString("\nendstream")
+		content := add(stream.Bytes())
+		pageObj := add([]byte(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			pagesObj, num(pageWidth), num(pageHeight), resources, content)))
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageObj))
+	}
+	objects[catalog-1] = []byte(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesObj))
+	objects[pagesObj-1] = []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	info := add([]byte(fmt.Sprintf("<< /Title (%s) /Producer (RAAS) >>", escapePDF(encodeWinAnsi(p.title)))))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, body := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n", i+1)
+		out.Write(body)
+		out.WriteString("\nendobj\n")
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(objects)+1, catalog, info, xref)
+	return out.Bytes(), nil
+}
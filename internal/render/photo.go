@@ -0,0 +1,41 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	imgcolor "image/color"
+	"image/jpeg"
+
+	_ "image/gif"
+	_ "image/png"
+)
+
+// preparePhoto returns the photo as a baseline JPEG that PDF readers can
+// decode directly. Other formats, and CMYK JPEGs, are re-encoded.
+func preparePhoto(data []byte) (pdfImage, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return pdfImage{}, err
+	}
+	if format == "jpeg" {
+		switch cfg.ColorModel {
+		case imgcolor.GrayModel:
+			return pdfImage{data: data, width: cfg.Width, height: cfg.Height, gray: true}, nil
+		case imgcolor.YCbCrModel:
+			return pdfImage{data: data, width: cfg.Width, height: cfg.Height}, nil
+		}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return pdfImage{}, err
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return pdfImage{}, err
+	}
+	// The encoder keeps grayscale images single-channel
+	_, gray := img.(*image.Gray)
+	b := img.Bounds()
+	return pdfImage{data: buf.Bytes(), width: b.Dx(), height: b.Dy(), gray: gray}, nil
+}
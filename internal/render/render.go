@@ -0,0 +1,71 @@
+package render
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Output formats
+const (
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+)
+
+// ErrUnsupportedFormat is returned for output formats other than PDF and DOCX.
+var ErrUnsupportedFormat = errors.New("unsupported output format")
+
+// File is a rendered document.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+var contentTypes = map[string]string{
+	FormatPDF:  "application/pdf",
+	FormatDOCX: "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+}
+
+// Render lays out doc with the template in the given output format. name is
+// the file name without extension.
+func Render(doc Document, tpl Template, format, name string) (File, error) {
+	var (
+		data []byte
+		err  error
+	)
+	switch format {
+	case FormatPDF:
+		data, err = renderPDF(doc, tpl)
+	case FormatDOCX:
+		data, err = renderDOCX(doc, tpl)
+	default:
+		return File{}, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return File{}, err
+	}
+	return File{Name: name + "." + format, ContentType: contentTypes[format], Data: data}, nil
+}
+
+// Zip bundles rendered files into one archive.
+func Zip(files ...File) ([]byte, error) {
+	parts := make([]zipPart, 0, len(files))
+	for _, f := range files {
+		parts = append(parts, zipPart{f.Name, f.Data})
+	}
+	return zipParts(parts)
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^\pL\pN._-]+`)
+
+// FileName builds a file name such as "Jane_Doe_CV" from its parts.
+func FileName(parts ...string) string {
+	var clean []string
+	for _, p := range parts {
+		if p = strings.Trim(unsafeFileChars.ReplaceAllString(p, "_"), "_"); p != "" {
+			clean = append(clean, p)
+		}
+	}
+	return strings.Join(clean, "_")
+}
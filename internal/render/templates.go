@@ -0,0 +1,172 @@
+package render
+
+import "sort"
+
+// Document kinds
+const (
+	KindCV          = "cv"
+	KindCoverLetter = "cover_letter"
+)
+
+// Font families
+const (
+	FamilyHelvetica = "helvetica"
+	FamilyTimes     = "times"
+	FamilyCourier   = "courier"
+)
+
+// Default templates, used when a document has no valid format stored
+const (
+	DefaultCVTemplate = "classic"
+	DefaultCLTemplate = "formal"
+)
+
+// color is an RGB color with components in [0, 1].
+type color struct{ r, g, b float64 }
+
+var (
+	black     = color{0, 0, 0}
+	white     = color{1, 1, 1}
+	darkGray  = color{0.2, 0.2, 0.2}
+	midGray   = color{0.42, 0.42, 0.42}
+	lightGray = color{0.85, 0.85, 0.85}
+)
+
+// Template describes the layout of one CV or cover letter format. The same
+// template drives the PDF and the DOCX output.
+type Template struct {
+	Name   string
+	Kind   string
+	Family string
+
+	Accent color // section titles, rules and header band
+	Text   color
+	Muted  color // subtitles, periods and contact lines
+	Tint   color // sidebar background
+
+	BaseSize float64 // body font size in points
+	Leading  float64 // line height as a multiple of the font size
+	Margin   float64 // page margin in points
+
+	HeaderAlign string // "left", "center" or "right"
+	HeaderBand  bool   // header text drawn white on an accent band
+	Sidebar     bool   // short list sections go to a left sidebar (PDF only)
+	SectionRule bool   // rule under section titles
+	UpperTitles bool   // section titles in capitals
+	Photo       bool   // show the profile photo when there is one
+	PhotoRound  bool
+	Indent      bool // first-line indent of letter paragraphs
+}
+
+var cvTemplates = map[string]Template{
+	"minimalist": {
+		Family: FamilyHelvetica, Accent: darkGray, Text: darkGray, Muted: midGray,
+		BaseSize: 9.5, Leading: 1.45, Margin: 60,
+		HeaderAlign: "left", UpperTitles: true, Photo: true, PhotoRound: true,
+	},
+	"classic": {
+		Family: FamilyTimes, Accent: black, Text: black, Muted: midGray,
+		BaseSize: 11, Leading: 1.3, Margin: 54,
+		HeaderAlign: "center", SectionRule: true, UpperTitles: true, Photo: true,
+	},
+	"modern": {
+		Family: FamilyHelvetica, Accent: color{0.13, 0.36, 0.64}, Text: darkGray, Muted: midGray,
+		BaseSize: 10, Leading: 1.35, Margin: 48,
+		HeaderAlign: "left", HeaderBand: true, SectionRule: true, Photo: true, PhotoRound: true,
+	},
+	"elegant": {
+		Family: FamilyTimes, Accent: color{0.45, 0.12, 0.2}, Text: darkGray, Muted: midGray,
+		BaseSize: 10.5, Leading: 1.4, Margin: 60,
+		HeaderAlign: "center", SectionRule: true, Photo: true, PhotoRound: true,
+	},
+	"creative": {
+		Family: FamilyHelvetica, Accent: color{0, 0.5, 0.5}, Text: darkGray, Muted: midGray,
+		Tint:     color{0.9, 0.96, 0.96},
+		BaseSize: 9.5, Leading: 1.35, Margin: 40,
+		HeaderAlign: "left", Sidebar: true, UpperTitles: true, Photo: true, PhotoRound: true,
+	},
+}
+
+var clTemplates = map[string]Template{
+	"formal": {
+		Family: FamilyTimes, Accent: black, Text: black, Muted: darkGray,
+		BaseSize: 11.5, Leading: 1.35, Margin: 72,
+		HeaderAlign: "right",
+	},
+	"casual": {
+		Family: FamilyHelvetica, Accent: color{0.85, 0.4, 0.1}, Text: darkGray, Muted: midGray,
+		BaseSize: 10.5, Leading: 1.45, Margin: 60,
+		HeaderAlign: "left", Photo: true, PhotoRound: true,
+	},
+	"concise": {
+		Family: FamilyHelvetica, Accent: darkGray, Text: darkGray, Muted: midGray,
+		BaseSize: 10, Leading: 1.3, Margin: 50,
+		HeaderAlign: "left", SectionRule: true,
+	},
+	"narrative": {
+		Family: FamilyTimes, Accent: color{0.2, 0.25, 0.4}, Text: darkGray, Muted: midGray,
+		BaseSize: 11.5, Leading: 1.55, Margin: 72,
+		HeaderAlign: "center", Indent: true,
+	},
+	"technical": {
+		Family: FamilyCourier, Accent: color{0.16, 0.3, 0.42}, Text: darkGray, Muted: midGray,
+		BaseSize: 9.5, Leading: 1.4, Margin: 56,
+		HeaderAlign: "left", HeaderBand: true, SectionRule: true,
+	},
+}
+
+func templatesOf(kind string) map[string]Template {
+	if kind == KindCoverLetter {
+		return clTemplates
+	}
+	return cvTemplates
+}
+
+// Formats returns the template names of a document kind, as the set used to
+// validate the stored cv_format and cl_format.
+func Formats(kind string) map[string]bool {
+	names := make(map[string]bool)
+	for name := range templatesOf(kind) {
+		names[name] = true
+	}
+	return names
+}
+
+// TemplateNames returns the sorted template names of a document kind.
+func TemplateNames(kind string) []string {
+	names := make([]string, 0, len(templatesOf(kind)))
+	for name := range templatesOf(kind) {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupTemplate returns the named template of a document kind.
+func LookupTemplate(kind, name string) (Template, bool) {
+	t, ok := templatesOf(kind)[name]
+	t.Name, t.Kind = name, kind
+	return t, ok
+}
+
+// TemplateFor returns the named template, or the kind's default template
+// when the name is unknown.
+func TemplateFor(kind, name string) Template {
+	if t, ok := LookupTemplate(kind, name); ok {
+		return t
+	}
+	if kind == KindCoverLetter {
+		t, _ := LookupTemplate(kind, DefaultCLTemplate)
+		return t
+	}
+	t, _ := LookupTemplate(kind, DefaultCVTemplate)
+	return t
+}
+
+func (t Template) family() family {
+	return families[t.Family]
+}
+
+func (t Template) font(f face) font {
+	return t.family().faces[f]
+}
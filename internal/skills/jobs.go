@@ -0,0 +1,67 @@
+package skills
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TagPendingJobs stores the canonical skill IDs found in the title,
+// description and skills of jobs that have not been tagged yet.
+func TagPendingJobs(ctx context.Context, coll *mongo.Collection) (int, error) {
+	cursor, err := coll.Find(ctx,
+		bson.M{"skill_ids": bson.M{"$exists": false}},
+		options.Find().SetProjection(bson.M{"job_id": 1, "title": 1, "job_description": 1, "skills": 1}),
+	)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	taxonomy := Default()
+	var models []mongo.WriteModel
+	for cursor.Next(ctx) {
+		var job struct {
+			JobID          string `bson:"job_id"`
+			Title          string `bson:"title"`
+			JobDescription string `bson:"job_description"`
+			Skills         string `bson:"skills"`
+		}
+		if err := cursor.Decode(&job); err != nil {
+			continue
+		}
+		ids := taxonomy.Extract(job.Title + "\n" + job.JobDescription + "\n" + job.Skills)
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"job_id": job.JobID}).
+			SetUpdate(bson.M{"$set": bson.M{"skill_ids": ids}}))
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(models) == 0 {
+		return 0, nil
+	}
+
+	res, err := coll.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return int(res.ModifiedCount), nil
+}
+
+// ResetJobTags clears skill_ids so TagPendingJobs re-tags every job after
+// the taxonomy changed.
+func ResetJobTags(ctx context.Context, coll *mongo.Collection) error {
+	_, err := coll.UpdateMany(ctx,
+		bson.M{"skill_ids": bson.M{"$exists": true}},
+		bson.M{"$unset": bson.M{"skill_ids": ""}},
+	)
+	return err
+}
+
+// IndexSkillIDs is the index used to filter jobs by skill.
+func IndexSkillIDs() mongo.IndexModel {
+	return mongo.IndexModel{Keys: bson.D{{Key: "skill_ids", Value: 1}}}
+}
@@ -0,0 +1,390 @@
+package skills
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	_ "embed"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+//go:embed data/skills.csv
+var skillsCSV []byte
+
+// Skill is a canonical skill of the taxonomy.
+type Skill struct {
+	ID       string   `bson:"_id" json:"id"`
+	Name     string   `bson:"name" json:"name"`
+	Aliases  []string `bson:"aliases" json:"aliases"`
+	Category string   `bson:"category" json:"category"`
+	Related  []string `bson:"related" json:"related"` // skill IDs
+	// Disabled hides a bundled skill when set on a database override.
+	Disabled bool `bson:"disabled,omitempty" json:"disabled,omitempty"`
+}
+
+// Taxonomy resolves free-text skills to canonical skills.
+type Taxonomy struct {
+	skills   map[string]Skill
+	terms    map[string]string // normalized name or alias -> skill ID
+	exact    map[string]string // short term key -> spelling required in free text
+	maxWords int
+}
+
+var (
+	loadOnce sync.Once
+	current  atomic.Pointer[Taxonomy]
+)
+
+// Default returns the active taxonomy: the bundled skills, merged with the
+// database overrides once Reload has run.
+func Default() *Taxonomy {
+	loadOnce.Do(func() {
+		if current.Load() == nil {
+			current.Store(New(Bundled()))
+		}
+	})
+	return current.Load()
+}
+
+// Reload rebuilds the active taxonomy from the bundled skills and the
+// overrides stored in coll. Overrides replace bundled skills with the same ID.
+func Reload(ctx context.Context, coll *mongo.Collection) error {
+	cursor, err := coll.Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var overrides []Skill
+	if err := cursor.All(ctx, &overrides); err != nil {
+		return err
+	}
+
+	merged := make(map[string]Skill)
+	for _, s := range Bundled() {
+		merged[s.ID] = s
+	}
+	for _, s := range overrides {
+		if s.Disabled {
+			delete(merged, s.ID)
+			continue
+		}
+		merged[s.ID] = s
+	}
+	list := make([]Skill, 0, len(merged))
+	for _, s := range merged {
+		list = append(list, s)
+	}
+
+	current.Store(New(list))
+	return nil
+}
+
+// Bundled parses the skills shipped with the binary.
+func Bundled() []Skill {
+	var list []Skill
+	scanner := bufio.NewScanner(bytes.NewReader(skillsCSV))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cols := strings.Split(line, ",")
+		if len(cols) != 5 {
+			log.Printf("⚠️ skills: skipping malformed line %q", line)
+			continue
+		}
+		list = append(list, Skill{
+			ID:       cols[0],
+			Name:     cols[1],
+			Aliases:  splitList(cols[2]),
+			Category: cols[3],
+			Related:  splitList(cols[4]),
+		})
+	}
+	return list
+}
+
+func splitList(s string) []string {
+	out := []string{}
+	for _, v := range strings.Split(s, "|") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// New builds a taxonomy from a list of skills.
+func New(list []Skill) *Taxonomy {
+	t := &Taxonomy{skills: make(map[string]Skill, len(list)), terms: make(map[string]string), exact: make(map[string]string), maxWords: 1}
+	for _, s := range list {
+		t.skills[s.ID] = s
+	}
+	// Names win over aliases of other skills
+	for _, s := range list {
+		for _, alias := range s.Aliases {
+			t.addTerm(alias, s.ID)
+		}
+	}
+	for _, s := range list {
+		t.addTerm(s.Name, s.ID)
+	}
+	return t
+}
+
+func (t *Taxonomy) addTerm(term, id string) {
+	key := Normalize(term)
+	if key == "" {
+		return
+	}
+	t.terms[key] = id
+	if shortTerm(key) {
+		t.exact[key] = strings.TrimSpace(term)
+	}
+	if n := len(strings.Fields(key)); n > t.maxWords {
+		t.maxWords = n
+	}
+}
+
+// tokenSeparator splits text into words. Characters used inside skill names
+// such as "+", "#", "." and "-" are kept ("C++", "C#", "Node.js").
+func tokenSeparator(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\r', ',', ';', ':', '(', ')', '[', ']', '{', '}', '|', '/', '\\', '!', '?', '"', '\'', '`', '<', '>', '=':
+		return true
+	}
+	return false
+}
+
+// shortTerm reports whether a term key is a bare word of one or two letters
+// such as "go", "c" or "r", which are also ordinary words.
+func shortTerm(key string) bool {
+	if len(key) > 2 {
+		return false
+	}
+	for _, r := range key {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize lowercases text and splits it into words, dropping sentence
+// punctuation around them.
+func tokenize(text string) []string {
+	return splitWords(strings.ToLower(text))
+}
+
+// splitWords splits text into words as tokenize does, keeping their case.
+func splitWords(text string) []string {
+	fields := strings.FieldsFunc(text, tokenSeparator)
+	tokens := fields[:0]
+	for _, f := range fields {
+		f = strings.TrimRight(f, ".-*•")
+		f = strings.TrimLeft(f, "-*•")
+		if f != "" {
+			tokens = append(tokens, f)
+		}
+	}
+	return tokens
+}
+
+// Normalize turns a skill term into its lookup key: lowercase words
+// separated by single spaces.
+func Normalize(term string) string {
+	return strings.Join(tokenize(term), " ")
+}
+
+// Lookup resolves a name or alias to its canonical skill.
+func (t *Taxonomy) Lookup(term string) (Skill, bool) {
+	id, ok := t.terms[Normalize(term)]
+	if !ok {
+		return Skill{}, false
+	}
+	return t.skills[id], true
+}
+
+// Get returns the skill with the given ID.
+func (t *Taxonomy) Get(id string) (Skill, bool) {
+	s, ok := t.skills[id]
+	return s, ok
+}
+
+// All returns every skill ordered by ID.
+func (t *Taxonomy) All() []Skill {
+	list := make([]Skill, 0, len(t.skills))
+	for _, s := range t.skills {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// Canonicalize replaces known skills with their canonical names and removes
+// duplicates, keeping unknown skills as entered (trimmed).
+func (t *Taxonomy) Canonicalize(terms []string) []string {
+	out := []string{}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		term = strings.Join(strings.Fields(term), " ")
+		key := Normalize(term)
+		if key == "" {
+			continue
+		}
+		name := term
+		if id, ok := t.terms[key]; ok {
+			name = t.skills[id].Name
+			key = "id:" + id
+		}
+		if !seen[key] {
+			seen[key] = true
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// IDs returns the canonical IDs of the known skills among terms.
+func (t *Taxonomy) IDs(terms []string) []string {
+	ids := []string{}
+	seen := map[string]bool{}
+	for _, term := range terms {
+		if id, ok := t.terms[Normalize(term)]; ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Extract returns the IDs of skills mentioned in free text.
+func (t *Taxonomy) Extract(text string) []string {
+	return t.NewDocument(text).IDs()
+}
+
+// Suggest returns skills whose name or alias starts with (or, failing that,
+// contains) query, best matches first.
+func (t *Taxonomy) Suggest(query string, limit int) []Skill {
+	q := Normalize(query)
+	if q == "" || limit <= 0 {
+		return []Skill{}
+	}
+
+	type hit struct {
+		skill Skill
+		rank  int
+	}
+	best := map[string]hit{}
+	for term, id := range t.terms {
+		rank := -1
+		switch {
+		case term == q:
+			rank = 0
+		case strings.HasPrefix(term, q):
+			rank = 1
+		case strings.Contains(term, " "+q):
+			rank = 2
+		case strings.Contains(term, q):
+			rank = 3
+		}
+		if rank < 0 {
+			continue
+		}
+		if h, ok := best[id]; !ok || rank < h.rank {
+			best[id] = hit{skill: t.skills[id], rank: rank}
+		}
+	}
+
+	hits := make([]hit, 0, len(best))
+	for _, h := range best {
+		hits = append(hits, h)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].rank != hits[j].rank {
+			return hits[i].rank < hits[j].rank
+		}
+		if len(hits[i].skill.Name) != len(hits[j].skill.Name) {
+			return len(hits[i].skill.Name) < len(hits[j].skill.Name)
+		}
+		return hits[i].skill.Name < hits[j].skill.Name
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	out := make([]Skill, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, h.skill)
+	}
+	return out
+}
+
+// Document is text prepared for whole-word skill matching, so "Go" matches
+// "Go" and "Golang" but not "Google". Short terms such as "Go", "C" and "R"
+// only match as spelled in the taxonomy, so "go" and "c" do not.
+type Document struct {
+	taxonomy *Taxonomy
+	text     string // tokens joined by single spaces, padded
+	ids      map[string]bool
+}
+
+// NewDocument finds the skills in every word sequence of text up to the
+// longest skill term.
+func (t *Taxonomy) NewDocument(text string) *Document {
+	words := splitWords(text)
+	tokens := make([]string, len(words))
+	for i, w := range words {
+		tokens[i] = strings.ToLower(w)
+	}
+	d := &Document{taxonomy: t, text: " " + strings.Join(tokens, " ") + " ", ids: map[string]bool{}}
+	for i := range tokens {
+		for n := 1; n <= t.maxWords && i+n <= len(tokens); n++ {
+			key := strings.Join(tokens[i:i+n], " ")
+			id, ok := t.terms[key]
+			if !ok {
+				continue
+			}
+			if spelling, short := t.exact[key]; short && words[i] != spelling {
+				continue
+			}
+			d.ids[id] = true
+		}
+	}
+	return d
+}
+
+// Has reports whether the document mentions term: by canonical skill when
+// term is in the taxonomy, otherwise as a whole-word phrase.
+func (d *Document) Has(term string) bool {
+	key := Normalize(term)
+	if key == "" {
+		return false
+	}
+	if id, ok := d.taxonomy.terms[key]; ok {
+		return d.ids[id]
+	}
+	return strings.Contains(d.text, " "+key+" ")
+}
+
+// HasID reports whether the document mentions the skill with the given ID.
+func (d *Document) HasID(id string) bool {
+	return d.ids[id]
+}
+
+// IDs returns the IDs of the skills mentioned in the document, sorted.
+func (d *Document) IDs() []string {
+	ids := make([]string, 0, len(d.ids))
+	for id := range d.ids {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
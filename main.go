@@ -5,6 +5,7 @@ import (
     "RAAS/app/workers"
     "RAAS/core/config"
     "RAAS/internal/models"
+    "RAAS/internal/skills"
     "context"
     "fmt"
     "log"
@@ -27,7 +28,16 @@ func main() {
     }
 
     // Init MongoDB
-    client, _ := models.InitDB(config.Cfg)
+    client, db := models.InitDB(config.Cfg)
+    if err := models.RunMigrations(db); err != nil {
+        log.Fatalf("Error running migrations: %v", err)
+    }
+    if err := skills.Reload(context.Background(), db.Collection(models.CollectionSkillTaxonomy)); err != nil {
+        log.Printf("⚠️ Using bundled skill taxonomy: %v", err)
+    }
+    if config.Cfg.Cloud.MLStub {
+        log.Println("⚠️ ML_STUB is set: generation and resume extraction use the built-in stub")
+    }
 
     // Setup Gin router
     r := gin.Default()
@@ -45,9 +55,21 @@ func main() {
 	deletionCancel := workers.StartPurgeWorker(client.Database(config.Cfg.Cloud.MongoDBName), 24*time.Hour)
 	defer deletionCancel()
 
+	expiryCancel := workers.StartJobExpiryWorker(db, 1*time.Hour)
+	defer expiryCancel()
+
     // notifier := workers.StartTestNotifier(client.Database(config.Cfg.Cloud.MongoDBName))
     // defer notifier.Stop()
 
+    alertNotifier := workers.StartJobAlertNotifier(db)
+    defer alertNotifier.Stop()
+
+    interviewReminders := workers.StartInterviewReminderWorker(db)
+    defer interviewReminders.Stop()
+
+    generationCancel := workers.StartGenerationWorker(db, config.Cfg.Project.GenerationWorkers)
+    defer generationCancel()
+
 
 
     // Start HTTP server
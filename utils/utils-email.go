@@ -4,6 +4,8 @@ import (
 
     "RAAS/core/config"
 
+    "io"
+
     "gopkg.in/mail.v2"
     "github.com/google/uuid"
      // make sure this is correctly imported based on your structure
@@ -31,6 +33,38 @@ func SendEmail(cfg EmailConfig, to, subject, body string) error {
     return d.DialAndSend(m)
 }
 
+// EmailAttachment is a file attached to an outgoing email.
+type EmailAttachment struct {
+    Name        string
+    ContentType string
+    Data        []byte
+}
+
+// SendEmailWithAttachments sends an HTML email with the given files attached.
+func SendEmailWithAttachments(cfg EmailConfig, to, subject, body string, attachments ...EmailAttachment) error {
+    m := mail.NewMessage()
+    m.SetHeader("From", cfg.From)
+    m.SetHeader("To", to)
+    m.SetHeader("Subject", subject)
+    m.SetBody("text/html", body)
+
+    for _, a := range attachments {
+        data := a.Data
+        m.Attach(a.Name,
+            mail.SetCopyFunc(func(w io.Writer) error {
+                _, err := w.Write(data)
+                return err
+            }),
+            mail.SetHeader(map[string][]string{"Content-Type": {a.ContentType}}),
+        )
+    }
+
+    d := mail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
+    d.TLSConfig = nil
+
+    return d.DialAndSend(m)
+}
+
 func GenerateVerificationToken() string {
     return uuid.New().String()
 }
@@ -0,0 +1,99 @@
+package utils
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// ICSEvent is a single VEVENT in an iCalendar document.
+type ICSEvent struct {
+    UID         string
+    Start       time.Time
+    End         time.Time
+    Summary     string
+    Description string
+    Location    string
+    URL         string
+    Updated     time.Time
+    Cancelled   bool
+}
+
+// BuildICS renders events as an RFC 5545 calendar. Times are written in UTC.
+func BuildICS(calendarName string, events []ICSEvent) string {
+    var b strings.Builder
+    line := func(s string) { b.WriteString(foldICSLine(s) + "\r\n") }
+
+    line("BEGIN:VCALENDAR")
+    line("VERSION:2.0")
+    line("PRODID:-//RAAS//Interview Calendar//EN")
+    line("CALSCALE:GREGORIAN")
+    line("METHOD:PUBLISH")
+    line("X-WR-CALNAME:" + escapeICSText(calendarName))
+
+    now := time.Now()
+    for _, e := range events {
+        stamp := e.Updated
+        if stamp.IsZero() {
+            stamp = now
+        }
+        line("BEGIN:VEVENT")
+        line("UID:" + e.UID)
+        line("DTSTAMP:" + icsTime(stamp))
+        line("DTSTART:" + icsTime(e.Start))
+        line("DTEND:" + icsTime(e.End))
+        line("SUMMARY:" + escapeICSText(e.Summary))
+        if e.Description != "" {
+            line("DESCRIPTION:" + escapeICSText(e.Description))
+        }
+        if e.Location != "" {
+            line("LOCATION:" + escapeICSText(e.Location))
+        }
+        if e.URL != "" {
+            line("URL:" + e.URL)
+        }
+        if e.Cancelled {
+            line("STATUS:CANCELLED")
+        } else {
+            line("STATUS:CONFIRMED")
+        }
+        line("END:VEVENT")
+    }
+    line("END:VCALENDAR")
+    return b.String()
+}
+
+func icsTime(t time.Time) string {
+    return t.UTC().Format("20060102T150405Z")
+}
+
+func escapeICSText(s string) string {
+    r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+    return r.Replace(s)
+}
+
+// foldICSLine splits content lines longer than 75 octets, as RFC 5545 requires,
+// without breaking UTF-8 sequences.
+func foldICSLine(s string) string {
+    const max = 75
+    if len(s) <= max {
+        return s
+    }
+    var b strings.Builder
+    width := 0
+    for _, r := range s {
+        n := len(string(r))
+        if width+n > max {
+            b.WriteString("\r\n ")
+            width = 1
+        }
+        b.WriteRune(r)
+        width += n
+    }
+    return b.String()
+}
+
+// ICSFilename returns a safe attachment filename for an event.
+func ICSFilename(prefix string, t time.Time) string {
+    return fmt.Sprintf("%s-%s.ics", prefix, t.UTC().Format("20060102-1504"))
+}